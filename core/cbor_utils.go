@@ -0,0 +1,164 @@
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+const (
+	cborMajorTypeArray = byte(4 << 5)
+	cborMajorTypeMap   = byte(5 << 5)
+
+	cborEncodedDataTag         = 24
+	cborSetTag                 = 258
+	cborAuxiliaryDataAlonzoTag = 259
+)
+
+var cborSetTagPrefix = []byte{0xd9, 0x01, 0x02}
+
+type cborKeyValue struct {
+	Key   any
+	Value any
+}
+
+// cborOrderedMap is cbor map which keeps the order of its keys during encoding
+type cborOrderedMap []cborKeyValue
+
+func (m cborOrderedMap) MarshalCBOR() ([]byte, error) {
+	result := getCborHeader(cborMajorTypeMap, uint64(len(m)))
+
+	for _, kv := range m {
+		keyBytes, err := cbor.Marshal(kv.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		valueBytes, err := cbor.Marshal(kv.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(append(result, keyBytes...), valueBytes...)
+	}
+
+	return result, nil
+}
+
+// cborSet is cbor array which is tagged with set tag (258) if isTagged is true (conway era)
+type cborSet[T any] struct {
+	items    []T
+	isTagged bool
+}
+
+func newCborSet[T any](items []T, isTagged bool) cborSet[T] {
+	if items == nil {
+		items = []T{}
+	}
+
+	return cborSet[T]{
+		items:    items,
+		isTagged: isTagged,
+	}
+}
+
+func (s cborSet[T]) MarshalCBOR() ([]byte, error) {
+	if s.isTagged {
+		return cbor.Marshal(cbor.Tag{
+			Number:  cborSetTag,
+			Content: s.items,
+		})
+	}
+
+	return cbor.Marshal(s.items)
+}
+
+// decodeCborSet decodes cbor array which can be tagged with set tag
+// empty data is decoded as empty set
+func decodeCborSet(data []byte) (items []cbor.RawMessage, isTagged bool, err error) {
+	if len(data) == 0 {
+		return nil, false, nil
+	}
+
+	if isTagged = bytes.HasPrefix(data, cborSetTagPrefix); isTagged {
+		data = data[len(cborSetTagPrefix):]
+	}
+
+	if err := cbor.Unmarshal(data, &items); err != nil {
+		return nil, false, err
+	}
+
+	return items, isTagged, nil
+}
+
+// decodeCborMapEntries decodes cbor map into raw key value pairs
+// it is used for maps which keys can not be go map keys (arrays, maps)
+func decodeCborMapEntries(data []byte) ([][2]cbor.RawMessage, error) {
+	if len(data) == 0 || data[0]&0xe0 != cborMajorTypeMap {
+		return nil, errors.New("cbor map expected")
+	}
+
+	var (
+		length       uint64
+		isIndefinite = data[0] == cborMajorTypeMap|31
+		headerSize   = 1
+	)
+
+	switch info := data[0] & 0x1f; {
+	case isIndefinite:
+	case info < 24:
+		length = uint64(info)
+	case info <= 27 && len(data) > 1<<(info-24):
+		headerSize += 1 << (info - 24)
+
+		for _, x := range data[1:headerSize] {
+			length = length<<8 | uint64(x)
+		}
+	default:
+		return nil, errors.New("invalid cbor map header")
+	}
+
+	decoder := cbor.NewDecoder(bytes.NewReader(data[headerSize:]))
+	result := [][2]cbor.RawMessage{}
+
+	for i := uint64(0); isIndefinite || i < length; i++ {
+		if isIndefinite {
+			if position := headerSize + decoder.NumBytesRead(); position >= len(data) {
+				return nil, errors.New("invalid cbor map: break expected")
+			} else if data[position] == 0xff {
+				break
+			}
+		}
+
+		var entry [2]cbor.RawMessage
+
+		if err := decoder.Decode(&entry[0]); err != nil {
+			return nil, err
+		}
+
+		if err := decoder.Decode(&entry[1]); err != nil {
+			return nil, err
+		}
+
+		result = append(result, entry)
+	}
+
+	return result, nil
+}
+
+func getCborHeader(majorType byte, length uint64) []byte {
+	switch {
+	case length < 24:
+		return []byte{majorType | byte(length)}
+	case length <= 0xFF:
+		return []byte{majorType | 24, byte(length)}
+	case length <= 0xFFFF:
+		return binary.BigEndian.AppendUint16([]byte{majorType | 25}, uint16(length))
+	case length <= 0xFFFFFFFF:
+		return binary.BigEndian.AppendUint32([]byte{majorType | 26}, uint32(length))
+	default:
+		return binary.BigEndian.AppendUint64([]byte{majorType | 27}, length)
+	}
+}
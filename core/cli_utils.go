@@ -20,12 +20,12 @@ type AddressInfo struct {
 }
 
 type CliUtils struct {
-	cardanoCliBinary string
+	cli cardanoCli
 }
 
-func NewCliUtils(cardanoCliBinary string) CliUtils {
+func NewCliUtils(cardanoCliBinary string, options ...CardanoCliOption) CliUtils {
 	return CliUtils{
-		cardanoCliBinary: cardanoCliBinary,
+		cli: newCardanoCli(cardanoCliBinary, options...),
 	}
 }
 
@@ -69,7 +69,7 @@ func (cu CliUtils) GetPolicyScriptAddress(
 		args = append(args, "--stake-script-file", policyScriptStakeFilePath)
 	}
 
-	response, err := runCommand(cu.cardanoCliBinary, append(args, getTestNetMagicArgs(testNetMagic)...))
+	response, err := cu.cli.run(append(args, getTestNetMagicArgs(testNetMagic)...))
 	if err != nil {
 		return "", err
 	}
@@ -96,7 +96,7 @@ func (cu CliUtils) GetPolicyID(policyScript any) (string, error) {
 		return "", err
 	}
 
-	response, err := runCommand(cu.cardanoCliBinary, []string{
+	response, err := cu.cli.run([]string{
 		"transaction", "policyid", "--script-file", policyScriptFilePath,
 	})
 	if err != nil {
@@ -110,7 +110,7 @@ func (cu CliUtils) GetPolicyID(policyScript any) (string, error) {
 func (cu CliUtils) GetAddressInfo(address string) (AddressInfo, error) {
 	var ai AddressInfo
 
-	res, err := runCommand(cu.cardanoCliBinary, []string{
+	res, err := cu.cli.run([]string{
 		"address", "info", "--address", address,
 	})
 	if err != nil {
@@ -135,7 +135,7 @@ func (cu CliUtils) GetWalletAddress(
 
 	// enterprise address
 	if len(stakeVerificationKey) == 0 {
-		addr, err = runCommand(cu.cardanoCliBinary, append([]string{
+		addr, err = cu.cli.run(append([]string{
 			"address", "build",
 			"--payment-verification-key", bech32String,
 		}, getTestNetMagicArgs(testNetMagic)...))
@@ -148,7 +148,7 @@ func (cu CliUtils) GetWalletAddress(
 		return "", "", err
 	}
 
-	addr, err = runCommand(cu.cardanoCliBinary, append([]string{
+	addr, err = cu.cli.run(append([]string{
 		"address", "build",
 		"--payment-verification-key", bech32String,
 		"--stake-verification-key", bech32StakeString,
@@ -157,7 +157,7 @@ func (cu CliUtils) GetWalletAddress(
 		return "", "", err
 	}
 
-	stakeAddr, err = runCommand(cu.cardanoCliBinary, append([]string{
+	stakeAddr, err = cu.cli.run(append([]string{
 		"stake-address", "build",
 		"--stake-verification-key", bech32StakeString,
 	}, getTestNetMagicArgs(testNetMagic)...))
@@ -171,7 +171,7 @@ func (cu CliUtils) GetKeyHash(key []byte) (string, error) {
 		return "", err
 	}
 
-	resultKeyHash, err := runCommand(cu.cardanoCliBinary, []string{
+	resultKeyHash, err := cu.cli.run([]string{
 		"address", "key-hash",
 		"--payment-verification-key", bech32String,
 	})
@@ -210,7 +210,7 @@ func (cu CliUtils) getTxHash(txRaw []byte, baseDirectory string) (string, error)
 		"transaction", "txid",
 		"--tx-body-file", txFilePath}
 
-	res, err := runCommand(cu.cardanoCliBinary, args)
+	res, err := cu.cli.run(args)
 	if err != nil {
 		return "", err
 	}
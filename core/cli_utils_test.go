@@ -1,9 +1,11 @@
 package core
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
+	"github.com/igorcrevar/go-cardano-tx/core/clitest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -49,3 +51,30 @@ func TestIsValidCardanoAddress(t *testing.T) {
 		}
 	}
 }
+
+func TestCliUtils_FakeCli(t *testing.T) {
+	t.Parallel()
+
+	const policyID = "4aaad0f0626a8ce7b097497e542055b6520842ade881f980e002ae66"
+
+	fakeCli := clitest.NewFakeCardanoCli().
+		Handle(clitest.Response{Stdout: policyID + "\n"}, "transaction", "policyid").
+		Handle(clitest.Response{Err: errors.New("invalid address")}, "address", "info")
+
+	cliUtils := NewCliUtils("cardano-cli-fake", WithCommandRunner(fakeCli))
+
+	result, err := cliUtils.GetPolicyID(NewPolicyScript([]string{
+		"d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21",
+	}, 1))
+	require.NoError(t, err)
+	assert.Equal(t, policyID, result)
+
+	_, err = cliUtils.GetAddressInfo("addr_test1invalid")
+	require.ErrorIs(t, err, ErrInvalidAddressData)
+
+	calls := fakeCli.Calls()
+	require.Len(t, calls, 2)
+
+	assert.True(t, calls[0].HasFlag("--script-file"))
+	assert.Equal(t, "addr_test1invalid", calls[1].Flag("--address"))
+}
@@ -0,0 +1,172 @@
+// Package clitest provides fake cardano-cli which can be injected into core cli types
+// (core.WithCommandRunner) so cli integration paths can be tested without cardano-cli and node.
+package clitest
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+const filePermission = 0750
+
+// Call is recorded invocation of the fake cardano-cli
+type Call struct {
+	Binary string
+	Args   []string
+	Env    []string
+}
+
+// Flag returns value of the flag (for example --out-file) or empty string if flag does not exist
+func (c Call) Flag(flag string) string {
+	values := c.Flags(flag)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// Flags returns values of all occurrences of the flag (for example --tx-in)
+func (c Call) Flags(flag string) (values []string) {
+	for i := 0; i < len(c.Args)-1; i++ {
+		if c.Args[i] == flag {
+			values = append(values, c.Args[i+1])
+		}
+	}
+
+	return values
+}
+
+// HasFlag returns true if the call contains flag (with or without value)
+func (c Call) HasFlag(flag string) bool {
+	for _, arg := range c.Args {
+		if arg == flag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Response is canned response of the fake cardano-cli
+type Response struct {
+	Stdout string
+	Err    error
+	// Files maps flag (for example --out-file) to the content which is written to the path passed with that flag
+	Files map[string][]byte
+}
+
+// HandlerFunc creates response for the call
+type HandlerFunc func(call Call) Response
+
+type handler struct {
+	command []string
+	handle  HandlerFunc
+}
+
+// FakeCardanoCli records calls and returns canned responses for commands
+type FakeCardanoCli struct {
+	lock     sync.Mutex
+	handlers []handler
+	calls    []Call
+}
+
+func NewFakeCardanoCli() *FakeCardanoCli {
+	return &FakeCardanoCli{}
+}
+
+// Handle registers response for command (leading arguments, for example "query", "utxo")
+func (f *FakeCardanoCli) Handle(response Response, command ...string) *FakeCardanoCli {
+	return f.HandleFunc(func(Call) Response {
+		return response
+	}, command...)
+}
+
+// HandleFunc registers handler for command (leading arguments, for example "transaction", "build-raw")
+// later registered handlers have priority
+func (f *FakeCardanoCli) HandleFunc(handle HandlerFunc, command ...string) *FakeCardanoCli {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.handlers = append(f.handlers, handler{command: command, handle: handle})
+
+	return f
+}
+
+// RunCommand implements core.ICommandRunner
+func (f *FakeCardanoCli) RunCommand(binary string, args []string, envVariables ...string) (string, error) {
+	call := Call{
+		Binary: binary,
+		Args:   append([]string(nil), args...),
+		Env:    append([]string(nil), envVariables...),
+	}
+
+	f.lock.Lock()
+	f.calls = append(f.calls, call)
+	handle := f.findHandler(args)
+	f.lock.Unlock()
+
+	if handle == nil {
+		return "", fmt.Errorf("fake cardano-cli: unexpected command: %s", strings.Join(args, " "))
+	}
+
+	response := handle(call)
+
+	for flag, content := range response.Files {
+		filePath := call.Flag(flag)
+		if filePath == "" {
+			return "", fmt.Errorf("fake cardano-cli: flag %s not found: %s", flag, strings.Join(args, " "))
+		}
+
+		if err := os.WriteFile(filePath, content, filePermission); err != nil {
+			return "", err
+		}
+	}
+
+	return response.Stdout, response.Err
+}
+
+// Calls returns all the recorded calls
+func (f *FakeCardanoCli) Calls() []Call {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return append([]Call(nil), f.calls...)
+}
+
+// CallsOf returns recorded calls of the command (leading arguments)
+func (f *FakeCardanoCli) CallsOf(command ...string) (result []Call) {
+	for _, call := range f.Calls() {
+		if hasPrefix(call.Args, command) {
+			result = append(result, call)
+		}
+	}
+
+	return result
+}
+
+func (f *FakeCardanoCli) findHandler(args []string) HandlerFunc {
+	for i := len(f.handlers) - 1; i >= 0; i-- {
+		if hasPrefix(args, f.handlers[i].command) {
+			return f.handlers[i].handle
+		}
+	}
+
+	return nil
+}
+
+func hasPrefix(args []string, prefix []string) bool {
+	if len(args) < len(prefix) {
+		return false
+	}
+
+	for i, x := range prefix {
+		if args[i] != x {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,79 @@
+package clitest
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// UtxoRow is one row of query utxo output
+type UtxoRow struct {
+	TxHash   string
+	TxIx     uint32
+	Lovelace uint64
+	// Assets are in cardano-cli format: "<amount> <policy id>.<hex name>"
+	Assets []string
+}
+
+// QueryUtxoOutput returns query utxo output in cardano-cli table format
+func QueryUtxoOutput(rows ...UtxoRow) string {
+	var sb strings.Builder
+
+	sb.WriteString("                           TxHash                                 TxIx        Amount\n")
+	sb.WriteString(strings.Repeat("-", 86))
+	sb.WriteRune('\n')
+
+	for _, row := range rows {
+		sb.WriteString(fmt.Sprintf("%s     %d        %d lovelace", row.TxHash, row.TxIx, row.Lovelace))
+
+		for _, asset := range row.Assets {
+			sb.WriteString(" + ")
+			sb.WriteString(asset)
+		}
+
+		sb.WriteString(" + TxOutDatumNone\n")
+	}
+
+	return sb.String()
+}
+
+// QueryTipOutput returns query tip output in cardano-cli json format
+func QueryTipOutput(block, epoch, slot uint64, era string) string {
+	bytes, _ := json.MarshalIndent(map[string]interface{}{
+		"block":           block,
+		"epoch":           epoch,
+		"era":             era,
+		"hash":            strings.Repeat("ab", 32),
+		"slot":            slot,
+		"slotInEpoch":     slot % 432000,
+		"slotsToEpochEnd": 432000 - slot%432000,
+		"syncProgress":    "100.00",
+	}, "", "    ")
+
+	return string(bytes)
+}
+
+// TextEnvelope returns cardano-cli text envelope json (tx.draft, tx.signed, ...) for cbor
+func TextEnvelope(envelopeType string, cbor []byte) []byte {
+	bytes, _ := json.MarshalIndent(map[string]string{
+		"type":        envelopeType,
+		"description": "Ledger Cddl Format",
+		"cborHex":     hex.EncodeToString(cbor),
+	}, "", "    ")
+
+	return bytes
+}
+
+// ReadTextEnvelope returns cbor from cardano-cli text envelope json
+func ReadTextEnvelope(content []byte) ([]byte, error) {
+	var envelope struct {
+		CborHex string `json:"cborHex"`
+	}
+
+	if err := json.Unmarshal(content, &envelope); err != nil {
+		return nil, err
+	}
+
+	return hex.DecodeString(envelope.CborHex)
+}
@@ -31,6 +31,50 @@ func ResolveCardanoCliBinary(_ CardanoNetworkType) string {
 	return "cardano-cli"
 }
 
+// ICommandRunner runs binary with args and returns its standard output
+type ICommandRunner interface {
+	RunCommand(binary string, args []string, envVariables ...string) (string, error)
+}
+
+// processCommandRunner runs binary as a new process
+type processCommandRunner struct{}
+
+func (processCommandRunner) RunCommand(binary string, args []string, envVariables ...string) (string, error) {
+	return runCommand(binary, args, envVariables...)
+}
+
+// CardanoCliOption configures how cardano-cli is executed
+type CardanoCliOption func(c *cardanoCli)
+
+// WithCommandRunner replaces execution of cardano-cli process with runner (for example fake cli in tests)
+func WithCommandRunner(runner ICommandRunner) CardanoCliOption {
+	return func(c *cardanoCli) {
+		c.runner = runner
+	}
+}
+
+type cardanoCli struct {
+	binary string
+	runner ICommandRunner
+}
+
+func newCardanoCli(binary string, options ...CardanoCliOption) cardanoCli {
+	cli := cardanoCli{
+		binary: binary,
+		runner: processCommandRunner{},
+	}
+
+	for _, opt := range options {
+		opt(&cli)
+	}
+
+	return cli
+}
+
+func (c cardanoCli) run(args []string) (string, error) {
+	return c.runner.RunCommand(c.binary, args)
+}
+
 func runCommand(binary string, args []string, envVariables ...string) (string, error) {
 	var (
 		stdErrBuffer bytes.Buffer
@@ -117,6 +117,11 @@ type ITxDataRetriever interface {
 	GetProtocolParameters(ctx context.Context) ([]byte, error)
 }
 
+// ITxEvaluator evaluates plutus scripts of the transaction and returns execution units of every redeemer
+type ITxEvaluator interface {
+	EvaluateTx(ctx context.Context, txRaw []byte) (map[TxRedeemerPointer]TxExUnits, error)
+}
+
 type IUTxORetriever interface {
 	GetUtxos(ctx context.Context, addr string) ([]Utxo, error)
 }
@@ -133,6 +138,11 @@ type ITxSigner interface {
 	GetPaymentKeys() ([]byte, []byte)
 }
 
+// ITxStakeSigner is signer which also witnesses certificates and withdrawals of its stake key
+type ITxStakeSigner interface {
+	GetStakeKeys() ([]byte, []byte)
+}
+
 type IPolicyScript interface {
 	GetPolicyScriptJSON() ([]byte, error)
 	GetCount() int
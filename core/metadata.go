@@ -0,0 +1,162 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+const metadataMaxStringLength = 64
+
+var ErrInvalidMetadata = errors.New("invalid metadata")
+
+// getMetadataCbor converts metadata json (cardano-cli no schema format) to cbor
+func getMetadataCbor(metadataJSON []byte) ([]byte, error) {
+	var data map[string]interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(metadataJSON))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&data); err != nil {
+		return nil, errors.Join(ErrInvalidMetadata, err)
+	}
+
+	labels := make([]uint64, 0, len(data))
+
+	for key := range data {
+		label, err := strconv.ParseUint(key, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: label %s is not a number", ErrInvalidMetadata, key)
+		}
+
+		labels = append(labels, label)
+	}
+
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i] < labels[j]
+	})
+
+	result := make(cborOrderedMap, len(labels))
+
+	for i, label := range labels {
+		value, err := getMetadataValue(data[strconv.FormatUint(label, 10)])
+		if err != nil {
+			return nil, err
+		}
+
+		result[i] = cborKeyValue{Key: label, Value: value}
+	}
+
+	return cbor.Marshal(result)
+}
+
+// getAuxiliaryDataCbor returns auxiliary data cbor (alonzo format) for metadata json
+func getAuxiliaryDataCbor(metadataJSON []byte) ([]byte, error) {
+	metadata, err := getMetadataCbor(metadataJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	return cbor.Marshal(cbor.Tag{
+		Number: cborAuxiliaryDataAlonzoTag,
+		Content: cborOrderedMap{
+			{Key: 0, Value: cbor.RawMessage(metadata)},
+		},
+	})
+}
+
+func getMetadataValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case json.Number:
+		number, ok := new(big.Int).SetString(v.String(), 10)
+		if !ok {
+			return nil, fmt.Errorf("%w: number %s is not an integer", ErrInvalidMetadata, v)
+		}
+
+		return number, nil
+	case string:
+		if bytes, ok := getMetadataBytes(v); ok {
+			if len(bytes) > metadataMaxStringLength {
+				return nil, fmt.Errorf("%w: bytes %s longer than %d", ErrInvalidMetadata, v, metadataMaxStringLength)
+			}
+
+			return bytes, nil
+		}
+
+		if len(v) > metadataMaxStringLength {
+			return nil, fmt.Errorf("%w: string %s longer than %d", ErrInvalidMetadata, v, metadataMaxStringLength)
+		}
+
+		return v, nil
+	case []interface{}:
+		result := make([]interface{}, len(v))
+
+		for i, item := range v {
+			itemValue, err := getMetadataValue(item)
+			if err != nil {
+				return nil, err
+			}
+
+			result[i] = itemValue
+		}
+
+		return result, nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		result := make(cborOrderedMap, len(keys))
+
+		for i, key := range keys {
+			itemValue, err := getMetadataValue(v[key])
+			if err != nil {
+				return nil, err
+			}
+
+			result[i] = cborKeyValue{Key: getMetadataKey(key), Value: itemValue}
+		}
+
+		return result, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported value %v", ErrInvalidMetadata, value)
+	}
+}
+
+// getMetadataKey converts map key to number or bytes if possible, otherwise key remains string
+func getMetadataKey(key string) interface{} {
+	if number, ok := new(big.Int).SetString(key, 10); ok {
+		return number
+	}
+
+	if bytes, ok := getMetadataBytes(key); ok {
+		return bytes
+	}
+
+	return key
+}
+
+func getMetadataBytes(value string) ([]byte, bool) {
+	hexValue, found := strings.CutPrefix(value, "0x")
+	if !found || strings.ToLower(hexValue) != hexValue {
+		return nil, false
+	}
+
+	bytes, err := hex.DecodeString(hexValue)
+	if err != nil {
+		return nil, false
+	}
+
+	return bytes, true
+}
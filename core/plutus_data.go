@@ -0,0 +1,569 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/fxamacker/cbor/v2"
+	"golang.org/x/crypto/blake2b"
+)
+
+type PlutusDataType string
+
+const (
+	PlutusDataConstrType PlutusDataType = "constructor"
+	PlutusDataMapType    PlutusDataType = "map"
+	PlutusDataListType   PlutusDataType = "list"
+	PlutusDataIntType    PlutusDataType = "int"
+	PlutusDataBytesType  PlutusDataType = "bytes"
+
+	// constructor alternatives 0-6 are tags 121-127, 7-127 are tags 1280-1400
+	// and all the others are tag 102 with [alternative, fields]
+	plutusDataConstrTagBase         = 121
+	plutusDataConstrTagExtendedBase = 1280
+	plutusDataConstrTagGeneral      = 102
+	plutusDataBigUIntTag            = 2
+	plutusDataBigNIntTag            = 3
+
+	// plutus bytes longer than 64 are encoded as indefinite bytes with 64 bytes long chunks
+	plutusDataBytesChunkSize = 64
+
+	cborMajorTypeUInt  = byte(0)
+	cborMajorTypeNInt  = byte(1 << 5)
+	cborMajorTypeBytes = byte(2 << 5)
+	cborMajorTypeTag   = byte(6 << 5)
+	cborIndefinite     = byte(31)
+	cborBreak          = byte(0xff)
+)
+
+var ErrInvalidPlutusData = errors.New("invalid plutus data")
+
+// PlutusData is datum or redeemer data: constructor, map, list, integer or bytes
+type PlutusData struct {
+	Type PlutusDataType
+	// Constructor is constructor alternative and Fields are its fields
+	Constructor uint64
+	Fields      []PlutusData
+	Map         []PlutusDataMapEntry
+	List        []PlutusData
+	Int         *big.Int
+	Bytes       []byte
+
+	// original is set if plutus data is decoded from cbor which is not encoded the same way as MarshalCBOR does
+	original *plutusDataCbor
+}
+
+// plutusDataCbor is original plutus data cbor and its MarshalCBOR encoding
+type plutusDataCbor struct {
+	raw       []byte
+	canonical []byte
+}
+
+type PlutusDataMapEntry struct {
+	Key   PlutusData
+	Value PlutusData
+}
+
+func NewPlutusDataConstr(alternative uint64, fields ...PlutusData) PlutusData {
+	if fields == nil {
+		fields = []PlutusData{}
+	}
+
+	return PlutusData{
+		Type:        PlutusDataConstrType,
+		Constructor: alternative,
+		Fields:      fields,
+	}
+}
+
+func NewPlutusDataMap(entries ...PlutusDataMapEntry) PlutusData {
+	if entries == nil {
+		entries = []PlutusDataMapEntry{}
+	}
+
+	return PlutusData{
+		Type: PlutusDataMapType,
+		Map:  entries,
+	}
+}
+
+func NewPlutusDataMapEntry(key PlutusData, value PlutusData) PlutusDataMapEntry {
+	return PlutusDataMapEntry{
+		Key:   key,
+		Value: value,
+	}
+}
+
+func NewPlutusDataList(items ...PlutusData) PlutusData {
+	if items == nil {
+		items = []PlutusData{}
+	}
+
+	return PlutusData{
+		Type: PlutusDataListType,
+		List: items,
+	}
+}
+
+func NewPlutusDataInt(value int64) PlutusData {
+	return NewPlutusDataBigInt(big.NewInt(value))
+}
+
+func NewPlutusDataBigInt(value *big.Int) PlutusData {
+	return PlutusData{
+		Type: PlutusDataIntType,
+		Int:  new(big.Int).Set(value),
+	}
+}
+
+func NewPlutusDataBytes(value []byte) PlutusData {
+	if value == nil {
+		value = []byte{}
+	}
+
+	return PlutusData{
+		Type:  PlutusDataBytesType,
+		Bytes: value,
+	}
+}
+
+// NewPlutusDataFromCbor decodes plutus data cbor
+func NewPlutusDataFromCbor(data []byte) (PlutusData, error) {
+	var pd PlutusData
+
+	if err := pd.UnmarshalCBOR(data); err != nil {
+		return PlutusData{}, err
+	}
+
+	return pd, nil
+}
+
+// NewPlutusDataFromJSON decodes cardano-cli detailed schema json
+func NewPlutusDataFromJSON(data []byte) (PlutusData, error) {
+	var pd PlutusData
+
+	if err := pd.UnmarshalJSON(data); err != nil {
+		return PlutusData{}, err
+	}
+
+	return pd, nil
+}
+
+// Hash returns datum hash (blake2b-256 of plutus data cbor).
+// Hash of the original cbor is returned for decoded and not modified plutus data
+func (pd PlutusData) Hash() (string, error) {
+	data, err := pd.MarshalCBOR()
+	if err != nil {
+		return "", err
+	}
+
+	hash := blake2b.Sum256(data)
+
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// MarshalCBOR encodes plutus data the same way as cardano node does:
+// non empty lists are indefinite and long bytes are split into chunks.
+// Original cbor is returned for decoded and not modified plutus data
+func (pd PlutusData) MarshalCBOR() ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := pd.writeCbor(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (pd PlutusData) writeCbor(buf *bytes.Buffer) error {
+	if pd.original == nil {
+		return pd.writeCanonicalCbor(buf)
+	}
+
+	var canonicalBuf bytes.Buffer
+
+	if err := pd.writeCanonicalCbor(&canonicalBuf); err != nil {
+		return err
+	}
+
+	if bytes.Equal(canonicalBuf.Bytes(), pd.original.canonical) {
+		buf.Write(pd.original.raw)
+	} else {
+		buf.Write(canonicalBuf.Bytes())
+	}
+
+	return nil
+}
+
+func (pd PlutusData) writeCanonicalCbor(buf *bytes.Buffer) error {
+	switch pd.Type {
+	case PlutusDataConstrType:
+		switch {
+		case pd.Constructor < 7:
+			buf.Write(getCborHeader(cborMajorTypeTag, plutusDataConstrTagBase+pd.Constructor))
+		case pd.Constructor < 128:
+			buf.Write(getCborHeader(cborMajorTypeTag, plutusDataConstrTagExtendedBase+pd.Constructor-7))
+		default:
+			buf.Write(getCborHeader(cborMajorTypeTag, plutusDataConstrTagGeneral))
+			buf.Write(getCborHeader(cborMajorTypeArray, 2))
+			buf.Write(getCborHeader(cborMajorTypeUInt, pd.Constructor))
+		}
+
+		return writePlutusDataListCbor(buf, pd.Fields)
+	case PlutusDataMapType:
+		buf.Write(getCborHeader(cborMajorTypeMap, uint64(len(pd.Map))))
+
+		for _, entry := range pd.Map {
+			if err := entry.Key.writeCbor(buf); err != nil {
+				return err
+			}
+
+			if err := entry.Value.writeCbor(buf); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case PlutusDataListType:
+		return writePlutusDataListCbor(buf, pd.List)
+	case PlutusDataIntType:
+		if pd.Int == nil {
+			return fmt.Errorf("%w: int value not set", ErrInvalidPlutusData)
+		}
+
+		switch {
+		case pd.Int.IsUint64():
+			buf.Write(getCborHeader(cborMajorTypeUInt, pd.Int.Uint64()))
+		case pd.Int.Sign() < 0 && new(big.Int).Not(pd.Int).IsUint64():
+			// -1 - n
+			buf.Write(getCborHeader(cborMajorTypeNInt, new(big.Int).Not(pd.Int).Uint64()))
+		case pd.Int.Sign() > 0:
+			buf.Write(getCborHeader(cborMajorTypeTag, plutusDataBigUIntTag))
+			writePlutusDataBytesCbor(buf, pd.Int.Bytes())
+		default:
+			buf.Write(getCborHeader(cborMajorTypeTag, plutusDataBigNIntTag))
+			writePlutusDataBytesCbor(buf, new(big.Int).Not(pd.Int).Bytes())
+		}
+
+		return nil
+	case PlutusDataBytesType:
+		writePlutusDataBytesCbor(buf, pd.Bytes)
+
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown type %s", ErrInvalidPlutusData, pd.Type)
+	}
+}
+
+func writePlutusDataListCbor(buf *bytes.Buffer, items []PlutusData) error {
+	if len(items) == 0 {
+		buf.Write(getCborHeader(cborMajorTypeArray, 0))
+
+		return nil
+	}
+
+	buf.WriteByte(cborMajorTypeArray | cborIndefinite)
+
+	for _, item := range items {
+		if err := item.writeCbor(buf); err != nil {
+			return err
+		}
+	}
+
+	buf.WriteByte(cborBreak)
+
+	return nil
+}
+
+func writePlutusDataBytesCbor(buf *bytes.Buffer, value []byte) {
+	if len(value) <= plutusDataBytesChunkSize {
+		buf.Write(getCborHeader(cborMajorTypeBytes, uint64(len(value))))
+		buf.Write(value)
+
+		return
+	}
+
+	buf.WriteByte(cborMajorTypeBytes | cborIndefinite)
+
+	for i := 0; i < len(value); i += plutusDataBytesChunkSize {
+		chunk := value[i:min(i+plutusDataBytesChunkSize, len(value))]
+
+		buf.Write(getCborHeader(cborMajorTypeBytes, uint64(len(chunk))))
+		buf.Write(chunk)
+	}
+
+	buf.WriteByte(cborBreak)
+}
+
+// UnmarshalCBOR decodes plutus data cbor (definite and indefinite lengths are accepted).
+// Original cbor is kept so MarshalCBOR and Hash return the same bytes and hash
+func (pd *PlutusData) UnmarshalCBOR(data []byte) error {
+	if err := pd.unmarshalCbor(data); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+
+	if err := pd.writeCanonicalCbor(&buf); err != nil {
+		return err
+	}
+
+	if !bytes.Equal(buf.Bytes(), data) {
+		pd.original = &plutusDataCbor{
+			raw:       bytes.Clone(data),
+			canonical: buf.Bytes(),
+		}
+	}
+
+	return nil
+}
+
+func (pd *PlutusData) unmarshalCbor(data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("%w: empty cbor", ErrInvalidPlutusData)
+	}
+
+	switch data[0] & 0xe0 {
+	case cborMajorTypeUInt, cborMajorTypeNInt:
+		return pd.unmarshalIntCbor(data)
+	case cborMajorTypeBytes:
+		var value []byte
+
+		if err := cbor.Unmarshal(data, &value); err != nil {
+			return err
+		}
+
+		*pd = NewPlutusDataBytes(value)
+	case cborMajorTypeArray:
+		items, err := decodePlutusDataListCbor(data)
+		if err != nil {
+			return err
+		}
+
+		*pd = NewPlutusDataList(items...)
+	case cborMajorTypeMap:
+		rawEntries, err := decodeCborMapEntries(data)
+		if err != nil {
+			return err
+		}
+
+		entries := make([]PlutusDataMapEntry, len(rawEntries))
+
+		for i, rawEntry := range rawEntries {
+			if err := entries[i].Key.unmarshalCbor(rawEntry[0]); err != nil {
+				return err
+			}
+
+			if err := entries[i].Value.unmarshalCbor(rawEntry[1]); err != nil {
+				return err
+			}
+		}
+
+		*pd = NewPlutusDataMap(entries...)
+	case cborMajorTypeTag:
+		var tag cbor.RawTag
+
+		if err := cbor.Unmarshal(data, &tag); err != nil {
+			return err
+		}
+
+		return pd.unmarshalTagCbor(data, tag)
+	default:
+		return fmt.Errorf("%w: unexpected cbor type %d", ErrInvalidPlutusData, data[0]>>5)
+	}
+
+	return nil
+}
+
+func (pd *PlutusData) unmarshalTagCbor(data []byte, tag cbor.RawTag) error {
+	var (
+		alternative uint64
+		fieldsRaw   = []byte(tag.Content)
+	)
+
+	switch {
+	case tag.Number == plutusDataBigUIntTag || tag.Number == plutusDataBigNIntTag:
+		return pd.unmarshalIntCbor(data)
+	case tag.Number >= plutusDataConstrTagBase && tag.Number < plutusDataConstrTagBase+7:
+		alternative = tag.Number - plutusDataConstrTagBase
+	case tag.Number >= plutusDataConstrTagExtendedBase && tag.Number < plutusDataConstrTagExtendedBase+121:
+		alternative = tag.Number - plutusDataConstrTagExtendedBase + 7
+	case tag.Number == plutusDataConstrTagGeneral:
+		var content struct {
+			_           struct{} `cbor:",toarray"`
+			Alternative uint64
+			Fields      cbor.RawMessage
+		}
+
+		if err := cbor.Unmarshal(tag.Content, &content); err != nil {
+			return err
+		}
+
+		alternative, fieldsRaw = content.Alternative, content.Fields
+	default:
+		return fmt.Errorf("%w: unexpected cbor tag %d", ErrInvalidPlutusData, tag.Number)
+	}
+
+	fields, err := decodePlutusDataListCbor(fieldsRaw)
+	if err != nil {
+		return err
+	}
+
+	*pd = NewPlutusDataConstr(alternative, fields...)
+
+	return nil
+}
+
+func (pd *PlutusData) unmarshalIntCbor(data []byte) error {
+	var value big.Int
+
+	if err := cbor.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	*pd = NewPlutusDataBigInt(&value)
+
+	return nil
+}
+
+func decodePlutusDataListCbor(data []byte) ([]PlutusData, error) {
+	var rawItems []cbor.RawMessage
+
+	if err := cbor.Unmarshal(data, &rawItems); err != nil {
+		return nil, err
+	}
+
+	items := make([]PlutusData, len(rawItems))
+
+	for i, rawItem := range rawItems {
+		if err := items[i].unmarshalCbor(rawItem); err != nil {
+			return nil, err
+		}
+	}
+
+	return items, nil
+}
+
+// MarshalJSON encodes plutus data in cardano-cli detailed schema json
+func (pd PlutusData) MarshalJSON() ([]byte, error) {
+	switch pd.Type {
+	case PlutusDataConstrType:
+		return json.Marshal(map[string]interface{}{
+			"constructor": pd.Constructor,
+			"fields":      nonNilSlice(pd.Fields),
+		})
+	case PlutusDataMapType:
+		entries := make([]map[string]PlutusData, len(pd.Map))
+
+		for i, entry := range pd.Map {
+			entries[i] = map[string]PlutusData{"k": entry.Key, "v": entry.Value}
+		}
+
+		return json.Marshal(map[string]interface{}{"map": entries})
+	case PlutusDataListType:
+		return json.Marshal(map[string]interface{}{"list": nonNilSlice(pd.List)})
+	case PlutusDataIntType:
+		if pd.Int == nil {
+			return nil, fmt.Errorf("%w: int value not set", ErrInvalidPlutusData)
+		}
+
+		return json.Marshal(map[string]interface{}{"int": pd.Int})
+	case PlutusDataBytesType:
+		return json.Marshal(map[string]interface{}{"bytes": hex.EncodeToString(pd.Bytes)})
+	default:
+		return nil, fmt.Errorf("%w: unknown type %s", ErrInvalidPlutusData, pd.Type)
+	}
+}
+
+// UnmarshalJSON decodes cardano-cli detailed schema json
+func (pd *PlutusData) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	switch {
+	case fields["constructor"] != nil && fields["fields"] != nil && len(fields) == 2:
+		var (
+			alternative uint64
+			items       []PlutusData
+		)
+
+		if err := json.Unmarshal(fields["constructor"], &alternative); err != nil {
+			return err
+		}
+
+		if err := json.Unmarshal(fields["fields"], &items); err != nil {
+			return err
+		}
+
+		*pd = NewPlutusDataConstr(alternative, items...)
+	case fields["map"] != nil && len(fields) == 1:
+		var rawEntries []struct {
+			Key   *PlutusData `json:"k"`
+			Value *PlutusData `json:"v"`
+		}
+
+		if err := json.Unmarshal(fields["map"], &rawEntries); err != nil {
+			return err
+		}
+
+		entries := make([]PlutusDataMapEntry, len(rawEntries))
+
+		for i, rawEntry := range rawEntries {
+			if rawEntry.Key == nil || rawEntry.Value == nil {
+				return fmt.Errorf("%w: map entry must have k and v", ErrInvalidPlutusData)
+			}
+
+			entries[i] = NewPlutusDataMapEntry(*rawEntry.Key, *rawEntry.Value)
+		}
+
+		*pd = NewPlutusDataMap(entries...)
+	case fields["list"] != nil && len(fields) == 1:
+		var items []PlutusData
+
+		if err := json.Unmarshal(fields["list"], &items); err != nil {
+			return err
+		}
+
+		*pd = NewPlutusDataList(items...)
+	case fields["int"] != nil && len(fields) == 1:
+		var value big.Int
+
+		if err := json.Unmarshal(fields["int"], &value); err != nil {
+			return err
+		}
+
+		*pd = NewPlutusDataBigInt(&value)
+	case fields["bytes"] != nil && len(fields) == 1:
+		var hexValue string
+
+		if err := json.Unmarshal(fields["bytes"], &hexValue); err != nil {
+			return err
+		}
+
+		value, err := hex.DecodeString(hexValue)
+		if err != nil {
+			return err
+		}
+
+		*pd = NewPlutusDataBytes(value)
+	default:
+		return fmt.Errorf("%w: unknown json schema: %s", ErrInvalidPlutusData, string(data))
+	}
+
+	return nil
+}
+
+func nonNilSlice[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+
+	return items
+}
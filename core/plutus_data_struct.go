@@ -0,0 +1,390 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// plutusTagName is struct tag used by MarshalPlutusData and UnmarshalPlutusData:
+//   - `plutus:"constr=N"` on blank (_) field sets constructor alternative of the struct (default 0)
+//   - `plutus:"hex"` encodes string as hex decoded bytes instead of utf-8 bytes
+//   - `plutus:"-"` skips the field
+const plutusTagName = "plutus"
+
+var (
+	plutusDataReflectType = reflect.TypeOf(PlutusData{})
+	bigIntReflectType     = reflect.TypeOf(big.Int{})
+)
+
+// MarshalPlutusData converts go value into plutus data:
+// structs are constructors with exported fields in declaration order, bool is constructor 0 (false) or 1 (true),
+// integers and big.Int are ints, []byte and strings are bytes, slices and arrays are lists and maps are maps
+func MarshalPlutusData(value interface{}) (PlutusData, error) {
+	if value == nil {
+		return PlutusData{}, fmt.Errorf("%w: nil value", ErrInvalidPlutusData)
+	}
+
+	return marshalPlutusData(reflect.ValueOf(value), false)
+}
+
+// UnmarshalPlutusData converts plutus data into go value pointed by value (reverse of MarshalPlutusData)
+func UnmarshalPlutusData(pd PlutusData, value interface{}) error {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("%w: non nil pointer expected", ErrInvalidPlutusData)
+	}
+
+	return unmarshalPlutusData(pd, rv.Elem(), false)
+}
+
+type plutusFieldTag struct {
+	skip        bool
+	isHex       bool
+	constructor uint64
+}
+
+func parsePlutusFieldTag(tag string) (result plutusFieldTag, err error) {
+	for _, option := range strings.Split(tag, ",") {
+		switch {
+		case option == "":
+		case option == "-":
+			result.skip = true
+		case option == "hex":
+			result.isHex = true
+		case strings.HasPrefix(option, "constr="):
+			result.constructor, err = strconv.ParseUint(strings.TrimPrefix(option, "constr="), 10, 64)
+			if err != nil {
+				return result, fmt.Errorf("invalid plutus tag %s: %w", tag, err)
+			}
+		default:
+			return result, fmt.Errorf("unknown plutus tag option: %s", option)
+		}
+	}
+
+	return result, nil
+}
+
+type plutusStructField struct {
+	index int
+	isHex bool
+}
+
+// getPlutusStructFields returns constructor alternative and fields of the struct which are part of plutus data
+func getPlutusStructFields(structType reflect.Type) (uint64, []plutusStructField, error) {
+	var (
+		constructor uint64
+		fields      []plutusStructField
+	)
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+
+		tag, err := parsePlutusFieldTag(field.Tag.Get(plutusTagName))
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if field.Name == "_" {
+			constructor = tag.constructor
+		} else if field.IsExported() && !tag.skip {
+			fields = append(fields, plutusStructField{index: i, isHex: tag.isHex})
+		}
+	}
+
+	return constructor, fields, nil
+}
+
+func marshalPlutusData(rv reflect.Value, isHex bool) (PlutusData, error) {
+	if rv.Type() == plutusDataReflectType {
+		return rv.Interface().(PlutusData), nil //nolint:forcetypeassert
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return PlutusData{}, fmt.Errorf("%w: nil %s", ErrInvalidPlutusData, rv.Type())
+		}
+
+		return marshalPlutusData(rv.Elem(), isHex)
+	case reflect.Bool:
+		if rv.Bool() {
+			return NewPlutusDataConstr(1), nil
+		}
+
+		return NewPlutusDataConstr(0), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return NewPlutusDataInt(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return NewPlutusDataBigInt(new(big.Int).SetUint64(rv.Uint())), nil
+	case reflect.String:
+		if !isHex {
+			return NewPlutusDataBytes([]byte(rv.String())), nil
+		}
+
+		value, err := hex.DecodeString(rv.String())
+		if err != nil {
+			return PlutusData{}, err
+		}
+
+		return NewPlutusDataBytes(value), nil
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			value := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(value), rv)
+
+			return NewPlutusDataBytes(value), nil
+		}
+
+		items := make([]PlutusData, rv.Len())
+
+		for i := range items {
+			item, err := marshalPlutusData(rv.Index(i), isHex)
+			if err != nil {
+				return PlutusData{}, err
+			}
+
+			items[i] = item
+		}
+
+		return NewPlutusDataList(items...), nil
+	case reflect.Map:
+		return marshalPlutusDataMap(rv, isHex)
+	case reflect.Struct:
+		if rv.Type() == bigIntReflectType {
+			value := rv.Interface().(big.Int) //nolint:forcetypeassert
+
+			return NewPlutusDataBigInt(&value), nil
+		}
+
+		constructor, fields, err := getPlutusStructFields(rv.Type())
+		if err != nil {
+			return PlutusData{}, err
+		}
+
+		items := make([]PlutusData, len(fields))
+
+		for i, field := range fields {
+			item, err := marshalPlutusData(rv.Field(field.index), field.isHex)
+			if err != nil {
+				return PlutusData{}, fmt.Errorf("%s: %w", rv.Type().Field(field.index).Name, err)
+			}
+
+			items[i] = item
+		}
+
+		return NewPlutusDataConstr(constructor, items...), nil
+	default:
+		return PlutusData{}, fmt.Errorf("%w: unsupported type %s", ErrInvalidPlutusData, rv.Type())
+	}
+}
+
+// marshalPlutusDataMap returns plutus map with entries sorted by key cbor so the result is deterministic
+func marshalPlutusDataMap(rv reflect.Value, isHex bool) (PlutusData, error) {
+	type entryWithCbor struct {
+		entry   PlutusDataMapEntry
+		keyCbor []byte
+	}
+
+	entries := make([]entryWithCbor, 0, rv.Len())
+	iter := rv.MapRange()
+
+	for iter.Next() {
+		key, err := marshalPlutusData(iter.Key(), isHex)
+		if err != nil {
+			return PlutusData{}, err
+		}
+
+		value, err := marshalPlutusData(iter.Value(), isHex)
+		if err != nil {
+			return PlutusData{}, err
+		}
+
+		keyCbor, err := key.MarshalCBOR()
+		if err != nil {
+			return PlutusData{}, err
+		}
+
+		entries = append(entries, entryWithCbor{
+			entry:   NewPlutusDataMapEntry(key, value),
+			keyCbor: keyCbor,
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].keyCbor, entries[j].keyCbor) < 0
+	})
+
+	result := make([]PlutusDataMapEntry, len(entries))
+
+	for i, entry := range entries {
+		result[i] = entry.entry
+	}
+
+	return NewPlutusDataMap(result...), nil
+}
+
+func unmarshalPlutusData(pd PlutusData, rv reflect.Value, isHex bool) error {
+	if rv.Type() == plutusDataReflectType {
+		rv.Set(reflect.ValueOf(pd))
+
+		return nil
+	}
+
+	checkType := func(expected PlutusDataType) error {
+		if pd.Type != expected {
+			return fmt.Errorf("%w: expected %s for %s, got %s", ErrInvalidPlutusData, expected, rv.Type(), pd.Type)
+		}
+
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+
+		return unmarshalPlutusData(pd, rv.Elem(), isHex)
+	case reflect.Bool:
+		if err := checkType(PlutusDataConstrType); err != nil {
+			return err
+		} else if pd.Constructor > 1 || len(pd.Fields) > 0 {
+			return fmt.Errorf("%w: invalid bool constructor %d", ErrInvalidPlutusData, pd.Constructor)
+		}
+
+		rv.SetBool(pd.Constructor == 1)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if err := checkType(PlutusDataIntType); err != nil {
+			return err
+		} else if !pd.Int.IsInt64() || rv.OverflowInt(pd.Int.Int64()) {
+			return fmt.Errorf("%w: %s overflows %s", ErrInvalidPlutusData, pd.Int, rv.Type())
+		}
+
+		rv.SetInt(pd.Int.Int64())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if err := checkType(PlutusDataIntType); err != nil {
+			return err
+		} else if !pd.Int.IsUint64() || rv.OverflowUint(pd.Int.Uint64()) {
+			return fmt.Errorf("%w: %s overflows %s", ErrInvalidPlutusData, pd.Int, rv.Type())
+		}
+
+		rv.SetUint(pd.Int.Uint64())
+	case reflect.String:
+		if err := checkType(PlutusDataBytesType); err != nil {
+			return err
+		}
+
+		if isHex {
+			rv.SetString(hex.EncodeToString(pd.Bytes))
+		} else {
+			rv.SetString(string(pd.Bytes))
+		}
+	case reflect.Slice, reflect.Array:
+		return unmarshalPlutusDataList(pd, rv, isHex)
+	case reflect.Map:
+		if err := checkType(PlutusDataMapType); err != nil {
+			return err
+		}
+
+		result := reflect.MakeMapWithSize(rv.Type(), len(pd.Map))
+
+		for _, entry := range pd.Map {
+			key := reflect.New(rv.Type().Key()).Elem()
+			value := reflect.New(rv.Type().Elem()).Elem()
+
+			if err := unmarshalPlutusData(entry.Key, key, isHex); err != nil {
+				return err
+			}
+
+			if err := unmarshalPlutusData(entry.Value, value, isHex); err != nil {
+				return err
+			}
+
+			result.SetMapIndex(key, value)
+		}
+
+		rv.Set(result)
+	case reflect.Struct:
+		if rv.Type() == bigIntReflectType {
+			if err := checkType(PlutusDataIntType); err != nil {
+				return err
+			}
+
+			rv.Set(reflect.ValueOf(*new(big.Int).Set(pd.Int)))
+
+			return nil
+		}
+
+		return unmarshalPlutusDataStruct(pd, rv)
+	default:
+		return fmt.Errorf("%w: unsupported type %s", ErrInvalidPlutusData, rv.Type())
+	}
+
+	return nil
+}
+
+func unmarshalPlutusDataList(pd PlutusData, rv reflect.Value, isHex bool) error {
+	if rv.Type().Elem().Kind() == reflect.Uint8 {
+		if pd.Type != PlutusDataBytesType {
+			return fmt.Errorf("%w: expected %s for %s, got %s", ErrInvalidPlutusData, PlutusDataBytesType, rv.Type(), pd.Type)
+		}
+
+		if rv.Kind() == reflect.Slice {
+			rv.Set(reflect.MakeSlice(rv.Type(), len(pd.Bytes), len(pd.Bytes)))
+		} else if rv.Len() != len(pd.Bytes) {
+			return fmt.Errorf("%w: expected %d bytes, got %d", ErrInvalidPlutusData, rv.Len(), len(pd.Bytes))
+		}
+
+		reflect.Copy(rv, reflect.ValueOf(pd.Bytes))
+
+		return nil
+	}
+
+	if pd.Type != PlutusDataListType {
+		return fmt.Errorf("%w: expected %s for %s, got %s", ErrInvalidPlutusData, PlutusDataListType, rv.Type(), pd.Type)
+	}
+
+	if rv.Kind() == reflect.Slice {
+		rv.Set(reflect.MakeSlice(rv.Type(), len(pd.List), len(pd.List)))
+	} else if rv.Len() != len(pd.List) {
+		return fmt.Errorf("%w: expected %d items, got %d", ErrInvalidPlutusData, rv.Len(), len(pd.List))
+	}
+
+	for i, item := range pd.List {
+		if err := unmarshalPlutusData(item, rv.Index(i), isHex); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func unmarshalPlutusDataStruct(pd PlutusData, rv reflect.Value) error {
+	constructor, fields, err := getPlutusStructFields(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	if pd.Type != PlutusDataConstrType {
+		return fmt.Errorf("%w: expected %s for %s, got %s", ErrInvalidPlutusData, PlutusDataConstrType, rv.Type(), pd.Type)
+	} else if pd.Constructor != constructor {
+		return fmt.Errorf("%w: expected constructor %d for %s, got %d",
+			ErrInvalidPlutusData, constructor, rv.Type(), pd.Constructor)
+	} else if len(pd.Fields) != len(fields) {
+		return fmt.Errorf("%w: expected %d fields for %s, got %d", ErrInvalidPlutusData, len(fields), rv.Type(), len(pd.Fields))
+	}
+
+	for i, field := range fields {
+		if err := unmarshalPlutusData(pd.Fields[i], rv.Field(field.index), field.isHex); err != nil {
+			return fmt.Errorf("%s: %w", rv.Type().Field(field.index).Name, err)
+		}
+	}
+
+	return nil
+}
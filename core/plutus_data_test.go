@@ -0,0 +1,229 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+)
+
+func TestPlutusData_Cbor(t *testing.T) {
+	t.Parallel()
+
+	bigValue, _ := new(big.Int).SetString("18446744073709551616", 10) // 2^64
+	longBytes := bytes.Repeat([]byte{0xab}, 65)
+
+	cases := []struct {
+		name string
+		data PlutusData
+		cbor string
+	}{
+		{"unit", NewPlutusDataConstr(0), "d87980"},
+		{"constructor", NewPlutusDataConstr(1, NewPlutusDataInt(42), NewPlutusDataBytes([]byte{0xab})), "d87a9f182a41abff"},
+		{"constructor 7", NewPlutusDataConstr(7), "d9050080"},
+		{"constructor 127", NewPlutusDataConstr(127, NewPlutusDataInt(0)), "d905789f00ff"},
+		{"constructor 200", NewPlutusDataConstr(200), "d8668218c880"},
+		{"negative int", NewPlutusDataInt(-1), "20"},
+		{"big int", NewPlutusDataBigInt(bigValue), "c249010000000000000000"},
+		{"big negative int", NewPlutusDataBigInt(new(big.Int).Neg(new(big.Int).Add(bigValue, big.NewInt(1)))), "c349010000000000000000"},
+		{"long bytes", NewPlutusDataBytes(longBytes), "5f5840" + hex.EncodeToString(longBytes[:64]) + "41abff"},
+		{"empty list", NewPlutusDataList(), "80"},
+		{"map", NewPlutusDataMap(
+			NewPlutusDataMapEntry(NewPlutusDataInt(1), NewPlutusDataBytes(nil)),
+			NewPlutusDataMapEntry(NewPlutusDataBytes([]byte{0x01}), NewPlutusDataList(NewPlutusDataInt(2))),
+		), "a2014041019f02ff"},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			cborBytes, err := c.data.MarshalCBOR()
+			require.NoError(t, err)
+
+			assert.Equal(t, c.cbor, hex.EncodeToString(cborBytes))
+
+			decoded, err := NewPlutusDataFromCbor(cborBytes)
+			require.NoError(t, err)
+
+			decodedCbor, err := decoded.MarshalCBOR()
+			require.NoError(t, err)
+
+			assert.Equal(t, cborBytes, decodedCbor)
+		})
+	}
+
+	t.Run("definite lengths", func(t *testing.T) {
+		t.Parallel()
+
+		pd, err := NewPlutusDataFromCbor(mustDecodeHex(t, "d87a82182a41ab"))
+		require.NoError(t, err)
+
+		assert.Equal(t, PlutusDataConstrType, pd.Type)
+		assert.Equal(t, uint64(1), pd.Constructor)
+		require.Len(t, pd.Fields, 2)
+		assert.Equal(t, int64(42), pd.Fields[0].Int.Int64())
+		assert.Equal(t, []byte{0xab}, pd.Fields[1].Bytes)
+
+		// original encoding is kept for cbor and hash
+		pdCbor, err := pd.MarshalCBOR()
+		require.NoError(t, err)
+
+		assert.Equal(t, mustDecodeHex(t, "d87a82182a41ab"), pdCbor)
+
+		hash, err := pd.Hash()
+		require.NoError(t, err)
+
+		expectedHash := blake2b.Sum256(pdCbor)
+		assert.Equal(t, hex.EncodeToString(expectedHash[:]), hash)
+
+		// original encoding is kept for nested plutus data too
+		pdCbor, err = NewPlutusDataList(pd).MarshalCBOR()
+		require.NoError(t, err)
+
+		assert.Equal(t, mustDecodeHex(t, "9fd87a82182a41abff"), pdCbor)
+
+		// modified plutus data is encoded the same way as cardano node does
+		pd.Fields[0] = NewPlutusDataInt(43)
+
+		pdCbor, err = pd.MarshalCBOR()
+		require.NoError(t, err)
+
+		assert.Equal(t, mustDecodeHex(t, "d87a9f182b41abff"), pdCbor)
+	})
+
+	t.Run("hash", func(t *testing.T) {
+		t.Parallel()
+
+		hash, err := NewPlutusDataConstr(0).Hash()
+		require.NoError(t, err)
+
+		assert.Equal(t, "923918e403bf43c34b4ef6b48eb2ee04babed17320d8d1b9ff9ad086e86f44ec", hash)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewPlutusDataFromCbor(mustDecodeHex(t, "d9010280"))
+		require.ErrorIs(t, err, ErrInvalidPlutusData)
+
+		_, err = NewPlutusDataFromCbor(mustDecodeHex(t, "f5"))
+		require.ErrorIs(t, err, ErrInvalidPlutusData)
+
+		_, err = PlutusData{Type: PlutusDataIntType}.MarshalCBOR()
+		require.ErrorIs(t, err, ErrInvalidPlutusData)
+	})
+}
+
+func TestPlutusData_JSON(t *testing.T) {
+	t.Parallel()
+
+	const detailedSchema = `{"constructor":1,"fields":[` +
+		`{"int":-18446744073709551617},` +
+		`{"bytes":"ab"},` +
+		`{"list":[{"constructor":0,"fields":[]}]},` +
+		`{"map":[{"k":{"int":1},"v":{"bytes":""}}]}]}`
+
+	pd, err := NewPlutusDataFromJSON([]byte(detailedSchema))
+	require.NoError(t, err)
+
+	cborBytes, err := pd.MarshalCBOR()
+	require.NoError(t, err)
+
+	assert.Equal(t, "d87a9fc34901000000000000000041ab9fd87980ffa10140ff", hex.EncodeToString(cborBytes))
+
+	jsonBytes, err := json.Marshal(pd)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, detailedSchema, string(jsonBytes))
+
+	for _, invalid := range []string{`{"int":1,"bytes":""}`, `{"bytes":"xy"}`, `{"map":[{"k":{"int":1}}]}`, `{}`} {
+		_, err := NewPlutusDataFromJSON([]byte(invalid))
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestMarshalPlutusData(t *testing.T) {
+	t.Parallel()
+
+	type credential struct {
+		_       struct{} `plutus:"constr=1"`
+		KeyHash string   `plutus:"hex"`
+	}
+
+	type datum struct {
+		Owner    credential
+		Deadline uint64
+		Amount   *big.Int
+		Tags     []string
+		Weights  map[string]int32
+		IsActive bool
+		Note     string `plutus:"-"`
+		hidden   int
+	}
+
+	value := datum{
+		Owner:    credential{KeyHash: "2411b7ef3c33d35c2f1e1a2fa4ef0a63e1aa5fe9ac9cd8ca4d1c1b09"},
+		Deadline: 1_700_000_000,
+		Amount:   big.NewInt(-5),
+		Tags:     []string{"a", "b"},
+		Weights:  map[string]int32{"y": 2, "x": 1},
+		IsActive: true,
+		Note:     "not serialized",
+		hidden:   1,
+	}
+
+	pd, err := MarshalPlutusData(value)
+	require.NoError(t, err)
+
+	jsonBytes, err := json.Marshal(pd)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"constructor":0,"fields":[`+
+		`{"constructor":1,"fields":[{"bytes":"2411b7ef3c33d35c2f1e1a2fa4ef0a63e1aa5fe9ac9cd8ca4d1c1b09"}]},`+
+		`{"int":1700000000},`+
+		`{"int":-5},`+
+		`{"list":[{"bytes":"61"},{"bytes":"62"}]},`+
+		`{"map":[{"k":{"bytes":"78"},"v":{"int":1}},{"k":{"bytes":"79"},"v":{"int":2}}]},`+
+		`{"constructor":1,"fields":[]}]}`, string(jsonBytes))
+
+	var result datum
+
+	require.NoError(t, UnmarshalPlutusData(pd, &result))
+
+	assert.Empty(t, result.Note)
+	assert.Zero(t, result.hidden)
+	assert.Equal(t, value.Owner, result.Owner)
+	assert.Equal(t, value.Deadline, result.Deadline)
+	assert.Equal(t, 0, value.Amount.Cmp(result.Amount))
+	assert.Equal(t, value.Tags, result.Tags)
+	assert.Equal(t, value.Weights, result.Weights)
+	assert.Equal(t, value.IsActive, result.IsActive)
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		var cred credential
+
+		require.ErrorIs(t, UnmarshalPlutusData(NewPlutusDataConstr(0, NewPlutusDataBytes(nil)), &cred), ErrInvalidPlutusData)
+		require.ErrorIs(t, UnmarshalPlutusData(NewPlutusDataConstr(1), &cred), ErrInvalidPlutusData)
+		require.ErrorIs(t, UnmarshalPlutusData(NewPlutusDataConstr(1), cred), ErrInvalidPlutusData)
+
+		var small int8
+
+		require.ErrorIs(t, UnmarshalPlutusData(NewPlutusDataInt(300), &small), ErrInvalidPlutusData)
+
+		_, err := MarshalPlutusData(struct{ Value float64 }{})
+		require.ErrorIs(t, err, ErrInvalidPlutusData)
+
+		_, err = MarshalPlutusData(struct{ Value *big.Int }{})
+		require.ErrorIs(t, err, ErrInvalidPlutusData)
+	})
+}
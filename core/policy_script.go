@@ -1,14 +1,42 @@
 package core
 
 import (
+	"bytes"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"sort"
+
+	"github.com/fxamacker/cbor/v2"
 )
 
 const (
 	PolicyScriptAtLeastType = "atLeast"
 	PolicyScriptSigType     = "sig"
+	PolicyScriptAllType     = "all"
+	PolicyScriptAnyType     = "any"
+	PolicyScriptAfterType   = "after"
+	PolicyScriptBeforeType  = "before"
+)
+
+var (
+	ErrPolicyScriptNotSatisfiable = errors.New("policy script can not be satisfied in validity interval")
+	ErrUnknownPolicyScriptType    = errors.New("unknown policy script type")
+	ErrInvalidPolicyScript        = errors.New("invalid policy script")
+)
+
+// native script hash is calculated over script cbor prefixed with this byte
+const nativeScriptHashPrefix = 0
+
+// native script cbor tags
+const (
+	nativeScriptSigTag = iota
+	nativeScriptAllTag
+	nativeScriptAnyTag
+	nativeScriptAtLeastTag
+	nativeScriptAfterTag
+	nativeScriptBeforeTag
 )
 
 type PolicyScript struct {
@@ -19,6 +47,15 @@ type PolicyScript struct {
 
 	KeyHash string `json:"keyHash,omitempty"`
 	Slot    uint64 `json:"slot,omitempty"`
+
+	// original is set if script is decoded from non canonical cbor
+	original *nativeScriptCbor
+}
+
+// nativeScriptCbor is original native script cbor and its canonical encoding
+type nativeScriptCbor struct {
+	raw       []byte
+	canonical []byte
 }
 
 func NewPolicyScript(keyHashes []string, atLeastSignersCount int) *PolicyScript {
@@ -41,29 +78,577 @@ func NewPolicyScript(keyHashes []string, atLeastSignersCount int) *PolicyScript
 	}
 }
 
+// NewPolicyScriptSig returns script which requires signature of the key with keyHash
+func NewPolicyScriptSig(keyHash string) PolicyScript {
+	return PolicyScript{
+		Type:    PolicyScriptSigType,
+		KeyHash: keyHash,
+	}
+}
+
+// NewPolicyScriptAll returns script which requires all sub scripts to be satisfied
+func NewPolicyScriptAll(scripts ...PolicyScript) PolicyScript {
+	return PolicyScript{
+		Type:    PolicyScriptAllType,
+		Scripts: scripts,
+	}
+}
+
+// NewPolicyScriptAny returns script which requires any of sub scripts to be satisfied
+func NewPolicyScriptAny(scripts ...PolicyScript) PolicyScript {
+	return PolicyScript{
+		Type:    PolicyScriptAnyType,
+		Scripts: scripts,
+	}
+}
+
+// NewPolicyScriptAtLeast returns script which requires at least required sub scripts to be satisfied
+func NewPolicyScriptAtLeast(required int, scripts ...PolicyScript) PolicyScript {
+	return PolicyScript{
+		Type:     PolicyScriptAtLeastType,
+		Required: required,
+		Scripts:  scripts,
+	}
+}
+
+// NewPolicyScriptAfter returns script which is satisfied if transaction is valid only from slot (invalid-before >= slot)
+func NewPolicyScriptAfter(slot uint64) PolicyScript {
+	return PolicyScript{
+		Type: PolicyScriptAfterType,
+		Slot: slot,
+	}
+}
+
+// NewPolicyScriptBefore returns script which is satisfied if transaction is valid only before slot
+// (invalid-hereafter <= slot)
+func NewPolicyScriptBefore(slot uint64) PolicyScript {
+	return PolicyScript{
+		Type: PolicyScriptBeforeType,
+		Slot: slot,
+	}
+}
+
+// NewPolicyScriptFromJSON parses and validates policy script in cardano-cli simple script json format
+func NewPolicyScriptFromJSON(data []byte) (*PolicyScript, error) {
+	var ps PolicyScript
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&ps); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPolicyScript, err)
+	} else if decoder.More() {
+		return nil, fmt.Errorf("%w: unexpected data after script", ErrInvalidPolicyScript)
+	}
+
+	if err := ps.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &ps, nil
+}
+
+// NewPolicyScriptFromCBOR parses and validates native script cbor (from witness set or reference script).
+// Any valid encoding is accepted. Original bytes are kept so MarshalCBOR and PolicyID
+// return them (and their hash) as long as the script is not modified
+func NewPolicyScriptFromCBOR(data []byte) (*PolicyScript, error) {
+	var ps PolicyScript
+
+	if err := ps.UnmarshalCBOR(data); err != nil {
+		return nil, err
+	}
+
+	if err := ps.Validate(); err != nil {
+		return nil, err
+	}
+
+	scriptBytes, err := ps.MarshalCBOR()
+	if err != nil {
+		return nil, err
+	} else if !bytes.Equal(scriptBytes, data) {
+		ps.original = &nativeScriptCbor{
+			raw:       bytes.Clone(data),
+			canonical: scriptBytes,
+		}
+	}
+
+	return &ps, nil
+}
+
+// Validate checks types and fields of policy script and all its sub scripts
+func (ps PolicyScript) Validate() error {
+	return ps.validate("$")
+}
+
+func (ps PolicyScript) validate(path string) error {
+	switch ps.Type {
+	case PolicyScriptSigType:
+		if keyHash, err := hex.DecodeString(ps.KeyHash); err != nil || len(keyHash) != KeyHashSize {
+			return fmt.Errorf("%w: %s: invalid key hash: %s", ErrInvalidPolicyScript, path, ps.KeyHash)
+		}
+	case PolicyScriptAtLeastType:
+		if ps.Required < 0 || ps.Required > len(ps.Scripts) {
+			return fmt.Errorf("%w: %s: required %d is not in range [0, %d]",
+				ErrInvalidPolicyScript, path, ps.Required, len(ps.Scripts))
+		}
+	case PolicyScriptAllType, PolicyScriptAnyType, PolicyScriptAfterType, PolicyScriptBeforeType:
+	default:
+		return fmt.Errorf("%w: %s: %s", ErrUnknownPolicyScriptType, path, ps.Type)
+	}
+
+	switch ps.Type {
+	case PolicyScriptSigType, PolicyScriptAfterType, PolicyScriptBeforeType:
+		if len(ps.Scripts) > 0 {
+			return fmt.Errorf("%w: %s: %s script can not have sub scripts", ErrInvalidPolicyScript, path, ps.Type)
+		}
+	}
+
+	if ps.KeyHash != "" && ps.Type != PolicyScriptSigType {
+		return fmt.Errorf("%w: %s: %s script can not have key hash", ErrInvalidPolicyScript, path, ps.Type)
+	}
+
+	if ps.Slot != 0 && ps.Type != PolicyScriptAfterType && ps.Type != PolicyScriptBeforeType {
+		return fmt.Errorf("%w: %s: %s script can not have slot", ErrInvalidPolicyScript, path, ps.Type)
+	}
+
+	if ps.Required != 0 && ps.Type != PolicyScriptAtLeastType {
+		return fmt.Errorf("%w: %s: %s script can not have required", ErrInvalidPolicyScript, path, ps.Type)
+	}
+
+	for i, x := range ps.Scripts {
+		if err := x.validate(fmt.Sprintf("%s.scripts[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (ps PolicyScript) GetPolicyScriptJSON() ([]byte, error) {
 	return json.MarshalIndent(ps, "", "  ")
 }
 
+// GetCount returns worst case witness count (all sub scripts of atLeast are counted).
+// Fee estimation intentionally keeps using it because any of the satisfying signer sets may sign the tx,
+// so the fee must cover the largest one. If signers are known, witness count of MinimalSigners
+// can be passed to TxBuilder.CalculateFee instead
 func (ps PolicyScript) GetCount() (cnt int) {
 	switch ps.Type {
 	case PolicyScriptSigType:
 		cnt = 1
-	case "any":
+	case PolicyScriptAnyType:
 		for _, x := range ps.Scripts {
 			if subCnt := x.GetCount(); cnt < subCnt {
 				cnt = subCnt
 			}
 		}
-	case "all", PolicyScriptAtLeastType:
+	case PolicyScriptAllType, PolicyScriptAtLeastType:
 		for _, x := range ps.Scripts {
 			cnt += x.GetCount()
 		}
+	case PolicyScriptAfterType, PolicyScriptBeforeType:
+		// time locks are checked against validity interval and do not require witnesses
+		cnt = 0
 	}
 
 	return cnt
 }
 
+// GetValidityInterval narrows validity interval [validityStart, timeToLive) so time locks of the policy script
+// are satisfied. Zero validityStart or timeToLive means that bound is not set.
+// All feasible sub intervals are considered and the first one found (in order of sub scripts) is returned.
+// Third return value is false if policy script can not be satisfied in any sub interval
+func (ps PolicyScript) GetValidityInterval(validityStart, timeToLive uint64) (uint64, uint64, bool) {
+	intervals := ps.getValidityIntervals(validityInterval{start: validityStart, ttl: timeToLive})
+	if len(intervals) == 0 {
+		return validityStart, timeToLive, false
+	}
+
+	return intervals[0].start, intervals[0].ttl, true
+}
+
+// validityInterval is [start, ttl) interval where zero start or ttl means that bound is not set
+type validityInterval struct {
+	start uint64
+	ttl   uint64
+}
+
+func (vi validityInterval) isEmpty() bool {
+	return vi.ttl > 0 && vi.start >= vi.ttl
+}
+
+// contains returns true if other interval is sub interval of this one
+func (vi validityInterval) contains(other validityInterval) bool {
+	return vi.start <= other.start && (vi.ttl == 0 || (other.ttl > 0 && other.ttl <= vi.ttl))
+}
+
+// addValidityInterval adds interval to the list unless some interval of the list contains it.
+// Intervals contained in the new one are removed because narrowing a wider interval is always possible
+// if narrowing a narrower one is
+func addValidityInterval(intervals []validityInterval, interval validityInterval) []validityInterval {
+	result := intervals[:0]
+
+	for _, x := range intervals {
+		if x.contains(interval) {
+			return intervals
+		}
+
+		if !interval.contains(x) {
+			result = append(result, x)
+		}
+	}
+
+	return append(result, interval)
+}
+
+// getValidityIntervals returns all maximal sub intervals of the interval in which the policy script is satisfied
+func (ps PolicyScript) getValidityIntervals(interval validityInterval) []validityInterval {
+	switch ps.Type {
+	case PolicyScriptSigType:
+		return []validityInterval{interval}
+	case PolicyScriptAfterType:
+		interval.start = max(interval.start, ps.Slot)
+		if interval.start == 0 || interval.isEmpty() {
+			return nil
+		}
+
+		return []validityInterval{interval}
+	case PolicyScriptBeforeType:
+		if interval.ttl == 0 || ps.Slot < interval.ttl {
+			interval.ttl = ps.Slot
+		}
+
+		if interval.ttl == 0 || interval.isEmpty() {
+			return nil
+		}
+
+		return []validityInterval{interval}
+	case PolicyScriptAllType, PolicyScriptAnyType, PolicyScriptAtLeastType:
+		required := ps.Required
+
+		switch ps.Type {
+		case PolicyScriptAllType:
+			required = len(ps.Scripts)
+		case PolicyScriptAnyType:
+			required = 1
+		}
+
+		if required > len(ps.Scripts) {
+			return nil
+		}
+
+		// reachable[j] are intervals in which j sub scripts (or at least required if j == required) are satisfied
+		reachable := make([][]validityInterval, max(required, 0)+1)
+		reachable[0] = []validityInterval{interval}
+
+		for _, x := range ps.Scripts {
+			for j := len(reachable) - 1; j >= 0; j-- {
+				for _, current := range reachable[j] {
+					for _, narrowed := range x.getValidityIntervals(current) {
+						reachable[min(j+1, required)] = addValidityInterval(reachable[min(j+1, required)], narrowed)
+					}
+				}
+			}
+		}
+
+		return reachable[len(reachable)-1]
+	default:
+		return nil
+	}
+}
+
+// GetCountInInterval returns witness count like GetCount but only sub scripts which time locks can be satisfied
+// in the transaction validity interval are counted. Zero validityStart or timeToLive means that bound is not set.
+// Second return value is false if policy script can not be satisfied in the interval
+func (ps PolicyScript) GetCountInInterval(validityStart, timeToLive uint64) (cnt int, ok bool) {
+	switch ps.Type {
+	case PolicyScriptSigType:
+		return 1, true
+	case PolicyScriptAfterType:
+		return 0, validityStart > 0 && ps.Slot <= validityStart
+	case PolicyScriptBeforeType:
+		return 0, timeToLive > 0 && timeToLive <= ps.Slot
+	case PolicyScriptAllType:
+		for _, x := range ps.Scripts {
+			subCnt, subOk := x.GetCountInInterval(validityStart, timeToLive)
+			if !subOk {
+				return 0, false
+			}
+
+			cnt += subCnt
+		}
+
+		return cnt, true
+	case PolicyScriptAnyType:
+		for _, x := range ps.Scripts {
+			if subCnt, subOk := x.GetCountInInterval(validityStart, timeToLive); subOk {
+				cnt = max(cnt, subCnt)
+				ok = true
+			}
+		}
+
+		return cnt, ok
+	case PolicyScriptAtLeastType:
+		satisfiedCnt := 0
+
+		for _, x := range ps.Scripts {
+			if subCnt, subOk := x.GetCountInInterval(validityStart, timeToLive); subOk {
+				cnt += subCnt
+				satisfiedCnt++
+			}
+		}
+
+		return cnt, satisfiedCnt >= ps.Required
+	default:
+		return 0, false
+	}
+}
+
+// MarshalCBOR encodes policy script as cardano native script.
+// Original cbor of the script decoded with NewPolicyScriptFromCBOR is returned if script is not modified
+func (ps PolicyScript) MarshalCBOR() ([]byte, error) {
+	data, err := ps.getNativeScriptData()
+	if err != nil {
+		return nil, err
+	}
+
+	scriptBytes, err := cbor.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if ps.original != nil && bytes.Equal(ps.original.canonical, scriptBytes) {
+		return bytes.Clone(ps.original.raw), nil
+	}
+
+	return scriptBytes, nil
+}
+
+// UnmarshalCBOR decodes cardano native script into policy script
+func (ps *PolicyScript) UnmarshalCBOR(data []byte) error {
+	var fields []cbor.RawMessage
+
+	if err := cbor.Unmarshal(data, &fields); err != nil {
+		return err
+	} else if len(fields) < 2 {
+		return fmt.Errorf("invalid native script: expected at least 2 elements, got %d", len(fields))
+	}
+
+	var tag int
+
+	if err := cbor.Unmarshal(fields[0], &tag); err != nil {
+		return err
+	}
+
+	expectedLength := 2
+	result := PolicyScript{}
+
+	switch tag {
+	case nativeScriptSigTag:
+		var keyHash []byte
+
+		if err := cbor.Unmarshal(fields[1], &keyHash); err != nil {
+			return err
+		}
+
+		result.Type = PolicyScriptSigType
+		result.KeyHash = hex.EncodeToString(keyHash)
+	case nativeScriptAllTag, nativeScriptAnyTag:
+		if err := cbor.Unmarshal(fields[1], &result.Scripts); err != nil {
+			return err
+		}
+
+		result.Type = PolicyScriptAllType
+		if tag == nativeScriptAnyTag {
+			result.Type = PolicyScriptAnyType
+		}
+	case nativeScriptAtLeastTag:
+		expectedLength = 3
+
+		if len(fields) != expectedLength {
+			break
+		}
+
+		if err := cbor.Unmarshal(fields[1], &result.Required); err != nil {
+			return err
+		}
+
+		if err := cbor.Unmarshal(fields[2], &result.Scripts); err != nil {
+			return err
+		}
+
+		result.Type = PolicyScriptAtLeastType
+	case nativeScriptAfterTag, nativeScriptBeforeTag:
+		if err := cbor.Unmarshal(fields[1], &result.Slot); err != nil {
+			return err
+		}
+
+		result.Type = PolicyScriptAfterType
+		if tag == nativeScriptBeforeTag {
+			result.Type = PolicyScriptBeforeType
+		}
+	default:
+		return fmt.Errorf("%w: native script tag %d", ErrUnknownPolicyScriptType, tag)
+	}
+
+	if len(fields) != expectedLength {
+		return fmt.Errorf("invalid native script: expected %d elements, got %d", expectedLength, len(fields))
+	}
+
+	*ps = result
+
+	return nil
+}
+
+// PolicyID returns policy id (script hash) of this policy script
+func (ps PolicyScript) PolicyID() (string, error) {
+	scriptBytes, err := ps.MarshalCBOR()
+	if err != nil {
+		return "", err
+	}
+
+	return getNativeScriptHash(scriptBytes)
+}
+
+func (ps PolicyScript) getNativeScriptData() ([]interface{}, error) {
+	getScriptsData := func() ([]interface{}, error) {
+		scripts := make([]interface{}, len(ps.Scripts))
+
+		for i, script := range ps.Scripts {
+			data, err := script.getNativeScriptData()
+			if err != nil {
+				return nil, err
+			}
+
+			scripts[i] = data
+		}
+
+		return scripts, nil
+	}
+
+	switch ps.Type {
+	case PolicyScriptSigType:
+		keyHash, err := hex.DecodeString(ps.KeyHash)
+		if err != nil {
+			return nil, err
+		} else if len(keyHash) != KeyHashSize {
+			return nil, fmt.Errorf("invalid key hash: %s", ps.KeyHash)
+		}
+
+		return []interface{}{nativeScriptSigTag, keyHash}, nil
+	case PolicyScriptAllType, PolicyScriptAnyType:
+		scripts, err := getScriptsData()
+		if err != nil {
+			return nil, err
+		}
+
+		if ps.Type == PolicyScriptAllType {
+			return []interface{}{nativeScriptAllTag, scripts}, nil
+		}
+
+		return []interface{}{nativeScriptAnyTag, scripts}, nil
+	case PolicyScriptAtLeastType:
+		scripts, err := getScriptsData()
+		if err != nil {
+			return nil, err
+		}
+
+		return []interface{}{nativeScriptAtLeastTag, ps.Required, scripts}, nil
+	case PolicyScriptAfterType:
+		return []interface{}{nativeScriptAfterTag, ps.Slot}, nil
+	case PolicyScriptBeforeType:
+		return []interface{}{nativeScriptBeforeTag, ps.Slot}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPolicyScriptType, ps.Type)
+	}
+}
+
+// getPolicyScriptCbor returns native script cbor for policy script
+func getPolicyScriptCbor(policyScript IPolicyScript) ([]byte, error) {
+	ps, err := toPolicyScript(policyScript)
+	if err != nil {
+		return nil, err
+	}
+
+	return ps.MarshalCBOR()
+}
+
+// getPolicyScriptCountInInterval returns witness count of policy script in the transaction validity interval
+func getPolicyScriptCountInInterval(policyScript IPolicyScript, validityStart, timeToLive uint64) (int, error) {
+	ps, err := toPolicyScript(policyScript)
+	if err != nil {
+		return 0, err
+	}
+
+	cnt, ok := ps.GetCountInInterval(validityStart, timeToLive)
+	if !ok {
+		return 0, fmt.Errorf("%w: [%d, %d)", ErrPolicyScriptNotSatisfiable, validityStart, timeToLive)
+	}
+
+	return cnt, nil
+}
+
+// getPolicyScriptValidityInterval returns validity interval narrowed by time locks of policy script
+func getPolicyScriptValidityInterval(
+	policyScript IPolicyScript, validityStart, timeToLive uint64,
+) (uint64, uint64, error) {
+	ps, err := toPolicyScript(policyScript)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	newValidityStart, newTimeToLive, ok := ps.GetValidityInterval(validityStart, timeToLive)
+	if !ok {
+		return 0, 0, fmt.Errorf("%w: [%d, %d)", ErrPolicyScriptNotSatisfiable, validityStart, timeToLive)
+	}
+
+	return newValidityStart, newTimeToLive, nil
+}
+
+func toPolicyScript(policyScript IPolicyScript) (ps PolicyScript, err error) {
+	switch script := policyScript.(type) {
+	case *PolicyScript:
+		return *script, nil
+	case PolicyScript:
+		return script, nil
+	default:
+		policyScriptJSON, err := policyScript.GetPolicyScriptJSON()
+		if err != nil {
+			return ps, err
+		}
+
+		err = json.Unmarshal(policyScriptJSON, &ps)
+
+		return ps, err
+	}
+}
+
+// getNativeScriptHash returns hash of native script cbor (blake2b-224 of 0x00 tagged script)
+func getNativeScriptHash(scriptBytes []byte) (string, error) {
+	return GetKeyHash(append([]byte{nativeScriptHashPrefix}, scriptBytes...))
+}
+
+// NewPolicyScriptAddressFromScript returns address for policy script (and optional stake policy script)
+func NewPolicyScriptAddressFromScript(
+	networkID CardanoNetworkType, policyScript *PolicyScript, policyScriptStake ...*PolicyScript,
+) (*CardanoAddress, error) {
+	policyID, err := policyScript.PolicyID()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(policyScriptStake) == 0 {
+		return NewPolicyScriptAddress(networkID, policyID)
+	}
+
+	policyIDStake, err := policyScriptStake[0].PolicyID()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPolicyScriptAddress(networkID, policyID, policyIDStake)
+}
+
 // GetAddress returns address for this policy script
 func NewPolicyScriptAddress(
 	networkID CardanoNetworkType, policyID string, policyIDStake ...string,
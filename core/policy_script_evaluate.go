@@ -0,0 +1,341 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PolicyScriptFailure describes policy script (or sub script) which is not satisfied
+type PolicyScriptFailure struct {
+	// Path of the sub script, e.g. $.scripts[1].scripts[0]
+	Path   string `json:"path"`
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+}
+
+func (f PolicyScriptFailure) String() string {
+	return fmt.Sprintf("%s (%s): %s", f.Path, f.Type, f.Reason)
+}
+
+// PolicyScriptEvaluation is result of offline policy script evaluation
+type PolicyScriptEvaluation struct {
+	Satisfied bool `json:"satisfied"`
+	// Failures contains not satisfied sub scripts of not satisfied branches (parents are after their sub scripts)
+	Failures []PolicyScriptFailure `json:"failures,omitempty"`
+}
+
+// Evaluate checks if policy script is satisfied by signatures of signerKeyHashes in the transaction
+// validity interval [validityStart, validityEnd). Zero validityStart or validityEnd means that bound is not set
+func (ps PolicyScript) Evaluate(signerKeyHashes []string, validityStart, validityEnd uint64) PolicyScriptEvaluation {
+	signers := make(map[string]bool, len(signerKeyHashes))
+	for _, keyHash := range signerKeyHashes {
+		signers[strings.ToLower(keyHash)] = true
+	}
+
+	var evaluation PolicyScriptEvaluation
+
+	evaluation.Satisfied, evaluation.Failures = ps.evaluate("$", signers, validityStart, validityEnd)
+
+	return evaluation
+}
+
+// MinimalSigners returns all minimal (no key hash can be removed) sets of key hashes which satisfy policy script
+// in validity interval [validityStart, validityEnd). Sets are sorted by size so the first ones are the smallest.
+// Result is empty if policy script can not be satisfied in the interval
+func (ps PolicyScript) MinimalSigners(validityStart, validityEnd uint64) [][]string {
+	signers := ps.minimalSigners(validityStart, validityEnd)
+
+	sort.SliceStable(signers, func(i, j int) bool {
+		if len(signers[i]) != len(signers[j]) {
+			return len(signers[i]) < len(signers[j])
+		}
+
+		return strings.Join(signers[i], ",") < strings.Join(signers[j], ",")
+	})
+
+	return signers
+}
+
+func (ps PolicyScript) evaluate(
+	path string, signers map[string]bool, validityStart, validityEnd uint64,
+) (bool, []PolicyScriptFailure) {
+	fail := func(format string, args ...interface{}) []PolicyScriptFailure {
+		return []PolicyScriptFailure{{Path: path, Type: ps.Type, Reason: fmt.Sprintf(format, args...)}}
+	}
+
+	switch ps.Type {
+	case PolicyScriptSigType:
+		if !signers[strings.ToLower(ps.KeyHash)] {
+			return false, fail("missing signature of %s", ps.KeyHash)
+		}
+
+		return true, nil
+	case PolicyScriptAfterType:
+		if validityStart == 0 {
+			return false, fail("validity start is not set, required at least %d", ps.Slot)
+		} else if validityStart < ps.Slot {
+			return false, fail("validity start %d is before slot %d", validityStart, ps.Slot)
+		}
+
+		return true, nil
+	case PolicyScriptBeforeType:
+		if validityEnd == 0 {
+			return false, fail("validity end is not set, required at most %d", ps.Slot)
+		} else if validityEnd > ps.Slot {
+			return false, fail("validity end %d is after slot %d", validityEnd, ps.Slot)
+		}
+
+		return true, nil
+	case PolicyScriptAllType, PolicyScriptAnyType, PolicyScriptAtLeastType:
+		var (
+			failures     []PolicyScriptFailure
+			satisfiedCnt int
+		)
+
+		for i, x := range ps.Scripts {
+			ok, subFailures := x.evaluate(
+				fmt.Sprintf("%s.scripts[%d]", path, i), signers, validityStart, validityEnd)
+			if ok {
+				satisfiedCnt++
+			}
+
+			failures = append(failures, subFailures...)
+		}
+
+		required := ps.Required
+
+		switch ps.Type {
+		case PolicyScriptAllType:
+			required = len(ps.Scripts)
+		case PolicyScriptAnyType:
+			required = 1
+		}
+
+		if satisfiedCnt >= required {
+			return true, nil
+		}
+
+		return false, append(failures, fail("%d of required %d sub scripts satisfied", satisfiedCnt, required)...)
+	default:
+		return false, fail("unknown policy script type")
+	}
+}
+
+func (ps PolicyScript) minimalSigners(validityStart, validityEnd uint64) [][]string {
+	switch ps.Type {
+	case PolicyScriptSigType:
+		return [][]string{{strings.ToLower(ps.KeyHash)}}
+	case PolicyScriptAfterType, PolicyScriptBeforeType:
+		if _, ok := ps.GetCountInInterval(validityStart, validityEnd); ok {
+			return [][]string{{}}
+		}
+
+		return nil
+	case PolicyScriptAllType:
+		return ps.combineMinimalSigners(len(ps.Scripts), validityStart, validityEnd)
+	case PolicyScriptAnyType:
+		return ps.combineMinimalSigners(1, validityStart, validityEnd)
+	case PolicyScriptAtLeastType:
+		return ps.combineMinimalSigners(ps.Required, validityStart, validityEnd)
+	default:
+		return nil
+	}
+}
+
+// combineMinimalSigners returns minimal signers of every combination of required sub scripts
+func (ps PolicyScript) combineMinimalSigners(required int, validityStart, validityEnd uint64) [][]string {
+	if required <= 0 {
+		return [][]string{{}}
+	}
+
+	if keyHashes, ok := ps.getDistinctSigKeyHashes(); ok {
+		return combineKeyHashes(keyHashes, required)
+	}
+
+	subSigners := make([][][]string, len(ps.Scripts))
+	for i, x := range ps.Scripts {
+		subSigners[i] = x.minimalSigners(validityStart, validityEnd)
+	}
+
+	var (
+		result  [][]string
+		combine func(start, remaining int, current [][]string)
+	)
+
+	combine = func(start, remaining int, current [][]string) {
+		if remaining == 0 {
+			result = append(result, current...)
+
+			return
+		}
+
+		for i := start; i <= len(subSigners)-remaining; i++ {
+			if len(subSigners[i]) == 0 {
+				continue
+			}
+
+			next := make([][]string, 0, len(current)*len(subSigners[i]))
+
+			for _, a := range current {
+				for _, b := range subSigners[i] {
+					next = append(next, unionKeyHashes(a, b))
+				}
+			}
+
+			combine(i+1, remaining-1, removeDuplicateSigners(next))
+		}
+	}
+
+	combine(0, required, [][]string{{}})
+
+	return ps.removeNonMinimalSigners(removeDuplicateSigners(result), validityStart, validityEnd)
+}
+
+// unionKeyHashes returns sorted union of two sorted key hash sets
+func unionKeyHashes(a, b []string) []string {
+	result := make([]string, 0, len(a)+len(b))
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case j == len(b) || (i < len(a) && a[i] < b[j]):
+			result = append(result, a[i])
+			i++
+		case i == len(a) || b[j] < a[i]:
+			result = append(result, b[j])
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+
+	return result
+}
+
+// getDistinctSigKeyHashes returns sorted key hashes if all sub scripts are sig scripts with distinct key hashes
+func (ps PolicyScript) getDistinctSigKeyHashes() ([]string, bool) {
+	keyHashes := make([]string, len(ps.Scripts))
+
+	for i, x := range ps.Scripts {
+		if x.Type != PolicyScriptSigType {
+			return nil, false
+		}
+
+		keyHashes[i] = strings.ToLower(x.KeyHash)
+	}
+
+	sort.Strings(keyHashes)
+
+	for i := 1; i < len(keyHashes); i++ {
+		if keyHashes[i] == keyHashes[i-1] {
+			return nil, false
+		}
+	}
+
+	return keyHashes, true
+}
+
+// combineKeyHashes returns all combinations of required distinct key hashes. All of them are minimal
+func combineKeyHashes(keyHashes []string, required int) [][]string {
+	var (
+		result  [][]string
+		combine func(start int, current []string)
+	)
+
+	combine = func(start int, current []string) {
+		if len(current) == required {
+			result = append(result, append([]string(nil), current...))
+
+			return
+		}
+
+		for i := start; i <= len(keyHashes)-(required-len(current)); i++ {
+			combine(i+1, append(current, keyHashes[i]))
+		}
+	}
+
+	combine(0, make([]string, 0, required))
+
+	return result
+}
+
+// removeDuplicateSigners removes duplicate sets of key hashes
+func removeDuplicateSigners(signers [][]string) [][]string {
+	seen := make(map[string]bool, len(signers))
+	result := signers[:0]
+
+	for _, x := range signers {
+		if key := strings.Join(x, ","); !seen[key] {
+			seen[key] = true
+			result = append(result, x)
+		}
+	}
+
+	return result
+}
+
+// removeNonMinimalSigners removes sets which satisfy policy script after some key hash is removed from them.
+// Every set is checked on its own, so the cost is linear in the number of sets
+func (ps PolicyScript) removeNonMinimalSigners(
+	signers [][]string, validityStart, validityEnd uint64,
+) [][]string {
+	result := make([][]string, 0, len(signers))
+
+outer:
+	for _, x := range signers {
+		signersMap := make(map[string]bool, len(x))
+		for _, keyHash := range x {
+			signersMap[keyHash] = true
+		}
+
+		for _, keyHash := range x {
+			signersMap[keyHash] = false
+			satisfied := ps.isSatisfied(signersMap, validityStart, validityEnd)
+			signersMap[keyHash] = true
+
+			if satisfied {
+				continue outer
+			}
+		}
+
+		result = append(result, x)
+	}
+
+	return result
+}
+
+// isSatisfied is same as evaluate but without collecting failures
+func (ps PolicyScript) isSatisfied(signers map[string]bool, validityStart, validityEnd uint64) bool {
+	switch ps.Type {
+	case PolicyScriptSigType:
+		return signers[strings.ToLower(ps.KeyHash)]
+	case PolicyScriptAfterType:
+		return validityStart != 0 && validityStart >= ps.Slot
+	case PolicyScriptBeforeType:
+		return validityEnd != 0 && validityEnd <= ps.Slot
+	case PolicyScriptAllType, PolicyScriptAnyType, PolicyScriptAtLeastType:
+		required := ps.Required
+
+		switch ps.Type {
+		case PolicyScriptAllType:
+			required = len(ps.Scripts)
+		case PolicyScriptAnyType:
+			required = 1
+		}
+
+		satisfiedCnt := 0
+
+		for _, x := range ps.Scripts {
+			if x.isSatisfied(signers, validityStart, validityEnd) {
+				satisfiedCnt++
+			}
+		}
+
+		return satisfiedCnt >= required
+	default:
+		return false
+	}
+}
@@ -0,0 +1,120 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyScript_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	const (
+		key1 = "06b4c7f5254d6395b527ac3de60c1d77194df7431d85fe55ca8f107d"
+		key2 = "2368e8113bd5f32d713751791d29acee9e1b5a425b0454b963b2558b"
+		key3 = "79df3577e4c7d7da04872c2182b8d8829d7b477912dbf35d89287c39"
+		key4 = "d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21"
+	)
+
+	t.Run("multisig", func(t *testing.T) {
+		t.Parallel()
+
+		ps := NewPolicyScript([]string{key1, key2, key3}, 2)
+
+		evaluation := ps.Evaluate([]string{key1, key3, key4}, 0, 0)
+
+		assert.True(t, evaluation.Satisfied)
+		assert.Empty(t, evaluation.Failures)
+
+		evaluation = ps.Evaluate([]string{key2, key4}, 0, 0)
+
+		assert.False(t, evaluation.Satisfied)
+		require.Len(t, evaluation.Failures, 3)
+		assert.Equal(t, "$.scripts[0] (sig): missing signature of "+key1, evaluation.Failures[0].String())
+		assert.Equal(t, "$.scripts[2]", evaluation.Failures[1].Path)
+		assert.Equal(t, "$ (atLeast): 1 of required 2 sub scripts satisfied", evaluation.Failures[2].String())
+	})
+
+	t.Run("time locks", func(t *testing.T) {
+		t.Parallel()
+
+		ps := NewPolicyScriptAny(
+			NewPolicyScriptAll(NewPolicyScriptSig(key1), NewPolicyScriptBefore(200)),
+			NewPolicyScriptAll(NewPolicyScriptSig(key2), NewPolicyScriptAfter(300)),
+		)
+
+		assert.True(t, ps.Evaluate([]string{key1}, 0, 200).Satisfied)
+		assert.True(t, ps.Evaluate([]string{key2}, 300, 0).Satisfied)
+
+		evaluation := ps.Evaluate([]string{key1}, 250, 400)
+
+		assert.False(t, evaluation.Satisfied)
+		assert.Equal(t, []PolicyScriptFailure{
+			{Path: "$.scripts[0].scripts[1]", Type: PolicyScriptBeforeType, Reason: "validity end 400 is after slot 200"},
+			{Path: "$.scripts[0]", Type: PolicyScriptAllType, Reason: "1 of required 2 sub scripts satisfied"},
+			{Path: "$.scripts[1].scripts[0]", Type: PolicyScriptSigType, Reason: "missing signature of " + key2},
+			{Path: "$.scripts[1].scripts[1]", Type: PolicyScriptAfterType, Reason: "validity start 250 is before slot 300"},
+			{Path: "$.scripts[1]", Type: PolicyScriptAllType, Reason: "0 of required 2 sub scripts satisfied"},
+			{Path: "$", Type: PolicyScriptAnyType, Reason: "0 of required 1 sub scripts satisfied"},
+		}, evaluation.Failures)
+	})
+}
+
+func TestPolicyScript_MinimalSigners(t *testing.T) {
+	t.Parallel()
+
+	const (
+		key1 = "06b4c7f5254d6395b527ac3de60c1d77194df7431d85fe55ca8f107d"
+		key2 = "2368e8113bd5f32d713751791d29acee9e1b5a425b0454b963b2558b"
+		key3 = "79df3577e4c7d7da04872c2182b8d8829d7b477912dbf35d89287c39"
+	)
+
+	assert.Equal(t, [][]string{
+		{key1, key2}, {key1, key3}, {key2, key3},
+	}, NewPolicyScript([]string{key3, key1, key2}, 2).MinimalSigners(0, 0))
+
+	// key1 alone is enough after slot 100, otherwise key2 and key3 are needed
+	ps := NewPolicyScriptAny(
+		NewPolicyScriptAll(NewPolicyScriptSig(key1), NewPolicyScriptAfter(100)),
+		NewPolicyScriptAll(NewPolicyScriptSig(key2), NewPolicyScriptSig(key3)),
+		// superset of the previous one
+		NewPolicyScriptAll(NewPolicyScriptSig(key3), NewPolicyScriptSig(key2), NewPolicyScriptSig(key1)),
+	)
+
+	assert.Equal(t, [][]string{{key1}, {key2, key3}}, ps.MinimalSigners(150, 0))
+	assert.Equal(t, [][]string{{key2, key3}}, ps.MinimalSigners(0, 0))
+
+	for _, signers := range ps.MinimalSigners(150, 0) {
+		assert.True(t, ps.Evaluate(signers, 150, 0).Satisfied)
+	}
+
+	assert.Empty(t, NewPolicyScriptAll(NewPolicyScriptSig(key1), NewPolicyScriptBefore(50)).MinimalSigners(0, 100))
+	assert.Empty(t, NewPolicyScriptAtLeast(3, NewPolicyScriptSig(key1), NewPolicyScriptSig(key2)).MinimalSigners(0, 0))
+
+	t.Run("many keys", func(t *testing.T) {
+		t.Parallel()
+
+		keyHashes := make([]string, 20)
+		timeLocked := make([]PolicyScript, len(keyHashes))
+
+		for i := range keyHashes {
+			keyHashes[i] = fmt.Sprintf("%056x", i)
+			timeLocked[i] = NewPolicyScriptAll(NewPolicyScriptSig(keyHashes[i]), NewPolicyScriptAfter(100))
+		}
+
+		// C(20, 14)
+		signers := NewPolicyScript(keyHashes, 14).MinimalSigners(0, 0)
+
+		require.Len(t, signers, 38760)
+		assert.Equal(t, keyHashes[:14], signers[0])
+
+		// C(12, 8)
+		signers = NewPolicyScriptAtLeast(8, timeLocked[:12]...).MinimalSigners(150, 0)
+
+		require.Len(t, signers, 495)
+		assert.Equal(t, keyHashes[:8], signers[0])
+		assert.Empty(t, NewPolicyScriptAtLeast(8, timeLocked[:12]...).MinimalSigners(0, 0))
+	})
+}
@@ -129,3 +129,310 @@ func TestPolicyScript_SpecificKeysAllPermutations(t *testing.T) {
 		require.Equal(t, cliAddr, addr.String())
 	})
 }
+
+func TestPolicyScript_PolicyID(t *testing.T) {
+	t.Parallel()
+
+	ps := NewPolicyScript([]string{
+		"d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21",
+		"cba89c7084bf0ce4bf404346b668a7e83c8c9c250d1cafd8d8996e41",
+		"79df3577e4c7d7da04872c2182b8d8829d7b477912dbf35d89287c39",
+		"2368e8113bd5f32d713751791d29acee9e1b5a425b0454b963b2558b",
+		"06b4c7f5254d6395b527ac3de60c1d77194df7431d85fe55ca8f107d",
+	}, 4)
+	psStake := NewPolicyScript([]string{"d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21"}, 1)
+
+	scriptBytes, err := ps.MarshalCBOR()
+	require.NoError(t, err)
+
+	require.Equal(t, "830304858200581c06b4c7f5254d6395b527ac3de60c1d77194df7431d85fe55ca8f107d8200581c2368e8113bd5f32d713751791d29acee9e1b5a425b0454b963b2558b8200581c79df3577e4c7d7da04872c2182b8d8829d7b477912dbf35d89287c398200581ccba89c7084bf0ce4bf404346b668a7e83c8c9c250d1cafd8d8996e418200581cd6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21", hex.EncodeToString(scriptBytes))
+
+	policyID, err := ps.PolicyID()
+	require.NoError(t, err)
+
+	require.Equal(t, "4aaad0f0626a8ce7b097497e542055b6520842ade881f980e002ae66", policyID)
+
+	addr, err := NewPolicyScriptAddressFromScript(TestNetNetwork, ps)
+	require.NoError(t, err)
+
+	require.Equal(t, "addr_test1wp92458svf4geeasjayhu4pq2km9yzzz4h5gr7vquqp2uesxzlq29", addr.String())
+
+	addrStake, err := NewPolicyScriptAddressFromScript(MainNetNetwork, ps, psStake)
+	require.NoError(t, err)
+
+	require.Equal(t, "addr1x992458svf4geeasjayhu4pq2km9yzzz4h5gr7vquqp2uek4tjn8naz0rtft8h6g2f5j8m4j2g6pkzdpre40rrl8k82sy37tml", addrStake.String())
+
+	_, err = PolicyScript{Type: PolicyScriptSigType, KeyHash: "d6b67f"}.PolicyID()
+	require.Error(t, err)
+}
+
+func TestPolicyScript_CBOR(t *testing.T) {
+	t.Parallel()
+
+	ps := PolicyScript{
+		Type: PolicyScriptAllType,
+		Scripts: []PolicyScript{
+			*NewPolicyScript([]string{
+				"d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21",
+				"cba89c7084bf0ce4bf404346b668a7e83c8c9c250d1cafd8d8996e41",
+			}, 1),
+			{
+				Type: PolicyScriptAnyType,
+				Scripts: []PolicyScript{
+					{Type: PolicyScriptAfterType, Slot: 1000},
+					{Type: PolicyScriptBeforeType, Slot: 2000},
+				},
+			},
+		},
+	}
+
+	scriptBytes, err := ps.MarshalCBOR()
+	require.NoError(t, err)
+
+	var decoded PolicyScript
+
+	require.NoError(t, decoded.UnmarshalCBOR(scriptBytes))
+	require.Equal(t, ps, decoded)
+
+	require.Error(t, decoded.UnmarshalCBOR([]byte{0x82, 0x06, 0x01}))
+	require.Error(t, decoded.UnmarshalCBOR([]byte{0x82, 0x03, 0x01}))
+}
+
+func TestPolicyScript_GetCountInInterval(t *testing.T) {
+	t.Parallel()
+
+	sig := func(keyHash string) PolicyScript {
+		return PolicyScript{Type: PolicyScriptSigType, KeyHash: keyHash}
+	}
+
+	after := PolicyScript{Type: PolicyScriptAfterType, Slot: 100}
+	before := PolicyScript{Type: PolicyScriptBeforeType, Slot: 200}
+	timeLocked := PolicyScript{
+		Type:    PolicyScriptAllType,
+		Scripts: []PolicyScript{after, before, *NewPolicyScript([]string{"01", "02", "03"}, 2)},
+	}
+	fallback := PolicyScript{
+		Type: PolicyScriptAnyType,
+		Scripts: []PolicyScript{
+			{Type: PolicyScriptAllType, Scripts: []PolicyScript{sig("01"), sig("02"), before}},
+			{Type: PolicyScriptAllType, Scripts: []PolicyScript{sig("03"), after}},
+		},
+	}
+
+	cases := []struct {
+		script        PolicyScript
+		validityStart uint64
+		timeToLive    uint64
+		count         int
+		ok            bool
+	}{
+		{timeLocked, 0, 0, 0, false},
+		{timeLocked, 99, 150, 0, false},
+		{timeLocked, 100, 201, 0, false},
+		{timeLocked, 100, 200, 3, true},
+		{fallback, 0, 0, 0, false},
+		{fallback, 0, 150, 2, true},
+		{fallback, 150, 0, 1, true},
+		{fallback, 150, 160, 2, true},
+		{
+			PolicyScript{Type: PolicyScriptAtLeastType, Required: 2, Scripts: []PolicyScript{sig("01"), after, before}},
+			0, 200, 1, true,
+		},
+		{
+			PolicyScript{Type: PolicyScriptAtLeastType, Required: 2, Scripts: []PolicyScript{sig("01"), after, before}},
+			0, 300, 0, false,
+		},
+	}
+
+	for i, c := range cases {
+		count, ok := c.script.GetCountInInterval(c.validityStart, c.timeToLive)
+
+		require.Equal(t, c.ok, ok, "case %d", i)
+
+		if c.ok {
+			require.Equal(t, c.count, count, "case %d", i)
+		}
+	}
+}
+
+func TestPolicyScript_GetValidityInterval(t *testing.T) {
+	t.Parallel()
+
+	sig := NewPolicyScriptSig("d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21")
+	timeLocked := NewPolicyScriptAll(NewPolicyScriptAfter(100), NewPolicyScriptBefore(200), sig)
+	fallback := NewPolicyScriptAny(
+		NewPolicyScriptAll(sig, NewPolicyScriptBefore(200)),
+		NewPolicyScriptAll(sig, NewPolicyScriptAfter(300)),
+	)
+
+	require.Equal(t, 1, timeLocked.GetCount())
+
+	cases := []struct {
+		script        PolicyScript
+		validityStart uint64
+		timeToLive    uint64
+		expectedStart uint64
+		expectedTTL   uint64
+		ok            bool
+	}{
+		{sig, 0, 0, 0, 0, true},
+		{timeLocked, 0, 0, 100, 200, true},
+		{timeLocked, 150, 180, 150, 180, true},
+		{timeLocked, 0, 100, 0, 0, false},
+		{timeLocked, 200, 0, 0, 0, false},
+		{fallback, 0, 0, 0, 200, true},
+		{fallback, 0, 150, 0, 150, true},
+		// first sub script can not be satisfied so second is used
+		{fallback, 250, 0, 300, 0, true},
+		{fallback, 250, 280, 0, 0, false},
+		{NewPolicyScriptAtLeast(2, sig, NewPolicyScriptAfter(100), NewPolicyScriptBefore(50)), 0, 0, 100, 0, true},
+		// greedy narrowing by before(200) would make after(250) unsatisfiable
+		{NewPolicyScriptAll(
+			NewPolicyScriptAny(NewPolicyScriptBefore(200), NewPolicyScriptAfter(300)), NewPolicyScriptAfter(250),
+		), 0, 0, 300, 0, true},
+		{NewPolicyScriptAtLeast(3, sig, NewPolicyScriptAfter(100), NewPolicyScriptBefore(50)), 0, 0, 0, 0, false},
+	}
+
+	for i, c := range cases {
+		validityStart, timeToLive, ok := c.script.GetValidityInterval(c.validityStart, c.timeToLive)
+
+		require.Equal(t, c.ok, ok, "case %d", i)
+
+		if c.ok {
+			require.Equal(t, c.expectedStart, validityStart, "case %d", i)
+			require.Equal(t, c.expectedTTL, timeToLive, "case %d", i)
+
+			_, ok := c.script.GetCountInInterval(validityStart, timeToLive)
+			require.True(t, ok, "case %d", i)
+		}
+	}
+}
+
+func TestPolicyScript_Parse(t *testing.T) {
+	t.Parallel()
+
+	const (
+		key1 = "d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21"
+		key2 = "cba89c7084bf0ce4bf404346b668a7e83c8c9c250d1cafd8d8996e41"
+	)
+
+	t.Run("cardano-cli json", func(t *testing.T) {
+		t.Parallel()
+
+		ps, err := NewPolicyScriptFromJSON([]byte(`{
+			"type": "all",
+			"scripts": [
+				{"type": "atLeast", "required": 1, "scripts": [
+					{"type": "sig", "keyHash": "` + key1 + `"},
+					{"type": "sig", "keyHash": "` + key2 + `"}
+				]},
+				{"type": "any", "scripts": [{"type": "after", "slot": 1000}, {"type": "before", "slot": 2000}]}
+			]
+		}`))
+		require.NoError(t, err)
+
+		require.Equal(t, NewPolicyScriptAll(
+			NewPolicyScriptAtLeast(1, NewPolicyScriptSig(key1), NewPolicyScriptSig(key2)),
+			NewPolicyScriptAny(NewPolicyScriptAfter(1000), NewPolicyScriptBefore(2000)),
+		), *ps)
+
+		scriptJSON, err := ps.GetPolicyScriptJSON()
+		require.NoError(t, err)
+
+		psRoundTrip, err := NewPolicyScriptFromJSON(scriptJSON)
+		require.NoError(t, err)
+
+		require.Equal(t, ps, psRoundTrip)
+	})
+
+	t.Run("cbor", func(t *testing.T) {
+		t.Parallel()
+
+		ps := NewPolicyScriptAny(*NewPolicyScript([]string{key1, key2}, 2), NewPolicyScriptAfter(0))
+
+		scriptBytes, err := ps.MarshalCBOR()
+		require.NoError(t, err)
+
+		decoded, err := NewPolicyScriptFromCBOR(scriptBytes)
+		require.NoError(t, err)
+
+		require.Equal(t, ps, *decoded)
+
+		policyID, err := ps.PolicyID()
+		require.NoError(t, err)
+
+		decodedPolicyID, err := decoded.PolicyID()
+		require.NoError(t, err)
+
+		require.Equal(t, policyID, decodedPolicyID)
+
+		// indefinite length array is valid cbor and the policy id is the hash of the original bytes
+		scriptBytes = mustDecodeHex(t, "9f00581c"+key1+"ff")
+
+		decoded, err = NewPolicyScriptFromCBOR(scriptBytes)
+		require.NoError(t, err)
+
+		require.Equal(t, PolicyScriptSigType, decoded.Type)
+		require.Equal(t, key1, decoded.KeyHash)
+
+		expectedPolicyID, err := getNativeScriptHash(scriptBytes)
+		require.NoError(t, err)
+
+		decodedPolicyID, err = decoded.PolicyID()
+		require.NoError(t, err)
+
+		require.Equal(t, expectedPolicyID, decodedPolicyID)
+
+		decodedBytes, err := getPolicyScriptCbor(decoded)
+		require.NoError(t, err)
+
+		require.Equal(t, scriptBytes, decodedBytes)
+
+		// modified script is encoded canonically
+		decoded.KeyHash = key2
+
+		decodedBytes, err = decoded.MarshalCBOR()
+		require.NoError(t, err)
+
+		require.Equal(t, mustDecodeHex(t, "8200581c"+key2), decodedBytes)
+
+		_, err = NewPolicyScriptFromCBOR(mustDecodeHex(t, "9f0500"))
+		require.Error(t, err)
+
+		_, err = NewPolicyScriptFromCBOR(mustDecodeHex(t, "820901"))
+		require.ErrorIs(t, err, ErrUnknownPolicyScriptType)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		cases := []struct {
+			json string
+			err  error
+			msg  string
+		}{
+			{`{"type": "any", "scripts": [{"type": "signature", "keyHash": "` + key1 + `"}]}`,
+				ErrUnknownPolicyScriptType, "$.scripts[0]: signature"},
+			{`{"type": "sig", "keyHash": "d6b67f"}`, ErrInvalidPolicyScript, "$: invalid key hash"},
+			{`{"type": "atLeast", "required": 2, "scripts": [{"type": "after", "slot": 1}]}`,
+				ErrInvalidPolicyScript, "required 2 is not in range [0, 1]"},
+			{`{"type": "before", "slot": 1, "scripts": [{"type": "after", "slot": 1}]}`,
+				ErrInvalidPolicyScript, "can not have sub scripts"},
+			{`{"type": "all", "keyHash": "` + key1 + `", "scripts": []}`,
+				ErrInvalidPolicyScript, "$: all script can not have key hash"},
+			{`{"type": "any", "scripts": [{"type": "sig", "keyHash": "` + key1 + `", "slot": 5}]}`,
+				ErrInvalidPolicyScript, "$.scripts[0]: sig script can not have slot"},
+			{`{"type": "all", "required": 1, "scripts": [{"type": "after", "slot": 1}]}`,
+				ErrInvalidPolicyScript, "$: all script can not have required"},
+			{`{"type": "before", "slots": 1}`, ErrInvalidPolicyScript, "unknown field"},
+			{`{"type": "before", "slot": 1} {}`, ErrInvalidPolicyScript, "unexpected data"},
+		}
+
+		for _, c := range cases {
+			_, err := NewPolicyScriptFromJSON([]byte(c.json))
+
+			require.ErrorIs(t, err, c.err, c.json)
+			require.ErrorContains(t, err, c.msg, c.json)
+		}
+	})
+}
@@ -1,5 +1,12 @@
 package core
 
+const conwayProtocolVersionMajor = 9
+
+const (
+	babbageEraName = "Babbage"
+	conwayEraName  = "Conway"
+)
+
 type ProtocolParametersVersion struct {
 	Major uint64 `json:"major"`
 	Minor uint64 `json:"minor"`
@@ -62,4 +69,24 @@ type ProtocolParameters struct {
 	ExtraPraosEntropy      *uint64                            `json:"extraPraosEntropy"`
 	Decentralization       *uint64                            `json:"decentralization"`
 	MinUTxOValue           *uint64                            `json:"minUTxOValue"`
+
+	MinFeeRefScriptCostPerByte float64 `json:"minFeeRefScriptCostPerByte"`
+	// MinFeeRefScriptRange and MinFeeRefScriptMultiplier define reference scripts fee tiers
+	// (not part of cardano-cli protocol parameters, default values are used if not set)
+	MinFeeRefScriptRange      uint64  `json:"minFeeRefScriptRange,omitempty"`
+	MinFeeRefScriptMultiplier float64 `json:"minFeeRefScriptMultiplier,omitempty"`
+}
+
+// IsConwayEra returns true if protocol version belongs to conway era
+func (pp ProtocolParameters) IsConwayEra() bool {
+	return pp.ProtocolVersion.Major >= conwayProtocolVersionMajor
+}
+
+// GetEraName returns era name for protocol version
+func (pp ProtocolParameters) GetEraName() string {
+	if pp.IsConwayEra() {
+		return conwayEraName
+	}
+
+	return babbageEraName
 }
@@ -1,16 +1,24 @@
 package core
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
 )
 
 const (
-	draftTxFile = "tx.draft"
+	draftTxFile            = "tx.draft"
+	protocolParametersFile = "protocol-parameters.json"
+)
+
+var (
+	ErrBurnedTokensNotInInputs = errors.New("burned tokens are not present in inputs")
+	ErrMintAmountOutOfRange    = errors.New("mint amount out of range")
+	ErrAmountOutOfRange        = errors.New("amount out of range")
 )
 
 type TxInput struct {
@@ -33,6 +41,12 @@ type TxOutput struct {
 	Addr   string        `json:"addr"`
 	Amount uint64        `json:"amount"`
 	Tokens []TokenAmount `json:"token,omitempty"`
+	// Datum is inline datum (plutus data cbor)
+	Datum []byte `json:"datum,omitempty"`
+	// DatumHash is hash of the datum which is not part of the output
+	DatumHash string `json:"datumHash,omitempty"`
+	// ReferenceScript is script which other transactions can use without including it
+	ReferenceScript *TxReferenceScript `json:"refScript,omitempty"`
 }
 
 func NewTxOutput(addr string, amount uint64, tokens ...TokenAmount) TxOutput {
@@ -43,6 +57,32 @@ func NewTxOutput(addr string, amount uint64, tokens ...TokenAmount) TxOutput {
 	}
 }
 
+// WithInlineDatum returns copy of the output with inline datum (plutus data cbor)
+func (o TxOutput) WithInlineDatum(datum []byte) TxOutput {
+	o.Datum = datum
+
+	return o
+}
+
+// WithDatumHash returns copy of the output with datum hash
+func (o TxOutput) WithDatumHash(datumHash string) TxOutput {
+	o.DatumHash = datumHash
+
+	return o
+}
+
+// WithReferenceScript returns copy of the output with reference script
+func (o TxOutput) WithReferenceScript(script TxReferenceScript) TxOutput {
+	o.ReferenceScript = &script
+
+	return o
+}
+
+// IsPostAlonzo returns true if output has datum or reference script and must be serialized in map format
+func (o TxOutput) IsPostAlonzo() bool {
+	return o.Datum != nil || o.DatumHash != "" || o.ReferenceScript != nil
+}
+
 func (o TxOutput) String() string {
 	var sb strings.Builder
 
@@ -56,29 +96,96 @@ func (o TxOutput) String() string {
 	return sb.String()
 }
 
+// Apply adds cardano-cli arguments for datum and reference script of the output (after --tx-out)
+func (o TxOutput) Apply(args *[]string, basePath string, indx int) error {
+	if o.Datum != nil {
+		datumFilePath := filepath.Join(basePath, fmt.Sprintf("out_datum_%d.cbor", indx))
+		if err := os.WriteFile(datumFilePath, o.Datum, FilePermission); err != nil {
+			return err
+		}
+
+		*args = append(*args, "--tx-out-inline-datum-cbor-file", datumFilePath)
+	} else if o.DatumHash != "" {
+		*args = append(*args, "--tx-out-datum-hash", o.DatumHash)
+	}
+
+	if o.ReferenceScript != nil {
+		scriptJSON, err := o.ReferenceScript.ToJSON()
+		if err != nil {
+			return err
+		}
+
+		scriptFilePath := filepath.Join(basePath, fmt.Sprintf("out_script_%d.json", indx))
+		if err := os.WriteFile(scriptFilePath, scriptJSON, FilePermission); err != nil {
+			return err
+		}
+
+		*args = append(*args, "--tx-out-reference-script-file", scriptFilePath)
+	}
+
+	return nil
+}
+
 type TxBuilder struct {
-	baseDirectory      string
-	inputs             []txInputWithPolicyScript
-	outputs            []TxOutput
-	mints              txTokenMintInputs
-	metadata           []byte
-	protocolParameters []byte
-	timeToLive         uint64
-	testNetMagic       uint
-	fee                uint64
-	cardanoCliBinary   string
-}
-
-func NewTxBuilder(cardanoCliBinary string) (*TxBuilder, error) {
+	baseDirectory          string
+	inputs                 []txInputWithPolicyScript
+	outputs                []TxOutput
+	mints                  txTokenMintInputs
+	certificates           []txCertificateWithScript
+	withdrawals            []txWithdrawalWithScript
+	votes                  []txVotingProcedureWithScript
+	proposals              []TxProposal
+	metadata               []byte
+	protocolParameters     []byte
+	protocolParametersData *ProtocolParameters
+	timeToLive             uint64
+	validityStart          uint64
+	collateralInputs       []TxInput
+	referenceInputs        []TxInput
+	requiredSigners        []string
+	collateralReturn       *TxOutput
+	totalCollateral        uint64
+	testNetMagic           uint
+	fee                    uint64
+	referenceScriptsSize   uint64
+	cardanoCliBinary       string
+	backend                ITxBuilderBackend
+}
+
+// TxBuilderOption defines TxBuilder configuration option
+type TxBuilderOption func(b *TxBuilder)
+
+// WithTxBuilderBackend sets backend which builds, measures and signs transactions
+func WithTxBuilderBackend(backend ITxBuilderBackend) TxBuilderOption {
+	return func(b *TxBuilder) {
+		b.backend = backend
+	}
+}
+
+// WithCardanoCliBuild uses cardano-cli backend instead of native one
+func WithCardanoCliBuild(options ...CardanoCliOption) TxBuilderOption {
+	return func(b *TxBuilder) {
+		b.backend = NewTxBuilderCliBackend(b.cardanoCliBinary, options...)
+	}
+}
+
+func NewTxBuilder(cardanoCliBinary string, options ...TxBuilderOption) (*TxBuilder, error) {
 	baseDirectory, err := os.MkdirTemp("", "cardano-txs")
 	if err != nil {
 		return nil, err
 	}
 
-	return &TxBuilder{
+	builder := &TxBuilder{
 		baseDirectory:    baseDirectory,
 		cardanoCliBinary: cardanoCliBinary,
-	}, nil
+		backend:          NewTxBuilderNativeBackend(),
+	}
+
+	for _, opt := range options {
+		opt(builder)
+	}
+
+	return builder, nil
 }
 
 func (b *TxBuilder) Dispose() {
@@ -108,6 +215,22 @@ func (b *TxBuilder) AddInputsWithScript(script IPolicyScript, inputs ...TxInput)
 	return b
 }
 
+// AddInputsWithReferenceScript adds native script locked inputs whose script is published
+// as reference script in scriptRefInput utxo, so the script is not part of the witness set
+func (b *TxBuilder) AddInputsWithReferenceScript(
+	script IPolicyScript, scriptRefInput TxInput, inputs ...TxInput,
+) *TxBuilder {
+	for _, inp := range inputs {
+		b.inputs = append(b.inputs, txInputWithPolicyScript{
+			txInput:        inp,
+			policyScript:   script,
+			scriptRefInput: &scriptRefInput,
+		})
+	}
+
+	return b
+}
+
 func (b *TxBuilder) AddInputsWithScripts(inputs []TxInput, scripts []IPolicyScript) *TxBuilder {
 	cnt := len(inputs)
 	if l := len(scripts); cnt > l {
@@ -134,6 +257,57 @@ func (b *TxBuilder) AddInputs(inputs ...TxInput) *TxBuilder {
 	return b
 }
 
+// AddPlutusInputs adds plutus script locked inputs which are spent with the same script, datum and redeemer
+func (b *TxBuilder) AddPlutusInputs(witness TxPlutusScriptWitness, inputs ...TxInput) *TxBuilder {
+	for _, inp := range inputs {
+		// every input has its own redeemer execution units
+		inputWitness := witness
+
+		b.inputs = append(b.inputs, txInputWithPolicyScript{
+			txInput:       inp,
+			plutusWitness: &inputWitness,
+		})
+	}
+
+	return b
+}
+
+// AddReferenceInputs adds inputs which are read (datums, reference scripts) but not spent by the transaction
+func (b *TxBuilder) AddReferenceInputs(inputs ...TxInput) *TxBuilder {
+	b.referenceInputs = append(b.referenceInputs, inputs...)
+
+	return b
+}
+
+// AddRequiredSigners adds key hashes which must sign the transaction although they do not own any input
+// (signatures checked by native or plutus scripts)
+func (b *TxBuilder) AddRequiredSigners(keyHashes ...string) *TxBuilder {
+	b.requiredSigners = append(b.requiredSigners, keyHashes...)
+
+	return b
+}
+
+// AddCollateralInputs adds key locked inputs which are taken if plutus script validation fails
+func (b *TxBuilder) AddCollateralInputs(inputs ...TxInput) *TxBuilder {
+	b.collateralInputs = append(b.collateralInputs, inputs...)
+
+	return b
+}
+
+// SetCollateralReturn sets output which receives collateral inputs change if plutus script validation fails
+func (b *TxBuilder) SetCollateralReturn(output TxOutput) *TxBuilder {
+	b.collateralReturn = &output
+
+	return b
+}
+
+// SetTotalCollateral sets amount of lovelace which is taken from collateral inputs if plutus script validation fails
+func (b *TxBuilder) SetTotalCollateral(totalCollateral uint64) *TxBuilder {
+	b.totalCollateral = totalCollateral
+
+	return b
+}
+
 func (b *TxBuilder) AddOutputs(outputs ...TxOutput) *TxBuilder {
 	b.outputs = append(b.outputs, outputs...)
 
@@ -179,6 +353,39 @@ func (b *TxBuilder) RemoveOutput(index int) *TxBuilder {
 
 func (b *TxBuilder) AddTokenMints(
 	policyScripts []IPolicyScript, tokens []TokenAmount,
+) *TxBuilder {
+	return b.AddMints(policyScripts, b.toMintTokenAmounts(tokens, 1))
+}
+
+// BurnTokens burns tokens which must be present in the transaction inputs
+func (b *TxBuilder) BurnTokens(
+	policyScripts []IPolicyScript, tokens []TokenAmount,
+) *TxBuilder {
+	return b.AddMints(policyScripts, b.toMintTokenAmounts(tokens, -1))
+}
+
+// toMintTokenAmounts converts amounts to signed ones. Amounts which do not fit into int64 are skipped
+// and the error is returned from Build
+func (b *TxBuilder) toMintTokenAmounts(tokens []TokenAmount, sign int64) []MintTokenAmount {
+	mintTokens := make([]MintTokenAmount, 0, len(tokens))
+
+	for _, token := range tokens {
+		if token.Amount > math.MaxInt64 {
+			b.mints.errs = append(b.mints.errs, fmt.Errorf("%w: %s amount = %d",
+				ErrMintAmountOutOfRange, token.Token, token.Amount))
+
+			continue
+		}
+
+		mintTokens = append(mintTokens, NewMintTokenAmount(token.Token, sign*int64(token.Amount)))
+	}
+
+	return mintTokens
+}
+
+// AddMints adds minted (positive amount) and burned (negative amount) tokens
+func (b *TxBuilder) AddMints(
+	policyScripts []IPolicyScript, tokens []MintTokenAmount,
 ) *TxBuilder {
 	b.mints.tokens = append(b.mints.tokens, tokens...)
 	b.mints.policyScripts = append(b.mints.policyScripts, policyScripts...)
@@ -186,6 +393,62 @@ func (b *TxBuilder) AddTokenMints(
 	return b
 }
 
+// GetMintedAndBurnedTokens returns sums of minted and burned (as positive amounts) tokens by token name
+func (b *TxBuilder) GetMintedAndBurnedTokens() (minted map[string]uint64, burned map[string]uint64, err error) {
+	sums := map[string]int64{}
+
+	for _, token := range b.mints.tokens {
+		tokenName, sum := token.TokenName(), sums[token.TokenName()]
+		if (token.Amount > 0 && sum > math.MaxInt64-token.Amount) ||
+			(token.Amount < 0 && sum < math.MinInt64-token.Amount) {
+			return nil, nil, fmt.Errorf("%w: sum of %s overflows", ErrMintAmountOutOfRange, tokenName)
+		}
+
+		sums[tokenName] = sum + token.Amount
+	}
+
+	minted, burned = map[string]uint64{}, map[string]uint64{}
+
+	for tokenName, amount := range sums {
+		if amount > 0 {
+			minted[tokenName] = uint64(amount)
+		} else if amount < 0 {
+			burned[tokenName] = uint64(-(amount + 1)) + 1 // -math.MinInt64 does not fit into int64
+		}
+	}
+
+	return minted, burned, nil
+}
+
+// CheckMints checks that mint amounts and their sums fit into int64
+func (b *TxBuilder) CheckMints() error {
+	if _, _, err := b.GetMintedAndBurnedTokens(); err != nil {
+		return errors.Join(append(b.mints.errs, err)...)
+	}
+
+	return errors.Join(b.mints.errs...)
+}
+
+// CheckBurnedTokens checks that burned tokens are present in the inputs (token name => sum of inputs).
+// Build does not know input amounts and does not call it, so the caller must provide the sums of inputs
+func (b *TxBuilder) CheckBurnedTokens(inputsSum map[string]uint64) error {
+	var errs []error
+
+	_, burned, err := b.GetMintedAndBurnedTokens()
+	if err != nil {
+		return err
+	}
+
+	for tokenName, amount := range burned {
+		if inputsSum[tokenName] < amount {
+			errs = append(errs, fmt.Errorf("%w: %s has = %d, burned = %d",
+				ErrBurnedTokensNotInInputs, tokenName, inputsSum[tokenName], amount))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 func (b *TxBuilder) SetMetaData(metadata []byte) *TxBuilder {
 	b.metadata = metadata
 
@@ -194,6 +457,7 @@ func (b *TxBuilder) SetMetaData(metadata []byte) *TxBuilder {
 
 func (b *TxBuilder) SetProtocolParameters(protocolParameters []byte) *TxBuilder {
 	b.protocolParameters = protocolParameters
+	b.protocolParametersData = nil
 
 	return b
 }
@@ -204,65 +468,47 @@ func (b *TxBuilder) SetTimeToLive(timeToLive uint64) *TxBuilder {
 	return b
 }
 
+// SetValidityStart sets slot from which transaction is valid (invalid-before)
+func (b *TxBuilder) SetValidityStart(validityStart uint64) *TxBuilder {
+	b.validityStart = validityStart
+
+	return b
+}
+
+// SetReferenceScriptsSize sets total size of scripts in spent and referenced outputs which are not known
+// to the builder (used for conway era reference scripts fee). Scripts of AddInputsWithReferenceScript
+// and of plutus inputs with ReferenceInput are added to it automatically
+func (b *TxBuilder) SetReferenceScriptsSize(referenceScriptsSize uint64) *TxBuilder {
+	b.referenceScriptsSize = referenceScriptsSize
+
+	return b
+}
+
+// CalculateFee calculates fee for transaction with witnessCount vkey witnesses
+// if witnessCount is zero, witness count is estimated from inputs and policy scripts
 func (b *TxBuilder) CalculateFee(witnessCount int) (uint64, error) {
 	if b.protocolParameters == nil {
 		return 0, errors.New("protocol parameters not set")
 	}
 
-	protocolParamsFilePath := filepath.Join(b.baseDirectory, "protocol-parameters.json")
-	if err := os.WriteFile(protocolParamsFilePath, b.protocolParameters, FilePermission); err != nil {
+	if err := b.CheckMints(); err != nil {
 		return 0, err
 	}
 
-	if err := b.buildRawTx(protocolParamsFilePath, 0); err != nil {
+	if err := b.AdjustValidityInterval(); err != nil {
 		return 0, err
 	}
 
-	if witnessCount == 0 {
-		for _, inp := range b.inputs {
-			witnessCount += inp.GetWitnessCount()
-		}
-
-		witnessCount = max(witnessCount, 1)
-	}
-
-	feeOutput, err := runCommand(b.cardanoCliBinary, append([]string{
-		"transaction", "calculate-min-fee",
-		"--tx-body-file", filepath.Join(b.baseDirectory, draftTxFile),
-		"--tx-in-count", strconv.Itoa(len(b.inputs)),
-		"--tx-out-count", strconv.Itoa(len(b.outputs)),
-		"--witness-count", strconv.FormatUint(uint64(witnessCount), 10),
-		"--protocol-params-file", protocolParamsFilePath,
-	}, getTestNetMagicArgs(b.testNetMagic)...))
-	if err != nil {
-		return 0, err
-	}
-
-	return strconv.ParseUint(strings.Split(feeOutput, " ")[0], 10, 64)
+	return b.backend.CalculateFee(b, witnessCount)
 }
 
+// CalculateMinUtxo calculates minimal lovelace amount for the output
 func (b *TxBuilder) CalculateMinUtxo(output TxOutput) (uint64, error) {
 	if b.protocolParameters == nil {
 		return 0, errors.New("protocol parameters not set")
 	}
 
-	protocolParamsFilePath := filepath.Join(b.baseDirectory, "protocol-parameters.json")
-	if err := os.WriteFile(protocolParamsFilePath, b.protocolParameters, FilePermission); err != nil {
-		return 0, err
-	}
-
-	result, err := runCommand(b.cardanoCliBinary, []string{
-		"transaction", "calculate-min-required-utxo",
-		"--protocol-params-file", protocolParamsFilePath,
-		"--tx-out", output.String(),
-	})
-	if err != nil {
-		return 0, err
-	}
-
-	result = strings.TrimSpace(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(result)), AdaTokenName))
-
-	return strconv.ParseUint(result, 0, 64)
+	return b.backend.CalculateMinUtxo(b, output)
 }
 
 func (b *TxBuilder) Build() ([]byte, string, error) {
@@ -274,31 +520,23 @@ func (b *TxBuilder) Build() ([]byte, string, error) {
 		return nil, "", err
 	}
 
-	protocolParamsFilePath := filepath.Join(b.baseDirectory, "protocol-parameters.json")
-	if err := os.WriteFile(protocolParamsFilePath, b.protocolParameters, FilePermission); err != nil {
+	if err := b.CheckMints(); err != nil {
 		return nil, "", err
 	}
 
-	if err := b.buildRawTx(protocolParamsFilePath, b.fee); err != nil {
+	if err := b.AdjustValidityInterval(); err != nil {
 		return nil, "", err
 	}
 
-	bytes, err := os.ReadFile(filepath.Join(b.baseDirectory, draftTxFile))
-	if err != nil {
+	if err := b.CheckValidityInterval(); err != nil {
 		return nil, "", err
 	}
 
-	txRaw, err := newTransactionUnwitnessedRawFromJSON(bytes)
-	if err != nil {
+	if err := b.CheckExUnits(); err != nil {
 		return nil, "", err
 	}
 
-	txHash, err := NewCliUtils(b.cardanoCliBinary).getTxHash(txRaw, b.baseDirectory)
-	if err != nil {
-		return nil, "", err
-	}
-
-	return txRaw, txHash, nil
+	return b.backend.Build(b, b.fee)
 }
 
 func (b *TxBuilder) CheckOutputs() error {
@@ -313,159 +551,250 @@ func (b *TxBuilder) CheckOutputs() error {
 	return errors.Join(errs...)
 }
 
-func (b *TxBuilder) buildRawTx(protocolParamsFilePath string, fee uint64) error {
-	args := []string{
-		"transaction", "build-raw",
-		"--protocol-params-file", protocolParamsFilePath,
-		"--fee", strconv.FormatUint(fee, 10),
-		"--invalid-hereafter", strconv.FormatUint(b.timeToLive, 10),
-		"--out-file", filepath.Join(b.baseDirectory, draftTxFile),
+// CheckValidityInterval checks that validity interval is not empty
+// and that policy scripts of inputs, mints, certificates, withdrawals and votes can be satisfied in it
+func (b *TxBuilder) CheckValidityInterval() error {
+	if b.timeToLive > 0 && b.validityStart >= b.timeToLive {
+		return fmt.Errorf("invalid validity interval: [%d, %d)", b.validityStart, b.timeToLive)
 	}
 
-	if b.metadata != nil {
-		metaDataFilePath := filepath.Join(b.baseDirectory, "metadata.json")
-		if err := os.WriteFile(metaDataFilePath, b.metadata, FilePermission); err != nil {
-			return err
-		}
+	var errs []error
 
-		args = append(args, "--metadata-json-file", metaDataFilePath)
+	for _, inp := range b.inputs {
+		if _, err := inp.GetWitnessCount(b.validityStart, b.timeToLive); err != nil {
+			errs = append(errs, fmt.Errorf("input %s: %w", inp.txInput, err))
+		}
 	}
 
-	if err := b.mints.Apply(&args, b.baseDirectory); err != nil {
-		return err
+	for _, policyScript := range b.mints.policyScripts {
+		if _, err := getPolicyScriptCountInInterval(policyScript, b.validityStart, b.timeToLive); err != nil {
+			errs = append(errs, fmt.Errorf("mint: %w", err))
+		}
 	}
 
-	for i, inp := range b.inputs {
-		if err := inp.Apply(&args, b.baseDirectory, i); err != nil {
-			return err
+	for i, cert := range b.certificates {
+		if cert.policyScript == nil {
+			continue
+		}
+
+		if _, err := getPolicyScriptCountInInterval(cert.policyScript, b.validityStart, b.timeToLive); err != nil {
+			errs = append(errs, fmt.Errorf("certificate %d: %w", i, err))
 		}
 	}
 
-	for _, out := range b.outputs {
-		args = append(args, "--tx-out", out.String())
+	for _, w := range b.withdrawals {
+		if w.policyScript == nil {
+			continue
+		}
+
+		if _, err := getPolicyScriptCountInInterval(w.policyScript, b.validityStart, b.timeToLive); err != nil {
+			errs = append(errs, fmt.Errorf("withdrawal %s: %w", w.withdrawal.Address, err))
+		}
 	}
 
-	_, err := runCommand(b.cardanoCliBinary, args)
+	for _, v := range b.votes {
+		if v.policyScript == nil {
+			continue
+		}
+
+		if _, err := getPolicyScriptCountInInterval(v.policyScript, b.validityStart, b.timeToLive); err != nil {
+			errs = append(errs, fmt.Errorf("vote %s: %w", v.vote.GovActionID, err))
+		}
+	}
 
-	return err
+	return errors.Join(errs...)
 }
 
-// SignTx signs tx and assembles all signatures in final tx
-func (b *TxBuilder) SignTx(txRaw []byte, signers []ITxSigner) (res []byte, err error) {
-	witnesses := make([][]byte, len(signers))
-	for i, signer := range signers {
-		witnesses[i], err = b.CreateTxWitness(txRaw, signer)
+// AdjustValidityInterval narrows validity interval so time locks (before/after) of policy scripts
+// of inputs, mints, certificates, withdrawals and votes are satisfied.
+// Validity start is only increased and time to live is only decreased (or set if not set)
+func (b *TxBuilder) AdjustValidityInterval() error {
+	validityStart, timeToLive := b.validityStart, b.timeToLive
+
+	for _, policyScript := range b.getPolicyScripts() {
+		var err error
+
+		validityStart, timeToLive, err = getPolicyScriptValidityInterval(policyScript, validityStart, timeToLive)
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
 
-	return b.AssembleTxWitnesses(txRaw, witnesses)
-}
+	b.validityStart, b.timeToLive = validityStart, timeToLive
 
-// CreateTxWitness signs transaction hash and creates witness cbor
-func (b *TxBuilder) CreateTxWitness(txRaw []byte, wallet ITxSigner) ([]byte, error) {
-	outFilePath := filepath.Join(b.baseDirectory, "tx.wit")
-	txFilePath := filepath.Join(b.baseDirectory, "tx.raw")
-	signingKeyPath := filepath.Join(b.baseDirectory, "tx.skey")
-	signingKey, _ := wallet.GetPaymentKeys()
+	return nil
+}
 
-	txBytes, err := transactionUnwitnessedRaw(txRaw).ToJSON()
-	if err != nil {
-		return nil, err
+// getPolicyScripts returns policy scripts of inputs, mints, certificates, withdrawals and votes
+func (b *TxBuilder) getPolicyScripts() (policyScripts []IPolicyScript) {
+	for _, inp := range b.inputs {
+		if inp.policyScript != nil {
+			policyScripts = append(policyScripts, inp.policyScript)
+		}
 	}
 
-	if err := os.WriteFile(txFilePath, txBytes, FilePermission); err != nil {
-		return nil, err
-	}
+	policyScripts = append(policyScripts, b.mints.policyScripts...)
 
-	var title string
-	if len(signingKey) > KeySize {
-		title = "PaymentExtendedSigningKeyShelley_ed25519_bip32"
-	} else {
-		title = "PaymentSigningKeyShelley_ed25519"
+	for _, cert := range b.certificates {
+		if cert.policyScript != nil {
+			policyScripts = append(policyScripts, cert.policyScript)
+		}
 	}
 
-	key, err := NewKeyFromBytes(title, "", signingKey)
-	if err != nil {
-		return nil, err
+	for _, w := range b.withdrawals {
+		if w.policyScript != nil {
+			policyScripts = append(policyScripts, w.policyScript)
+		}
 	}
 
-	if err := key.WriteToFile(signingKeyPath); err != nil {
-		return nil, err
+	for _, v := range b.votes {
+		if v.policyScript != nil {
+			policyScripts = append(policyScripts, v.policyScript)
+		}
 	}
 
-	args := append([]string{
-		"transaction", "witness",
-		"--signing-key-file", signingKeyPath,
-		"--tx-body-file", txFilePath,
-		"--out-file", outFilePath},
-		getTestNetMagicArgs(b.testNetMagic)...)
+	return policyScripts
+}
 
-	if _, err = runCommand(b.cardanoCliBinary, args); err != nil {
-		return nil, err
+func (b *TxBuilder) getInputs() []TxInput {
+	inputs := make([]TxInput, len(b.inputs))
+	for i, inp := range b.inputs {
+		inputs[i] = inp.txInput
 	}
 
-	bytes, err := os.ReadFile(outFilePath)
-	if err != nil {
-		return nil, err
+	return inputs
+}
+
+// getRequiredSigners returns distinct required signers key hashes
+func (b *TxBuilder) getRequiredSigners() (result []string) {
+	seen := map[string]bool{}
+
+	for _, keyHash := range b.requiredSigners {
+		if !seen[keyHash] {
+			seen[keyHash] = true
+			result = append(result, keyHash)
+		}
 	}
 
-	return newTransactionWitnessedRawFromJSON(bytes)
+	return result
 }
 
-// AssembleTxWitnesses assembles final signed transaction
-func (b *TxBuilder) AssembleTxWitnesses(txRaw []byte, witnesses [][]byte) ([]byte, error) {
-	outFilePath := filepath.Join(b.baseDirectory, "tx.sig")
-	txFilePath := filepath.Join(b.baseDirectory, "tx.raw")
-	witnessesFilePaths := make([]string, len(witnesses))
-
-	for i, witness := range witnesses {
-		witnessesFilePaths[i] = filepath.Join(b.baseDirectory, fmt.Sprintf("witness-%d", i+1))
+// getReferenceInputs returns distinct reference inputs including utxos with reference scripts of inputs
+func (b *TxBuilder) getReferenceInputs() []TxInput {
+	var (
+		result []TxInput
+		seen   = map[TxInput]bool{}
+	)
 
-		content, err := TxWitnessRaw(witness).ToJSON()
-		if err != nil {
-			return nil, err
+	add := func(inp TxInput) {
+		if !seen[inp] {
+			seen[inp] = true
+			result = append(result, inp)
 		}
+	}
 
-		if err := os.WriteFile(witnessesFilePaths[i], content, FilePermission); err != nil {
-			return nil, err
-		}
+	for _, inp := range b.referenceInputs {
+		add(inp)
 	}
 
-	txBytes, err := transactionUnwitnessedRaw(txRaw).ToJSON()
-	if err != nil {
-		return nil, err
+	for _, inp := range b.inputs {
+		if inp.scriptRefInput != nil {
+			add(*inp.scriptRefInput)
+		} else if inp.plutusWitness != nil && inp.plutusWitness.ReferenceInput != nil {
+			add(*inp.plutusWitness.ReferenceInput)
+		}
 	}
 
-	if err := os.WriteFile(txFilePath, txBytes, FilePermission); err != nil {
-		return nil, err
+	return result
+}
+
+// getReferenceScriptsSize returns total size of known reference scripts (every script utxo is counted once)
+// increased by size set with SetReferenceScriptsSize
+func (b *TxBuilder) getReferenceScriptsSize() (uint64, error) {
+	var (
+		size = b.referenceScriptsSize
+		seen = map[TxInput]bool{}
+	)
+
+	for _, inp := range b.inputs {
+		switch {
+		case inp.scriptRefInput != nil && !seen[*inp.scriptRefInput]:
+			scriptBytes, err := getPolicyScriptCbor(inp.policyScript)
+			if err != nil {
+				return 0, err
+			}
+
+			seen[*inp.scriptRefInput] = true
+			size += uint64(len(scriptBytes))
+		case inp.plutusWitness != nil && inp.plutusWitness.ReferenceInput != nil &&
+			!seen[*inp.plutusWitness.ReferenceInput]:
+			seen[*inp.plutusWitness.ReferenceInput] = true
+			size += uint64(len(inp.plutusWitness.Script.Script))
+		}
 	}
 
-	args := []string{
-		"transaction", "assemble",
-		"--tx-body-file", txFilePath,
-		"--out-file", outFilePath}
+	return size, nil
+}
 
-	for _, fp := range witnessesFilePaths {
-		args = append(args, "--witness-file", fp)
+func (b *TxBuilder) getProtocolParameters() (ProtocolParameters, error) {
+	if b.protocolParameters == nil {
+		return ProtocolParameters{}, errors.New("protocol parameters not set")
 	}
 
-	if _, err = runCommand(b.cardanoCliBinary, args); err != nil {
-		return nil, err
+	if b.protocolParametersData == nil {
+		var protocolParams ProtocolParameters
+
+		if err := json.Unmarshal(b.protocolParameters, &protocolParams); err != nil {
+			return ProtocolParameters{}, err
+		}
+
+		b.protocolParametersData = &protocolParams
 	}
 
-	bytes, err := os.ReadFile(outFilePath)
-	if err != nil {
-		return nil, err
+	return *b.protocolParametersData, nil
+}
+
+// SignTx signs tx with payment keys of the signers (and with their stake keys if certificates,
+// withdrawals or required signers require it) and assembles all signatures in final tx
+func (b *TxBuilder) SignTx(txRaw []byte, signers []ITxSigner) (res []byte, err error) {
+	witnesses := make([][]byte, 0, len(signers))
+
+	for _, signer := range signers {
+		witness, err := b.CreateTxWitness(txRaw, signer)
+		if err != nil {
+			return nil, err
+		}
+
+		stakeWitness, err := b.createStakeKeyWitness(txRaw, signer)
+		if err != nil {
+			return nil, err
+		}
+
+		witnesses = append(witnesses, witness)
+
+		if stakeWitness != nil {
+			witnesses = append(witnesses, stakeWitness)
+		}
 	}
 
-	return newTransactionWitnessedRawFromJSON(bytes)
+	return b.AssembleTxWitnesses(txRaw, witnesses)
+}
+
+// CreateTxWitness signs transaction hash and creates witness cbor
+func (b *TxBuilder) CreateTxWitness(txRaw []byte, wallet ITxSigner) ([]byte, error) {
+	return b.backend.CreateTxWitness(b, txRaw, wallet)
+}
+
+// AssembleTxWitnesses assembles final signed transaction
+func (b *TxBuilder) AssembleTxWitnesses(txRaw []byte, witnesses [][]byte) ([]byte, error) {
+	return b.backend.AssembleTxWitnesses(b, txRaw, witnesses)
 }
 
 type txInputWithPolicyScript struct {
-	txInput      TxInput
-	policyScript IPolicyScript
+	txInput        TxInput
+	policyScript   IPolicyScript
+	scriptRefInput *TxInput
+	plutusWitness  *TxPlutusScriptWitness
 }
 
 func (txInputPS txInputWithPolicyScript) Apply(
@@ -473,10 +802,20 @@ func (txInputPS txInputWithPolicyScript) Apply(
 ) error {
 	*args = append(*args, "--tx-in", txInputPS.txInput.String())
 
+	if txInputPS.plutusWitness != nil {
+		return txInputPS.plutusWitness.Apply(args, basePath, indx)
+	}
+
 	if txInputPS.policyScript == nil {
 		return nil
 	}
 
+	if txInputPS.scriptRefInput != nil {
+		*args = append(*args, "--simple-script-tx-in-reference", txInputPS.scriptRefInput.String())
+
+		return nil
+	}
+
 	policyScriptJSON, err := txInputPS.policyScript.GetPolicyScriptJSON()
 	if err != nil {
 		return err
@@ -492,17 +831,19 @@ func (txInputPS txInputWithPolicyScript) Apply(
 	return nil
 }
 
-func (txInputPS txInputWithPolicyScript) GetWitnessCount() int {
+func (txInputPS txInputWithPolicyScript) GetWitnessCount(validityStart, timeToLive uint64) (int, error) {
 	if txInputPS.policyScript != nil {
-		return txInputPS.policyScript.GetCount()
+		return getPolicyScriptCountInInterval(txInputPS.policyScript, validityStart, timeToLive)
 	}
 
-	return 0
+	return 0, nil
 }
 
 type txTokenMintInputs struct {
-	tokens        []TokenAmount
+	tokens        []MintTokenAmount
 	policyScripts []IPolicyScript
+	// errs are errors of amounts which could not be converted to MintTokenAmount
+	errs []error
 }
 
 func (txMint txTokenMintInputs) Apply(
@@ -0,0 +1,334 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ITxBuilderBackend builds, measures and signs transactions described by TxBuilder
+type ITxBuilderBackend interface {
+	// Build returns cbor of the unwitnessed transaction with given fee and its hash
+	Build(b *TxBuilder, fee uint64) ([]byte, string, error)
+	// CalculateFee calculates fee for transaction with witnessCount vkey witnesses
+	CalculateFee(b *TxBuilder, witnessCount int) (uint64, error)
+	// CalculateMinUtxo calculates minimal lovelace amount for the output
+	CalculateMinUtxo(b *TxBuilder, output TxOutput) (uint64, error)
+	// GetTxHash returns hash of (witnessed or unwitnessed) transaction
+	GetTxHash(b *TxBuilder, txRaw []byte) (string, error)
+	// CreateTxWitness signs transaction hash and creates witness cbor
+	CreateTxWitness(b *TxBuilder, txRaw []byte, signer ITxSigner) ([]byte, error)
+	// AssembleTxWitnesses assembles final signed transaction
+	AssembleTxWitnesses(b *TxBuilder, txRaw []byte, witnesses [][]byte) ([]byte, error)
+}
+
+// TxBuilderNativeBackend builds transactions in go without any external tool
+type TxBuilderNativeBackend struct{}
+
+var _ ITxBuilderBackend = (*TxBuilderNativeBackend)(nil)
+
+func NewTxBuilderNativeBackend() *TxBuilderNativeBackend {
+	return &TxBuilderNativeBackend{}
+}
+
+func (nb *TxBuilderNativeBackend) Build(b *TxBuilder, fee uint64) ([]byte, string, error) {
+	return b.buildRawTxNative(fee)
+}
+
+func (nb *TxBuilderNativeBackend) CalculateFee(b *TxBuilder, witnessCount int) (uint64, error) {
+	return b.calculateFeeNative(witnessCount)
+}
+
+func (nb *TxBuilderNativeBackend) CalculateMinUtxo(b *TxBuilder, output TxOutput) (uint64, error) {
+	protocolParams, err := b.getProtocolParameters()
+	if err != nil {
+		return 0, err
+	}
+
+	return CalculateMinUtxoForOutput(protocolParams, output)
+}
+
+func (nb *TxBuilderNativeBackend) GetTxHash(_ *TxBuilder, txRaw []byte) (string, error) {
+	return GetTxHash(txRaw)
+}
+
+// CreateTxWitness signs transaction hash in process so key material never leaves the signer
+func (nb *TxBuilderNativeBackend) CreateTxWitness(_ *TxBuilder, txRaw []byte, signer ITxSigner) ([]byte, error) {
+	txHash, err := getTxHashBytes(txRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	return signer.CreateTxWitness(txHash)
+}
+
+func (nb *TxBuilderNativeBackend) AssembleTxWitnesses(
+	b *TxBuilder, txRaw []byte, witnesses [][]byte,
+) ([]byte, error) {
+	return b.assembleTxWitnessesNative(txRaw, witnesses)
+}
+
+// TxBuilderCliBackend builds transactions with cardano-cli
+type TxBuilderCliBackend struct {
+	cli cardanoCli
+}
+
+var _ ITxBuilderBackend = (*TxBuilderCliBackend)(nil)
+
+func NewTxBuilderCliBackend(cardanoCliBinary string, options ...CardanoCliOption) *TxBuilderCliBackend {
+	return &TxBuilderCliBackend{
+		cli: newCardanoCli(cardanoCliBinary, options...),
+	}
+}
+
+func (cb *TxBuilderCliBackend) Build(b *TxBuilder, fee uint64) ([]byte, string, error) {
+	protocolParamsFilePath, err := cb.writeProtocolParameters(b)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := cb.buildRawTx(b, protocolParamsFilePath, fee); err != nil {
+		return nil, "", err
+	}
+
+	bytes, err := os.ReadFile(filepath.Join(b.baseDirectory, draftTxFile))
+	if err != nil {
+		return nil, "", err
+	}
+
+	txRaw, err := newTransactionUnwitnessedRawFromJSON(bytes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	txHash, err := cb.GetTxHash(b, txRaw)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return txRaw, txHash, nil
+}
+
+func (cb *TxBuilderCliBackend) CalculateFee(b *TxBuilder, witnessCount int) (uint64, error) {
+	if _, _, err := cb.Build(b, 0); err != nil {
+		return 0, err
+	}
+
+	if witnessCount == 0 {
+		var err error
+
+		witnessCount, err = b.getWitnessCount()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	referenceScriptsSize, err := b.getReferenceScriptsSize()
+	if err != nil {
+		return 0, err
+	}
+
+	args := []string{
+		"transaction", "calculate-min-fee",
+		"--tx-body-file", filepath.Join(b.baseDirectory, draftTxFile),
+		"--tx-in-count", strconv.Itoa(len(b.inputs)),
+		"--tx-out-count", strconv.Itoa(len(b.outputs)),
+		"--witness-count", strconv.FormatUint(uint64(witnessCount), 10),
+		"--protocol-params-file", filepath.Join(b.baseDirectory, protocolParametersFile),
+	}
+
+	if referenceScriptsSize > 0 {
+		args = append(args, "--reference-script-size", strconv.FormatUint(referenceScriptsSize, 10))
+	}
+
+	feeOutput, err := cb.cli.run(append(args, getTestNetMagicArgs(b.testNetMagic)...))
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseUint(strings.Split(feeOutput, " ")[0], 10, 64)
+}
+
+func (cb *TxBuilderCliBackend) CalculateMinUtxo(b *TxBuilder, output TxOutput) (uint64, error) {
+	protocolParamsFilePath, err := cb.writeProtocolParameters(b)
+	if err != nil {
+		return 0, err
+	}
+
+	args := []string{
+		"transaction", "calculate-min-required-utxo",
+		"--protocol-params-file", protocolParamsFilePath,
+		"--tx-out", output.String(),
+	}
+
+	if err := output.Apply(&args, b.baseDirectory, 0); err != nil {
+		return 0, err
+	}
+
+	result, err := cb.cli.run(args)
+	if err != nil {
+		return 0, err
+	}
+
+	result = strings.TrimSpace(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(result)), AdaTokenName))
+
+	return strconv.ParseUint(result, 0, 64)
+}
+
+func (cb *TxBuilderCliBackend) GetTxHash(b *TxBuilder, txRaw []byte) (string, error) {
+	return CliUtils{cli: cb.cli}.getTxHash(txRaw, b.baseDirectory)
+}
+
+// CreateTxWitness signs transaction hash in process
+// cardano-cli transaction witness is not used because it requires signing key to be written to the disk
+func (cb *TxBuilderCliBackend) CreateTxWitness(b *TxBuilder, txRaw []byte, signer ITxSigner) ([]byte, error) {
+	return NewTxBuilderNativeBackend().CreateTxWitness(b, txRaw, signer)
+}
+
+func (cb *TxBuilderCliBackend) AssembleTxWitnesses(b *TxBuilder, txRaw []byte, witnesses [][]byte) ([]byte, error) {
+	outFilePath := filepath.Join(b.baseDirectory, "tx.sig")
+	txFilePath := filepath.Join(b.baseDirectory, "tx.raw")
+	witnessesFilePaths := make([]string, len(witnesses))
+	eraName := getTxEraName(txRaw)
+
+	for i, witness := range witnesses {
+		witnessesFilePaths[i] = filepath.Join(b.baseDirectory, fmt.Sprintf("witness-%d", i+1))
+
+		content, err := TxWitnessRaw(witness).toJSONInEra(eraName)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := os.WriteFile(witnessesFilePaths[i], content, FilePermission); err != nil {
+			return nil, err
+		}
+	}
+
+	txBytes, err := transactionUnwitnessedRaw(txRaw).ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(txFilePath, txBytes, FilePermission); err != nil {
+		return nil, err
+	}
+
+	args := []string{
+		"transaction", "assemble",
+		"--tx-body-file", txFilePath,
+		"--out-file", outFilePath}
+
+	for _, fp := range witnessesFilePaths {
+		args = append(args, "--witness-file", fp)
+	}
+
+	if _, err = cb.cli.run(args); err != nil {
+		return nil, err
+	}
+
+	bytes, err := os.ReadFile(outFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTransactionWitnessedRawFromJSON(bytes)
+}
+
+func (cb *TxBuilderCliBackend) writeProtocolParameters(b *TxBuilder) (string, error) {
+	protocolParamsFilePath := filepath.Join(b.baseDirectory, protocolParametersFile)
+	if err := os.WriteFile(protocolParamsFilePath, b.protocolParameters, FilePermission); err != nil {
+		return "", err
+	}
+
+	return protocolParamsFilePath, nil
+}
+
+func (cb *TxBuilderCliBackend) buildRawTx(b *TxBuilder, protocolParamsFilePath string, fee uint64) error {
+	args := []string{
+		"transaction", "build-raw",
+		"--protocol-params-file", protocolParamsFilePath,
+		"--fee", strconv.FormatUint(fee, 10),
+		"--out-file", filepath.Join(b.baseDirectory, draftTxFile),
+	}
+
+	if b.timeToLive > 0 {
+		args = append(args, "--invalid-hereafter", strconv.FormatUint(b.timeToLive, 10))
+	}
+
+	if b.validityStart > 0 {
+		args = append(args, "--invalid-before", strconv.FormatUint(b.validityStart, 10))
+	}
+
+	if b.metadata != nil {
+		metaDataFilePath := filepath.Join(b.baseDirectory, "metadata.json")
+		if err := os.WriteFile(metaDataFilePath, b.metadata, FilePermission); err != nil {
+			return err
+		}
+
+		args = append(args, "--metadata-json-file", metaDataFilePath)
+	}
+
+	if err := b.mints.Apply(&args, b.baseDirectory); err != nil {
+		return err
+	}
+
+	for i, inp := range b.inputs {
+		if err := inp.Apply(&args, b.baseDirectory, i); err != nil {
+			return err
+		}
+	}
+
+	for i, out := range b.outputs {
+		args = append(args, "--tx-out", out.String())
+
+		if err := out.Apply(&args, b.baseDirectory, i); err != nil {
+			return err
+		}
+	}
+
+	for _, inp := range b.collateralInputs {
+		args = append(args, "--tx-in-collateral", inp.String())
+	}
+
+	if b.collateralReturn != nil {
+		args = append(args, "--tx-out-return-collateral", b.collateralReturn.String())
+	}
+
+	if b.totalCollateral > 0 {
+		args = append(args, "--tx-total-collateral", strconv.FormatUint(b.totalCollateral, 10))
+	}
+
+	for _, keyHash := range b.getRequiredSigners() {
+		args = append(args, "--required-signer-hash", keyHash)
+	}
+
+	// utxos with reference scripts of inputs are added by cardano-cli itself
+	for _, inp := range b.referenceInputs {
+		args = append(args, "--read-only-tx-in-reference", inp.String())
+	}
+
+	if err := b.applyWithdrawals(&args); err != nil {
+		return err
+	}
+
+	if len(b.certificates) > 0 {
+		protocolParams, err := b.getProtocolParameters()
+		if err != nil {
+			return err
+		}
+
+		if err := b.applyCertificates(&args, protocolParams.IsConwayEra()); err != nil {
+			return err
+		}
+	}
+
+	if err := b.applyGovernance(&args); err != nil {
+		return err
+	}
+
+	_, err := cb.cli.run(args)
+
+	return err
+}
@@ -0,0 +1,152 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// TxBackendMismatch describes different results of primary and secondary backend for the same operation
+type TxBackendMismatch struct {
+	Operation string
+	Primary   string
+	Secondary string
+	// Diff is set if both backends returned transactions which could be decoded
+	Diff TxDiff
+	// PrimaryErr and SecondaryErr are errors returned by backends
+	PrimaryErr   error
+	SecondaryErr error
+}
+
+func (m TxBackendMismatch) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("%s mismatch: primary=%s secondary=%s", m.Operation, m.Primary, m.Secondary))
+
+	if m.PrimaryErr != nil || m.SecondaryErr != nil {
+		sb.WriteString(fmt.Sprintf(" primaryErr=%v secondaryErr=%v", m.PrimaryErr, m.SecondaryErr))
+	}
+
+	if len(m.Diff) > 0 {
+		sb.WriteRune('\n')
+		sb.WriteString(m.Diff.String())
+	}
+
+	return sb.String()
+}
+
+// TxBuilderParityBackend runs every operation on both primary and secondary backend,
+// reports any difference and returns result of the primary backend
+type TxBuilderParityBackend struct {
+	primary   ITxBuilderBackend
+	secondary ITxBuilderBackend
+	report    func(mismatch TxBackendMismatch)
+}
+
+var _ ITxBuilderBackend = (*TxBuilderParityBackend)(nil)
+
+func NewTxBuilderParityBackend(
+	primary, secondary ITxBuilderBackend, report func(mismatch TxBackendMismatch),
+) *TxBuilderParityBackend {
+	return &TxBuilderParityBackend{
+		primary:   primary,
+		secondary: secondary,
+		report:    report,
+	}
+}
+
+func (pb *TxBuilderParityBackend) Build(b *TxBuilder, fee uint64) ([]byte, string, error) {
+	txRaw, txHash, err := pb.primary.Build(b, fee)
+	txRawSecondary, txHashSecondary, errSecondary := pb.secondary.Build(b, fee)
+
+	pb.checkTx("build", txRaw, txRawSecondary, err, errSecondary)
+
+	if err == nil && errSecondary == nil && bytes.Equal(txRaw, txRawSecondary) && txHash != txHashSecondary {
+		pb.report(TxBackendMismatch{Operation: "build hash", Primary: txHash, Secondary: txHashSecondary})
+	}
+
+	return txRaw, txHash, err
+}
+
+func (pb *TxBuilderParityBackend) CalculateFee(b *TxBuilder, witnessCount int) (uint64, error) {
+	fee, err := pb.primary.CalculateFee(b, witnessCount)
+	feeSecondary, errSecondary := pb.secondary.CalculateFee(b, witnessCount)
+
+	pb.checkValue("fee", fee, feeSecondary, err, errSecondary)
+
+	return fee, err
+}
+
+func (pb *TxBuilderParityBackend) CalculateMinUtxo(b *TxBuilder, output TxOutput) (uint64, error) {
+	minUtxo, err := pb.primary.CalculateMinUtxo(b, output)
+	minUtxoSecondary, errSecondary := pb.secondary.CalculateMinUtxo(b, output)
+
+	pb.checkValue("min utxo", minUtxo, minUtxoSecondary, err, errSecondary)
+
+	return minUtxo, err
+}
+
+func (pb *TxBuilderParityBackend) GetTxHash(b *TxBuilder, txRaw []byte) (string, error) {
+	txHash, err := pb.primary.GetTxHash(b, txRaw)
+	txHashSecondary, errSecondary := pb.secondary.GetTxHash(b, txRaw)
+
+	pb.checkValue("hash", txHash, txHashSecondary, err, errSecondary)
+
+	return txHash, err
+}
+
+func (pb *TxBuilderParityBackend) CreateTxWitness(b *TxBuilder, txRaw []byte, signer ITxSigner) ([]byte, error) {
+	witness, err := pb.primary.CreateTxWitness(b, txRaw, signer)
+	witnessSecondary, errSecondary := pb.secondary.CreateTxWitness(b, txRaw, signer)
+
+	pb.checkValue("witness", hex.EncodeToString(witness), hex.EncodeToString(witnessSecondary), err, errSecondary)
+
+	return witness, err
+}
+
+func (pb *TxBuilderParityBackend) AssembleTxWitnesses(
+	b *TxBuilder, txRaw []byte, witnesses [][]byte,
+) ([]byte, error) {
+	txSigned, err := pb.primary.AssembleTxWitnesses(b, txRaw, witnesses)
+	txSignedSecondary, errSecondary := pb.secondary.AssembleTxWitnesses(b, txRaw, witnesses)
+
+	pb.checkTx("assemble", txSigned, txSignedSecondary, err, errSecondary)
+
+	return txSigned, err
+}
+
+func (pb *TxBuilderParityBackend) checkTx(operation string, txRaw, txRawSecondary []byte, err, errSecondary error) {
+	if (err == nil) == (errSecondary == nil) && bytes.Equal(txRaw, txRawSecondary) {
+		return
+	}
+
+	mismatch := TxBackendMismatch{
+		Operation:    operation,
+		Primary:      hex.EncodeToString(txRaw),
+		Secondary:    hex.EncodeToString(txRawSecondary),
+		PrimaryErr:   err,
+		SecondaryErr: errSecondary,
+	}
+
+	if err == nil && errSecondary == nil {
+		// diff is best effort, byte level difference is reported anyway
+		mismatch.Diff, _ = DiffTx(txRaw, txRawSecondary)
+	}
+
+	pb.report(mismatch)
+}
+
+func (pb *TxBuilderParityBackend) checkValue(operation string, value, valueSecondary any, err, errSecondary error) {
+	if (err == nil) == (errSecondary == nil) && (err != nil || value == valueSecondary) {
+		return
+	}
+
+	pb.report(TxBackendMismatch{
+		Operation:    operation,
+		Primary:      fmt.Sprint(value),
+		Secondary:    fmt.Sprint(valueSecondary),
+		PrimaryErr:   err,
+		SecondaryErr: errSecondary,
+	})
+}
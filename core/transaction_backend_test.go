@@ -0,0 +1,265 @@
+package core
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/igorcrevar/go-cardano-tx/core/clitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type txBuilderFeeShiftBackend struct {
+	*TxBuilderNativeBackend
+}
+
+func (fb txBuilderFeeShiftBackend) Build(b *TxBuilder, fee uint64) ([]byte, string, error) {
+	return fb.TxBuilderNativeBackend.Build(b, fee+1)
+}
+
+func (fb txBuilderFeeShiftBackend) CalculateFee(b *TxBuilder, witnessCount int) (uint64, error) {
+	fee, err := fb.TxBuilderNativeBackend.CalculateFee(b, witnessCount)
+
+	return fee + 1, err
+}
+
+func Test_TxBuilder_ParityBackend(t *testing.T) {
+	t.Parallel()
+
+	wallet, err := GenerateWallet(false)
+	require.NoError(t, err)
+
+	buildAndSign := func(t *testing.T, secondary ITxBuilderBackend) []TxBackendMismatch {
+		t.Helper()
+
+		var mismatches []TxBackendMismatch
+
+		backend := NewTxBuilderParityBackend(NewTxBuilderNativeBackend(), secondary, func(mismatch TxBackendMismatch) {
+			mismatches = append(mismatches, mismatch)
+		})
+
+		builder, err := NewTxBuilder(ResolveCardanoCliBinary(TestNetNetwork), WithTxBuilderBackend(backend))
+		require.NoError(t, err)
+
+		defer builder.Dispose()
+
+		builder.SetProtocolParameters(protocolParameters).SetTimeToLive(1000)
+		builder.AddInputs(NewTxInput("e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f", 1))
+		builder.AddOutputs(NewTxOutput("addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u", 2_000_000))
+
+		minUtxo, err := builder.CalculateMinUtxo(NewTxOutput("addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u", 0))
+		require.NoError(t, err)
+		require.Greater(t, minUtxo, uint64(0))
+
+		fee, err := builder.CalculateFee(1)
+		require.NoError(t, err)
+
+		builder.SetFee(fee)
+
+		txRaw, txHash, err := builder.Build()
+		require.NoError(t, err)
+
+		txHashNative, err := GetTxHash(txRaw)
+		require.NoError(t, err)
+
+		require.Equal(t, txHashNative, txHash)
+
+		_, err = builder.SignTx(txRaw, []ITxSigner{wallet})
+		require.NoError(t, err)
+
+		return mismatches
+	}
+
+	t.Run("same backends", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, buildAndSign(t, NewTxBuilderNativeBackend()))
+	})
+
+	t.Run("different backends", func(t *testing.T) {
+		t.Parallel()
+
+		mismatches := buildAndSign(t, txBuilderFeeShiftBackend{NewTxBuilderNativeBackend()})
+
+		require.Len(t, mismatches, 2)
+
+		assert.Equal(t, "fee", mismatches[0].Operation)
+		assert.Equal(t, "build", mismatches[1].Operation)
+		assert.NotEqual(t, mismatches[1].Primary, mismatches[1].Secondary)
+		require.NotEmpty(t, mismatches[1].Diff)
+		assert.Equal(t, "hash", mismatches[1].Diff[0].Section)
+		assert.Equal(t, "fee", mismatches[1].Diff[1].Section)
+		assert.Contains(t, mismatches[1].String(), "build mismatch")
+	})
+
+	t.Run("cli backend without time to live", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			builder    *TxBuilder
+			mismatches []TxBackendMismatch
+		)
+
+		fakeCli := clitest.NewFakeCardanoCli().
+			HandleFunc(func(call clitest.Call) clitest.Response {
+				fee, err := strconv.ParseUint(call.Flag("--fee"), 10, 64)
+				if err != nil {
+					return clitest.Response{Err: err}
+				}
+
+				txRaw, _, err := builder.buildRawTxNative(fee)
+				if err != nil {
+					return clitest.Response{Err: err}
+				}
+
+				return clitest.Response{Files: map[string][]byte{
+					"--out-file": clitest.TextEnvelope("Unwitnessed Tx BabbageEra", txRaw),
+				}}
+			}, "transaction", "build-raw").
+			HandleFunc(func(call clitest.Call) clitest.Response {
+				content, err := os.ReadFile(call.Flag("--tx-body-file"))
+				if err != nil {
+					return clitest.Response{Err: err}
+				}
+
+				txRaw, err := clitest.ReadTextEnvelope(content)
+				if err != nil {
+					return clitest.Response{Err: err}
+				}
+
+				txHash, err := GetTxHash(txRaw)
+
+				return clitest.Response{Stdout: txHash + "\n", Err: err}
+			}, "transaction", "txid")
+
+		backend := NewTxBuilderParityBackend(
+			NewTxBuilderNativeBackend(),
+			NewTxBuilderCliBackend("cardano-cli-fake", WithCommandRunner(fakeCli)),
+			func(mismatch TxBackendMismatch) {
+				mismatches = append(mismatches, mismatch)
+			})
+
+		builder, err = NewTxBuilder(ResolveCardanoCliBinary(TestNetNetwork), WithTxBuilderBackend(backend))
+		require.NoError(t, err)
+
+		defer builder.Dispose()
+
+		builder.SetProtocolParameters(protocolParameters).SetFee(200_000)
+		builder.AddInputs(NewTxInput(testInputHash, 1))
+		builder.AddOutputs(NewTxOutput(testAddr, 2_000_000))
+
+		txRaw, _, err := builder.Build()
+		require.NoError(t, err)
+
+		tx, err := NewTransaction(txRaw)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(0), tx.TimeToLive)
+		assert.Empty(t, mismatches)
+
+		buildCalls := fakeCli.CallsOf("transaction", "build-raw")
+		require.Len(t, buildCalls, 1)
+
+		assert.False(t, buildCalls[0].HasFlag("--invalid-hereafter"))
+		assert.False(t, buildCalls[0].HasFlag("--invalid-before"))
+	})
+}
+
+func Test_TxBuilder_CliBackend_FakeCli(t *testing.T) {
+	t.Parallel()
+
+	const addr = "addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u"
+
+	var builder *TxBuilder
+
+	fakeCli := clitest.NewFakeCardanoCli().
+		HandleFunc(func(call clitest.Call) clitest.Response {
+			fee, err := strconv.ParseUint(call.Flag("--fee"), 10, 64)
+			if err != nil {
+				return clitest.Response{Err: err}
+			}
+
+			txRaw, _, err := builder.buildRawTxNative(fee)
+			if err != nil {
+				return clitest.Response{Err: err}
+			}
+
+			return clitest.Response{Files: map[string][]byte{
+				"--out-file": clitest.TextEnvelope("Unwitnessed Tx BabbageEra", txRaw),
+			}}
+		}, "transaction", "build-raw").
+		HandleFunc(func(call clitest.Call) clitest.Response {
+			content, err := os.ReadFile(call.Flag("--tx-body-file"))
+			if err != nil {
+				return clitest.Response{Err: err}
+			}
+
+			txRaw, err := clitest.ReadTextEnvelope(content)
+			if err != nil {
+				return clitest.Response{Err: err}
+			}
+
+			txHash, err := GetTxHash(txRaw)
+
+			return clitest.Response{Stdout: txHash + "\n", Err: err}
+		}, "transaction", "txid").
+		Handle(clitest.Response{Stdout: "170000 Lovelace\n"}, "transaction", "calculate-min-fee").
+		Handle(clitest.Response{Stdout: "Lovelace 999\n"}, "transaction", "calculate-min-required-utxo")
+
+	builder, err := NewTxBuilder("cardano-cli-fake", WithCardanoCliBuild(WithCommandRunner(fakeCli)))
+	require.NoError(t, err)
+
+	defer builder.Dispose()
+
+	policyScript := NewPolicyScript([]string{
+		"d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21",
+		"cba89c7084bf0ce4bf404346b668a7e83c8c9c250d1cafd8d8996e41",
+	}, 2)
+
+	builder.SetProtocolParameters(protocolParameters).SetTimeToLive(1000).SetValidityStart(10).SetTestNetMagic(2)
+	builder.AddInputs(NewTxInput("e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f", 1))
+	builder.AddInputsWithScript(policyScript,
+		NewTxInput("e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f", 2))
+	builder.AddOutputs(NewTxOutput(addr, 2_000_000))
+
+	minUtxo, err := builder.CalculateMinUtxo(NewTxOutput(addr, 0))
+	require.NoError(t, err)
+	require.Equal(t, uint64(999), minUtxo)
+
+	fee, err := builder.CalculateFee(0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(170000), fee)
+
+	builder.SetFee(fee)
+
+	txRaw, txHash, err := builder.Build()
+	require.NoError(t, err)
+
+	txHashNative, err := GetTxHash(txRaw)
+	require.NoError(t, err)
+
+	assert.Equal(t, txHashNative, txHash)
+
+	buildCalls := fakeCli.CallsOf("transaction", "build-raw")
+	require.Len(t, buildCalls, 2)
+
+	assert.Equal(t, "0", buildCalls[0].Flag("--fee"))
+	assert.Equal(t, "170000", buildCalls[1].Flag("--fee"))
+	assert.Equal(t, "1000", buildCalls[1].Flag("--invalid-hereafter"))
+	assert.Equal(t, "10", buildCalls[1].Flag("--invalid-before"))
+	assert.Equal(t, []string{
+		"e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f#1",
+		"e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f#2",
+	}, buildCalls[1].Flags("--tx-in"))
+	assert.Len(t, buildCalls[1].Flags("--tx-in-script-file"), 1)
+	assert.Equal(t, []string{addr + "+2000000"}, buildCalls[1].Flags("--tx-out"))
+
+	feeCalls := fakeCli.CallsOf("transaction", "calculate-min-fee")
+	require.Len(t, feeCalls, 1)
+
+	assert.Equal(t, "2", feeCalls[0].Flag("--tx-in-count"))
+	// owner of the key input and both keys of the policy script
+	assert.Equal(t, "3", feeCalls[0].Flag("--witness-count"))
+	assert.Equal(t, "2", feeCalls[0].Flag("--testnet-magic"))
+}
@@ -0,0 +1,643 @@
+package core
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/igorcrevar/go-cardano-tx/core/bech32"
+)
+
+const (
+	poolIDBech32Prefix       = "pool"
+	dRepIDBech32Prefix       = "drep"
+	dRepScriptIDBech32Prefix = "drep_script"
+
+	// CIP-129 header byte: drep key type in high nibble, credential type in low nibble
+	cip129DRepKeyHashHeader    byte = 0x22
+	cip129DRepScriptHashHeader byte = 0x23
+
+	anchorDataHashSize = 32
+)
+
+func NewTxCredential(hash string, isScript bool) TxCredential {
+	return TxCredential{
+		Hash:     hash,
+		IsScript: isScript,
+	}
+}
+
+// NewTxCredentialFromKey returns key hash credential of the (stake) verification key
+func NewTxCredentialFromKey(verificationKey []byte) (TxCredential, error) {
+	keyHash, err := GetKeyHash(verificationKey)
+	if err != nil {
+		return TxCredential{}, err
+	}
+
+	return NewTxCredential(keyHash, false), nil
+}
+
+// NewTxCredentialFromScript returns script hash credential of the native script
+func NewTxCredentialFromScript(policyScript IPolicyScript) (TxCredential, error) {
+	ps, err := toPolicyScript(policyScript)
+	if err != nil {
+		return TxCredential{}, err
+	}
+
+	scriptHash, err := ps.PolicyID()
+	if err != nil {
+		return TxCredential{}, err
+	}
+
+	return NewTxCredential(scriptHash, true), nil
+}
+
+func (c TxCredential) getCbor() ([]interface{}, error) {
+	hash, err := hex.DecodeString(c.Hash)
+	if err != nil {
+		return nil, err
+	} else if len(hash) != KeyHashSize {
+		return nil, fmt.Errorf("invalid credential hash: %s", c.Hash)
+	}
+
+	if c.IsScript {
+		return []interface{}{txCredentialScriptHashType, hash}, nil
+	}
+
+	return []interface{}{txCredentialKeyHashType, hash}, nil
+}
+
+// GetPoolKeyHash returns hex encoded pool key hash from bech32 (pool1...) or hex pool id
+func GetPoolKeyHash(poolID string) (string, error) {
+	var (
+		poolKeyHash []byte
+		err         error
+	)
+
+	if strings.HasPrefix(poolID, poolIDBech32Prefix) {
+		var prefix string
+
+		prefix, poolKeyHash, err = bech32.DecodeToBase256(poolID)
+		if err == nil && prefix != poolIDBech32Prefix {
+			err = fmt.Errorf("unexpected prefix: %s", prefix)
+		}
+	} else {
+		poolKeyHash, err = hex.DecodeString(poolID)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("invalid pool id %s: %w", poolID, err)
+	} else if len(poolKeyHash) != KeyHashSize {
+		return "", fmt.Errorf("invalid pool id %s: invalid length %d", poolID, len(poolKeyHash))
+	}
+
+	return hex.EncodeToString(poolKeyHash), nil
+}
+
+// GetDRepID returns CIP-129 bech32 drep id (drep1...) of the drep credential
+func GetDRepID(drepCredential TxCredential) (string, error) {
+	hash, err := hex.DecodeString(drepCredential.Hash)
+	if err != nil {
+		return "", err
+	} else if len(hash) != KeyHashSize {
+		return "", fmt.Errorf("invalid drep credential hash: %s", drepCredential.Hash)
+	}
+
+	header := cip129DRepKeyHashHeader
+	if drepCredential.IsScript {
+		header = cip129DRepScriptHashHeader
+	}
+
+	return bech32.EncodeFromBase256(dRepIDBech32Prefix, append([]byte{header}, hash...))
+}
+
+// GetDRepCredential returns drep credential from CIP-129 drep id
+// or from legacy CIP-105 drep id (drep1... for key hash, drep_script1... for script hash)
+func GetDRepCredential(drepID string) (TxCredential, error) {
+	prefix, data, err := bech32.DecodeToBase256(drepID)
+	if err != nil {
+		return TxCredential{}, fmt.Errorf("invalid drep id %s: %w", drepID, err)
+	}
+
+	switch {
+	case prefix == dRepIDBech32Prefix && len(data) == KeyHashSize+1:
+		switch data[0] {
+		case cip129DRepKeyHashHeader:
+			return NewTxCredential(hex.EncodeToString(data[1:]), false), nil
+		case cip129DRepScriptHashHeader:
+			return NewTxCredential(hex.EncodeToString(data[1:]), true), nil
+		default:
+			return TxCredential{}, fmt.Errorf("invalid drep id %s: unexpected header %x", drepID, data[0])
+		}
+	case prefix == dRepIDBech32Prefix && len(data) == KeyHashSize:
+		return NewTxCredential(hex.EncodeToString(data), false), nil
+	case prefix == dRepScriptIDBech32Prefix && len(data) == KeyHashSize:
+		return NewTxCredential(hex.EncodeToString(data), true), nil
+	case prefix != dRepIDBech32Prefix && prefix != dRepScriptIDBech32Prefix:
+		return TxCredential{}, fmt.Errorf("invalid drep id %s: unexpected prefix: %s", drepID, prefix)
+	default:
+		return TxCredential{}, fmt.Errorf("invalid drep id %s: invalid length %d", drepID, len(data))
+	}
+}
+
+// NewTxDRep creates drep which is identified by credential (key hash or script hash)
+func NewTxDRep(drepCredential TxCredential) TxDRep {
+	if drepCredential.IsScript {
+		return TxDRep{Type: DRepScriptHashType, Hash: drepCredential.Hash}
+	}
+
+	return TxDRep{Type: DRepKeyHashType, Hash: drepCredential.Hash}
+}
+
+// NewTxDRepFromID creates drep from CIP-129 or CIP-105 bech32 drep id
+func NewTxDRepFromID(drepID string) (TxDRep, error) {
+	drepCredential, err := GetDRepCredential(drepID)
+	if err != nil {
+		return TxDRep{}, err
+	}
+
+	return NewTxDRep(drepCredential), nil
+}
+
+// NewTxDRepAlwaysAbstain creates predefined always abstain drep
+func NewTxDRepAlwaysAbstain() TxDRep {
+	return TxDRep{Type: DRepAlwaysAbstainType}
+}
+
+// NewTxDRepAlwaysNoConfidence creates predefined always no confidence drep
+func NewTxDRepAlwaysNoConfidence() TxDRep {
+	return TxDRep{Type: DRepAlwaysNoConfidenceType}
+}
+
+func (d TxDRep) getCbor() ([]interface{}, error) {
+	switch d.Type {
+	case DRepKeyHashType, DRepScriptHashType:
+		credential, err := NewTxCredential(d.Hash, d.Type == DRepScriptHashType).getCbor()
+		if err != nil {
+			return nil, err
+		}
+
+		return []interface{}{uint64(d.Type), credential[1]}, nil
+	case DRepAlwaysAbstainType, DRepAlwaysNoConfidenceType:
+		return []interface{}{uint64(d.Type)}, nil
+	default:
+		return nil, fmt.Errorf("unknown drep type: %d", d.Type)
+	}
+}
+
+func NewTxAnchor(url string, dataHash string) TxAnchor {
+	return TxAnchor{
+		URL:      url,
+		DataHash: dataHash,
+	}
+}
+
+// getAnchorCbor returns [url, data hash] or nil (encoded as null) if anchor is not set
+func getAnchorCbor(anchor *TxAnchor) (interface{}, error) {
+	if anchor == nil {
+		return nil, nil
+	}
+
+	dataHash, err := hex.DecodeString(anchor.DataHash)
+	if err != nil {
+		return nil, err
+	} else if len(dataHash) != anchorDataHashSize {
+		return nil, fmt.Errorf("invalid anchor data hash: %s", anchor.DataHash)
+	}
+
+	return []interface{}{anchor.URL, dataHash}, nil
+}
+
+// NewStakeRegistrationCertificate creates shelley stake registration certificate
+// (deposit is taken from protocol parameters)
+func NewStakeRegistrationCertificate(stakeCredential TxCredential) TxCertificate {
+	return TxCertificate{
+		Type:            StakeRegistrationCertificate,
+		StakeCredential: &stakeCredential,
+	}
+}
+
+// NewStakeDeregistrationCertificate creates shelley stake deregistration certificate
+// (refund is taken from protocol parameters)
+func NewStakeDeregistrationCertificate(stakeCredential TxCredential) TxCertificate {
+	return TxCertificate{
+		Type:            StakeDeregistrationCertificate,
+		StakeCredential: &stakeCredential,
+	}
+}
+
+// NewRegistrationCertificate creates conway stake registration certificate with explicit deposit
+func NewRegistrationCertificate(stakeCredential TxCredential, deposit uint64) TxCertificate {
+	return TxCertificate{
+		Type:            RegistrationCertificate,
+		StakeCredential: &stakeCredential,
+		Deposit:         deposit,
+	}
+}
+
+// NewUnregistrationCertificate creates conway stake deregistration certificate with explicit refund
+func NewUnregistrationCertificate(stakeCredential TxCredential, refund uint64) TxCertificate {
+	return TxCertificate{
+		Type:            UnregistrationCertificate,
+		StakeCredential: &stakeCredential,
+		Deposit:         refund,
+	}
+}
+
+// NewStakeDelegationCertificate creates certificate which delegates stake to the pool (bech32 or hex pool id)
+func NewStakeDelegationCertificate(stakeCredential TxCredential, poolID string) (TxCertificate, error) {
+	poolKeyHash, err := GetPoolKeyHash(poolID)
+	if err != nil {
+		return TxCertificate{}, err
+	}
+
+	return TxCertificate{
+		Type:            StakeDelegationCertificate,
+		StakeCredential: &stakeCredential,
+		PoolKeyHash:     poolKeyHash,
+	}, nil
+}
+
+// NewStakeRegDelegationCertificate creates conway certificate which registers stake credential
+// and delegates it to the pool in one step
+func NewStakeRegDelegationCertificate(
+	stakeCredential TxCredential, poolID string, deposit uint64,
+) (TxCertificate, error) {
+	certificate, err := NewStakeDelegationCertificate(stakeCredential, poolID)
+	if err != nil {
+		return TxCertificate{}, err
+	}
+
+	certificate.Type = StakeRegDelegationCertificate
+	certificate.Deposit = deposit
+
+	return certificate, nil
+}
+
+// NewVoteDelegationCertificate creates certificate which delegates votes of the stake credential to the drep
+func NewVoteDelegationCertificate(stakeCredential TxCredential, drep TxDRep) TxCertificate {
+	return TxCertificate{
+		Type:            VoteDelegationCertificate,
+		StakeCredential: &stakeCredential,
+		DRep:            &drep,
+	}
+}
+
+// NewStakeVoteDelegationCertificate creates certificate which delegates stake to the pool
+// and votes to the drep
+func NewStakeVoteDelegationCertificate(
+	stakeCredential TxCredential, poolID string, drep TxDRep,
+) (TxCertificate, error) {
+	certificate, err := NewStakeDelegationCertificate(stakeCredential, poolID)
+	if err != nil {
+		return TxCertificate{}, err
+	}
+
+	certificate.Type = StakeVoteDelegationCertificate
+	certificate.DRep = &drep
+
+	return certificate, nil
+}
+
+// NewVoteRegDelegationCertificate creates certificate which registers stake credential
+// and delegates its votes to the drep in one step
+func NewVoteRegDelegationCertificate(stakeCredential TxCredential, drep TxDRep, deposit uint64) TxCertificate {
+	certificate := NewVoteDelegationCertificate(stakeCredential, drep)
+	certificate.Type = VoteRegDelegationCertificate
+	certificate.Deposit = deposit
+
+	return certificate
+}
+
+// NewStakeVoteRegDelegationCertificate creates certificate which registers stake credential,
+// delegates stake to the pool and votes to the drep in one step
+func NewStakeVoteRegDelegationCertificate(
+	stakeCredential TxCredential, poolID string, drep TxDRep, deposit uint64,
+) (TxCertificate, error) {
+	certificate, err := NewStakeVoteDelegationCertificate(stakeCredential, poolID, drep)
+	if err != nil {
+		return TxCertificate{}, err
+	}
+
+	certificate.Type = StakeVoteRegDelegationCertificate
+	certificate.Deposit = deposit
+
+	return certificate, nil
+}
+
+// NewDRepRegistrationCertificate creates drep registration certificate. Anchor is optional
+func NewDRepRegistrationCertificate(drepCredential TxCredential, deposit uint64, anchor *TxAnchor) TxCertificate {
+	return TxCertificate{
+		Type:           DRepRegistrationCertificate,
+		DRepCredential: &drepCredential,
+		Deposit:        deposit,
+		Anchor:         anchor,
+	}
+}
+
+// NewDRepUpdateCertificate creates certificate which updates (or removes if nil) drep anchor
+func NewDRepUpdateCertificate(drepCredential TxCredential, anchor *TxAnchor) TxCertificate {
+	return TxCertificate{
+		Type:           DRepUpdateCertificate,
+		DRepCredential: &drepCredential,
+		Anchor:         anchor,
+	}
+}
+
+// NewDRepRetirementCertificate creates drep retirement certificate which refunds drep deposit
+func NewDRepRetirementCertificate(drepCredential TxCredential, refund uint64) TxCertificate {
+	return TxCertificate{
+		Type:           DRepUnregistrationCertificate,
+		DRepCredential: &drepCredential,
+		Deposit:        refund,
+	}
+}
+
+// MarshalCBOR encodes certificate. Certificate types which can not be created by this library are encoded from Raw
+func (c TxCertificate) MarshalCBOR() ([]byte, error) {
+	var fields []interface{}
+
+	switch c.Type {
+	case StakeRegistrationCertificate, StakeDeregistrationCertificate, StakeDelegationCertificate,
+		RegistrationCertificate, UnregistrationCertificate, VoteDelegationCertificate,
+		StakeVoteDelegationCertificate, StakeRegDelegationCertificate, VoteRegDelegationCertificate,
+		StakeVoteRegDelegationCertificate:
+		if c.StakeCredential == nil {
+			return nil, fmt.Errorf("missing stake credential for certificate type %d", c.Type)
+		}
+
+		stakeCredential, err := c.StakeCredential.getCbor()
+		if err != nil {
+			return nil, err
+		}
+
+		fields = []interface{}{uint64(c.Type), stakeCredential}
+	case DRepRegistrationCertificate, DRepUnregistrationCertificate, DRepUpdateCertificate:
+		if c.DRepCredential == nil {
+			return nil, fmt.Errorf("missing drep credential for certificate type %d", c.Type)
+		}
+
+		drepCredential, err := c.DRepCredential.getCbor()
+		if err != nil {
+			return nil, err
+		}
+
+		fields = []interface{}{uint64(c.Type), drepCredential}
+	default:
+		if c.Raw == nil {
+			return nil, fmt.Errorf("unsupported certificate type %d", c.Type)
+		}
+
+		return c.Raw, nil
+	}
+
+	switch c.Type {
+	case StakeDelegationCertificate, StakeVoteDelegationCertificate,
+		StakeRegDelegationCertificate, StakeVoteRegDelegationCertificate:
+		poolKeyHash, err := hex.DecodeString(c.PoolKeyHash)
+		if err != nil {
+			return nil, err
+		} else if len(poolKeyHash) != KeyHashSize {
+			return nil, fmt.Errorf("invalid pool key hash: %s", c.PoolKeyHash)
+		}
+
+		fields = append(fields, poolKeyHash)
+	}
+
+	switch c.Type {
+	case VoteDelegationCertificate, StakeVoteDelegationCertificate,
+		VoteRegDelegationCertificate, StakeVoteRegDelegationCertificate:
+		if c.DRep == nil {
+			return nil, fmt.Errorf("missing drep for certificate type %d", c.Type)
+		}
+
+		drep, err := c.DRep.getCbor()
+		if err != nil {
+			return nil, err
+		}
+
+		fields = append(fields, drep)
+	}
+
+	switch c.Type {
+	case RegistrationCertificate, UnregistrationCertificate, StakeRegDelegationCertificate,
+		VoteRegDelegationCertificate, StakeVoteRegDelegationCertificate,
+		DRepRegistrationCertificate, DRepUnregistrationCertificate:
+		fields = append(fields, c.Deposit)
+	}
+
+	if c.Type == DRepRegistrationCertificate || c.Type == DRepUpdateCertificate {
+		anchor, err := getAnchorCbor(c.Anchor)
+		if err != nil {
+			return nil, err
+		}
+
+		fields = append(fields, anchor)
+	}
+
+	return cbor.Marshal(fields)
+}
+
+// GetDepositAndRefund returns lovelace deposited or refunded by the certificate
+func (c TxCertificate) GetDepositAndRefund(protocolParams ProtocolParameters) (deposit uint64, refund uint64) {
+	switch c.Type {
+	case StakeRegistrationCertificate:
+		return protocolParams.StakeAddressDeposit, 0
+	case StakeDeregistrationCertificate:
+		return 0, protocolParams.StakeAddressDeposit
+	case RegistrationCertificate, StakeRegDelegationCertificate,
+		VoteRegDelegationCertificate, StakeVoteRegDelegationCertificate, DRepRegistrationCertificate:
+		return c.Deposit, 0
+	case UnregistrationCertificate, DRepUnregistrationCertificate:
+		return 0, c.Deposit
+	default:
+		return 0, 0
+	}
+}
+
+// isWitnessRequired returns true if owner of the stake credential must witness the certificate
+// (shelley stake registration is the only one which does not require it)
+func (c TxCertificate) isWitnessRequired() bool {
+	return c.StakeCredential != nil && c.Type != StakeRegistrationCertificate
+}
+
+type txCertificateWithScript struct {
+	certificate  TxCertificate
+	policyScript IPolicyScript
+}
+
+// AddCertificates adds certificates of key hash credentials
+func (b *TxBuilder) AddCertificates(certificates ...TxCertificate) *TxBuilder {
+	for _, certificate := range certificates {
+		b.certificates = append(b.certificates, txCertificateWithScript{
+			certificate: certificate,
+		})
+	}
+
+	return b
+}
+
+// AddCertificatesWithScript adds certificates of the native script credential
+func (b *TxBuilder) AddCertificatesWithScript(script IPolicyScript, certificates ...TxCertificate) *TxBuilder {
+	for _, certificate := range certificates {
+		b.certificates = append(b.certificates, txCertificateWithScript{
+			certificate:  certificate,
+			policyScript: script,
+		})
+	}
+
+	return b
+}
+
+// GetImplicitCoin returns lovelace which transaction consumes besides inputs (withdrawals and refunds)
+// and lovelace which it produces besides outputs and fee (certificate and proposal deposits).
+// Balanced transaction satisfies: inputs + consumed = outputs + fee + produced
+func (b *TxBuilder) GetImplicitCoin() (consumed uint64, produced uint64, err error) {
+	protocolParams, err := b.getProtocolParameters()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, cert := range b.certificates {
+		deposit, refund := cert.certificate.GetDepositAndRefund(protocolParams)
+		produced += deposit
+		consumed += refund
+	}
+
+	for _, w := range b.withdrawals {
+		consumed += w.withdrawal.Amount
+	}
+
+	for _, proposal := range b.proposals {
+		produced += proposal.Deposit
+	}
+
+	return consumed, produced, nil
+}
+
+func (b *TxBuilder) getCertificatesCbor(isConway bool) (interface{}, error) {
+	certificates := make([]cbor.RawMessage, len(b.certificates))
+
+	for i, cert := range b.certificates {
+		certificateBytes, err := cert.certificate.MarshalCBOR()
+		if err != nil {
+			return nil, fmt.Errorf("certificate %d: %w", i, err)
+		}
+
+		certificates[i] = certificateBytes
+	}
+
+	return newCborSet(certificates, isConway), nil
+}
+
+// getStakeKeyHashesToSign returns key hashes of stake credentials which must witness the transaction
+func (b *TxBuilder) getStakeKeyHashesToSign() (keyHashes []string) {
+	seen := map[string]bool{}
+
+	add := func(credential TxCredential) {
+		if !credential.IsScript && !seen[credential.Hash] {
+			seen[credential.Hash] = true
+			keyHashes = append(keyHashes, credential.Hash)
+		}
+	}
+
+	for _, cert := range b.certificates {
+		if cert.certificate.isWitnessRequired() {
+			add(*cert.certificate.StakeCredential)
+		}
+	}
+
+	for _, w := range b.withdrawals {
+		// invalid reward address is reported when the transaction is built
+		if _, credential, err := w.withdrawal.getStakeCredential(); err == nil {
+			add(credential)
+		}
+	}
+
+	return keyHashes
+}
+
+// applyCertificates adds cardano-cli build-raw arguments for certificates
+func (b *TxBuilder) applyCertificates(args *[]string, isConway bool) error {
+	// cardano-cli expects conway certificates text envelope since conway era
+	certificateJSONType := "CertificateShelley"
+	if isConway {
+		certificateJSONType = "CertificateConway"
+	}
+
+	for i, cert := range b.certificates {
+		certificateBytes, err := cert.certificate.MarshalCBOR()
+		if err != nil {
+			return fmt.Errorf("certificate %d: %w", i, err)
+		}
+
+		certificateJSON, err := json.Marshal(map[string]interface{}{
+			"type":        certificateJSONType,
+			"description": "",
+			"cborHex":     hex.EncodeToString(certificateBytes),
+		})
+		if err != nil {
+			return err
+		}
+
+		certificateFilePath := filepath.Join(b.baseDirectory, fmt.Sprintf("certificate_%d.json", i))
+		if err := os.WriteFile(certificateFilePath, certificateJSON, FilePermission); err != nil {
+			return err
+		}
+
+		*args = append(*args, "--certificate-file", certificateFilePath)
+
+		if cert.policyScript == nil {
+			continue
+		}
+
+		policyScriptJSON, err := cert.policyScript.GetPolicyScriptJSON()
+		if err != nil {
+			return err
+		}
+
+		policyFilePath := filepath.Join(b.baseDirectory, fmt.Sprintf("certificate_policy_%d.json", i))
+		if err := os.WriteFile(policyFilePath, policyScriptJSON, FilePermission); err != nil {
+			return err
+		}
+
+		*args = append(*args, "--certificate-script-file", policyFilePath)
+	}
+
+	return nil
+}
+
+// createStakeKeyWitness creates stake key witness of the signer
+// returns nil if signer does not have stake key or transaction (certificates, withdrawals
+// or required signers) does not require its witness
+func (b *TxBuilder) createStakeKeyWitness(txRaw []byte, signer ITxSigner) ([]byte, error) {
+	stakeSigner, ok := signer.(ITxStakeSigner)
+	if !ok {
+		return nil, nil
+	}
+
+	signingKey, verificationKey := stakeSigner.GetStakeKeys()
+	if len(signingKey) == 0 || len(verificationKey) == 0 {
+		return nil, nil
+	}
+
+	keyHash, err := GetKeyHash(verificationKey)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stakeKeyHash := range append(b.getStakeKeyHashesToSign(), b.getRequiredSigners()...) {
+		if stakeKeyHash == keyHash {
+			return b.CreateTxWitness(txRaw, &Wallet{
+				SigningKey:      signingKey,
+				VerificationKey: verificationKey,
+			})
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,414 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/igorcrevar/go-cardano-tx/core/bech32"
+	"github.com/igorcrevar/go-cardano-tx/core/clitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPoolKeyHash(t *testing.T) {
+	t.Parallel()
+
+	const poolKeyHash = "2c4e1fc7ae53aa0ebeb14a4e8d5d8a5fd4fa0bd9fd3c6e25d8d4e06e"
+
+	poolID, err := bech32.EncodeFromBase256("pool", mustDecodeHex(t, poolKeyHash))
+	require.NoError(t, err)
+
+	result, err := GetPoolKeyHash(poolID)
+	require.NoError(t, err)
+
+	assert.Equal(t, poolKeyHash, result)
+
+	result, err = GetPoolKeyHash(poolKeyHash)
+	require.NoError(t, err)
+
+	assert.Equal(t, poolKeyHash, result)
+
+	_, err = GetPoolKeyHash(poolKeyHash[2:])
+	require.ErrorContains(t, err, "invalid length")
+
+	_, err = GetPoolKeyHash(poolID[:len(poolID)-1] + "q")
+	require.ErrorContains(t, err, "invalid pool id")
+
+	poolVrf, err := bech32.EncodeFromBase256("pool_vrf", mustDecodeHex(t, poolKeyHash))
+	require.NoError(t, err)
+
+	_, err = GetPoolKeyHash(poolVrf)
+	require.ErrorContains(t, err, "unexpected prefix")
+}
+
+func TestGetDRepID(t *testing.T) {
+	t.Parallel()
+
+	const drepHash = "9fd3c6e25d8d4e06e6d5f40a12c4e1fc7ae53aa0ebeb14a4e8d5d8a5"
+
+	for _, isScript := range []bool{false, true} {
+		credential := NewTxCredential(drepHash, isScript)
+
+		drepID, err := GetDRepID(credential)
+		require.NoError(t, err)
+
+		prefix, data, err := bech32.DecodeToBase256(drepID)
+		require.NoError(t, err)
+
+		assert.Equal(t, "drep", prefix)
+		assert.Equal(t, drepHash, hex.EncodeToString(data[1:]))
+
+		result, err := GetDRepCredential(drepID)
+		require.NoError(t, err)
+
+		assert.Equal(t, credential, result)
+	}
+
+	drepID, err := GetDRepID(NewTxCredential(drepHash, false))
+	require.NoError(t, err)
+
+	_, data, err := bech32.DecodeToBase256(drepID)
+	require.NoError(t, err)
+
+	assert.Equal(t, byte(0x22), data[0])
+
+	// CIP-105 drep ids
+	legacyID, err := bech32.EncodeFromBase256("drep", mustDecodeHex(t, drepHash))
+	require.NoError(t, err)
+
+	result, err := GetDRepCredential(legacyID)
+	require.NoError(t, err)
+
+	assert.Equal(t, NewTxCredential(drepHash, false), result)
+
+	legacyID, err = bech32.EncodeFromBase256("drep_script", mustDecodeHex(t, drepHash))
+	require.NoError(t, err)
+
+	result, err = GetDRepCredential(legacyID)
+	require.NoError(t, err)
+
+	assert.Equal(t, NewTxCredential(drepHash, true), result)
+
+	invalidHeaderID, err := bech32.EncodeFromBase256("drep", append([]byte{0x12}, mustDecodeHex(t, drepHash)...))
+	require.NoError(t, err)
+
+	_, err = GetDRepCredential(invalidHeaderID)
+	require.ErrorContains(t, err, "unexpected header")
+
+	poolID, err := bech32.EncodeFromBase256("pool", mustDecodeHex(t, drepHash))
+	require.NoError(t, err)
+
+	_, err = GetDRepCredential(poolID)
+	require.ErrorContains(t, err, "unexpected prefix")
+
+	_, err = GetDRepID(NewTxCredential(drepHash[2:], false))
+	require.ErrorContains(t, err, "invalid drep credential hash")
+}
+
+func Test_TxBuilder_Certificates(t *testing.T) {
+	t.Parallel()
+
+	const (
+		poolKeyHash = "2c4e1fc7ae53aa0ebeb14a4e8d5d8a5fd4fa0bd9fd3c6e25d8d4e06e"
+	)
+
+	conwayProtocolParams := bytes.Replace(conwayProtocolParameters,
+		[]byte(`"stakeAddressDeposit":0`), []byte(`"stakeAddressDeposit":2000000`), 1)
+
+	wallet, err := GenerateWallet(true)
+	require.NoError(t, err)
+
+	stakeCredential, err := NewTxCredentialFromKey(wallet.StakeVerificationKey)
+	require.NoError(t, err)
+
+	poolID, err := bech32.EncodeFromBase256("pool", mustDecodeHex(t, poolKeyHash))
+	require.NoError(t, err)
+
+	delegation, err := NewStakeDelegationCertificate(stakeCredential, poolID)
+	require.NoError(t, err)
+
+	t.Run("registration and delegation", func(t *testing.T) {
+		t.Parallel()
+
+		builder := newTestTxBuilder(t, conwayProtocolParams)
+		builder.AddCertificates(NewRegistrationCertificate(stakeCredential, 2_000_000), delegation)
+
+		consumed, produced, err := builder.GetImplicitCoin()
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(0), consumed)
+		assert.Equal(t, uint64(2_000_000), produced)
+
+		// payment key and stake key
+		witnessCount, err := builder.getWitnessCount()
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, witnessCount)
+
+		txRaw, _, err := builder.Build()
+		require.NoError(t, err)
+
+		tx, err := NewTransaction(txRaw)
+		require.NoError(t, err)
+
+		require.Len(t, tx.Certificates, 2)
+		assert.Equal(t, RegistrationCertificate, tx.Certificates[0].Type)
+		assert.Equal(t, &stakeCredential, tx.Certificates[0].StakeCredential)
+		assert.Equal(t, uint64(2_000_000), tx.Certificates[0].Deposit)
+		assert.Equal(t, StakeDelegationCertificate, tx.Certificates[1].Type)
+		assert.Equal(t, poolKeyHash, tx.Certificates[1].PoolKeyHash)
+
+		txSigned, err := builder.SignTx(txRaw, []ITxSigner{wallet})
+		require.NoError(t, err)
+
+		tx, err = NewTransaction(txSigned)
+		require.NoError(t, err)
+
+		require.Len(t, tx.VKeyWitnesses, 2)
+		assert.ElementsMatch(t, [][]byte{wallet.VerificationKey, wallet.StakeVerificationKey},
+			[][]byte{tx.VKeyWitnesses[0].VKey, tx.VKeyWitnesses[1].VKey})
+	})
+
+	t.Run("shelley registration and deregistration", func(t *testing.T) {
+		t.Parallel()
+
+		builder := newTestTxBuilder(t, conwayProtocolParams)
+		builder.AddCertificates(NewStakeRegistrationCertificate(stakeCredential))
+
+		consumed, produced, err := builder.GetImplicitCoin()
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(0), consumed)
+		assert.Equal(t, uint64(2_000_000), produced)
+
+		txRaw, _, err := builder.Build()
+		require.NoError(t, err)
+
+		// stake key does not witness shelley registration
+		txSigned, err := builder.SignTx(txRaw, []ITxSigner{wallet})
+		require.NoError(t, err)
+
+		tx, err := NewTransaction(txSigned)
+		require.NoError(t, err)
+
+		assert.Len(t, tx.VKeyWitnesses, 1)
+
+		builder = newTestTxBuilder(t, conwayProtocolParams)
+		builder.AddCertificates(NewStakeDeregistrationCertificate(stakeCredential))
+
+		consumed, produced, err = builder.GetImplicitCoin()
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(2_000_000), consumed)
+		assert.Equal(t, uint64(0), produced)
+	})
+
+	t.Run("script credential", func(t *testing.T) {
+		t.Parallel()
+
+		policyScript := NewPolicyScript([]string{
+			"2411b7ef3c33d35c2f1e1a2fa4ef0a63e1aa5fe9ac9cd8ca4d1c1b09",
+			"9fd3c6e25d8d4e06e6d5f40a12c4e1fc7ae53aa0ebeb14a4e8d5d8a5",
+		}, 2)
+
+		scriptCredential, err := NewTxCredentialFromScript(policyScript)
+		require.NoError(t, err)
+
+		builder := newTestTxBuilder(t, conwayProtocolParams)
+		builder.AddCertificatesWithScript(policyScript, NewUnregistrationCertificate(scriptCredential, 2_000_000))
+
+		// payment key and two keys of the script
+		witnessCount, err := builder.getWitnessCount()
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, witnessCount)
+
+		txRaw, _, err := builder.Build()
+		require.NoError(t, err)
+
+		tx, err := NewTransaction(txRaw)
+		require.NoError(t, err)
+
+		require.Len(t, tx.NativeScripts, 1)
+		assert.Equal(t, &scriptCredential, tx.Certificates[0].StakeCredential)
+	})
+
+	t.Run("vote delegation", func(t *testing.T) {
+		t.Parallel()
+
+		drepWallet, err := GenerateDRepWallet()
+		require.NoError(t, err)
+
+		drepID, err := drepWallet.GetDRepID()
+		require.NoError(t, err)
+
+		drep, err := NewTxDRepFromID(drepID)
+		require.NoError(t, err)
+
+		stakeVoteRegDelegation, err := NewStakeVoteRegDelegationCertificate(
+			stakeCredential, poolID, NewTxDRepAlwaysAbstain(), 2_000_000)
+		require.NoError(t, err)
+
+		stakeVoteDelegation, err := NewStakeVoteDelegationCertificate(
+			stakeCredential, poolID, NewTxDRepAlwaysNoConfidence())
+		require.NoError(t, err)
+
+		builder := newTestTxBuilder(t, conwayProtocolParams)
+		builder.AddCertificates(
+			NewVoteDelegationCertificate(stakeCredential, drep),
+			NewVoteRegDelegationCertificate(stakeCredential, drep, 2_000_000),
+			stakeVoteRegDelegation,
+			stakeVoteDelegation,
+		)
+
+		consumed, produced, err := builder.GetImplicitCoin()
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(0), consumed)
+		assert.Equal(t, uint64(4_000_000), produced)
+
+		txRaw, _, err := builder.Build()
+		require.NoError(t, err)
+
+		tx, err := NewTransaction(txRaw)
+		require.NoError(t, err)
+
+		require.Len(t, tx.Certificates, 4)
+		assert.Equal(t, VoteDelegationCertificate, tx.Certificates[0].Type)
+		assert.Equal(t, &drep, tx.Certificates[0].DRep)
+		assert.Equal(t, VoteRegDelegationCertificate, tx.Certificates[1].Type)
+		assert.Equal(t, &drep, tx.Certificates[1].DRep)
+		assert.Equal(t, uint64(2_000_000), tx.Certificates[1].Deposit)
+		assert.Equal(t, StakeVoteRegDelegationCertificate, tx.Certificates[2].Type)
+		assert.Equal(t, poolKeyHash, tx.Certificates[2].PoolKeyHash)
+		assert.Equal(t, &TxDRep{Type: DRepAlwaysAbstainType}, tx.Certificates[2].DRep)
+		assert.Equal(t, uint64(2_000_000), tx.Certificates[2].Deposit)
+		assert.Equal(t, StakeVoteDelegationCertificate, tx.Certificates[3].Type)
+		assert.Equal(t, poolKeyHash, tx.Certificates[3].PoolKeyHash)
+		assert.Equal(t, &TxDRep{Type: DRepAlwaysNoConfidenceType}, tx.Certificates[3].DRep)
+
+		for _, cert := range tx.Certificates {
+			certificateCbor, err := cert.MarshalCBOR()
+			require.NoError(t, err)
+
+			cert.Raw = nil
+
+			reencoded, err := cert.MarshalCBOR()
+			require.NoError(t, err)
+
+			assert.Equal(t, certificateCbor, reencoded)
+		}
+	})
+
+	t.Run("drep", func(t *testing.T) {
+		t.Parallel()
+
+		drepWallet, err := GenerateDRepWallet()
+		require.NoError(t, err)
+
+		drepCredential, err := drepWallet.GetCredential()
+		require.NoError(t, err)
+
+		anchor := NewTxAnchor("https://example.com/drep.json", strings.Repeat("cd", 32))
+
+		builder := newTestTxBuilder(t, conwayProtocolParams)
+		builder.AddCertificates(
+			NewDRepRegistrationCertificate(drepCredential, 500_000_000, &anchor),
+			NewDRepUpdateCertificate(drepCredential, nil),
+			NewDRepRetirementCertificate(drepCredential, 500_000_000),
+		)
+
+		consumed, produced, err := builder.GetImplicitCoin()
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(500_000_000), consumed)
+		assert.Equal(t, uint64(500_000_000), produced)
+
+		// payment key and drep key
+		witnessCount, err := builder.getWitnessCount()
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, witnessCount)
+
+		txRaw, _, err := builder.Build()
+		require.NoError(t, err)
+
+		txSigned, err := builder.SignTx(txRaw, []ITxSigner{wallet, drepWallet})
+		require.NoError(t, err)
+
+		tx, err := NewTransaction(txSigned)
+		require.NoError(t, err)
+
+		require.Len(t, tx.Certificates, 3)
+		assert.Equal(t, DRepRegistrationCertificate, tx.Certificates[0].Type)
+		assert.Equal(t, &drepCredential, tx.Certificates[0].DRepCredential)
+		assert.Equal(t, uint64(500_000_000), tx.Certificates[0].Deposit)
+		assert.Equal(t, &anchor, tx.Certificates[0].Anchor)
+		assert.Equal(t, DRepUpdateCertificate, tx.Certificates[1].Type)
+		assert.Nil(t, tx.Certificates[1].Anchor)
+		assert.Equal(t, DRepUnregistrationCertificate, tx.Certificates[2].Type)
+		assert.Equal(t, uint64(500_000_000), tx.Certificates[2].Deposit)
+
+		require.Len(t, tx.VKeyWitnesses, 2)
+		assert.ElementsMatch(t, [][]byte{wallet.VerificationKey, drepWallet.VerificationKey},
+			[][]byte{tx.VKeyWitnesses[0].VKey, tx.VKeyWitnesses[1].VKey})
+
+		invalidAnchor := NewTxAnchor("https://example.com/drep.json", "cd")
+
+		_, err = NewDRepUpdateCertificate(drepCredential, &invalidAnchor).MarshalCBOR()
+		require.ErrorContains(t, err, "invalid anchor data hash")
+	})
+
+	t.Run("cardano-cli", func(t *testing.T) {
+		t.Parallel()
+
+		var builder *TxBuilder
+
+		fakeCli := clitest.NewFakeCardanoCli().
+			HandleFunc(func(call clitest.Call) clitest.Response {
+				txRaw, _, err := builder.buildRawTxNative(0)
+				if err != nil {
+					return clitest.Response{Err: err}
+				}
+
+				return clitest.Response{Files: map[string][]byte{
+					"--out-file": clitest.TextEnvelope("Unwitnessed Tx ConwayEra", txRaw),
+				}}
+			}, "transaction", "build-raw").
+			Handle(clitest.Response{Stdout: strings.Repeat("ab", 32)}, "transaction", "txid").
+			Handle(clitest.Response{Stdout: "170000 Lovelace\n"}, "transaction", "calculate-min-fee")
+
+		builder = newTestTxBuilder(t, conwayProtocolParams, WithCardanoCliBuild(WithCommandRunner(fakeCli)))
+		builder.AddCertificates(delegation)
+
+		_, err := builder.CalculateFee(0)
+		require.NoError(t, err)
+
+		feeCalls := fakeCli.CallsOf("transaction", "calculate-min-fee")
+		require.Len(t, feeCalls, 1)
+
+		// payment key and stake key of the delegation
+		assert.Equal(t, "2", feeCalls[0].Flag("--witness-count"))
+
+		_, _, err = builder.Build()
+		require.NoError(t, err)
+
+		calls := fakeCli.CallsOf("transaction", "build-raw")
+		require.Len(t, calls, 2)
+
+		content, err := os.ReadFile(calls[1].Flag("--certificate-file"))
+		require.NoError(t, err)
+
+		certificateCbor, err := clitest.ReadTextEnvelope(content)
+		require.NoError(t, err)
+
+		delegationCbor, err := delegation.MarshalCBOR()
+		require.NoError(t, err)
+
+		assert.Contains(t, string(content), `"type":"CertificateConway"`)
+		assert.Equal(t, delegationCbor, certificateCbor)
+	})
+}
@@ -0,0 +1,1047 @@
+package core
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/fxamacker/cbor/v2"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	txBodyCertificatesKey = 4
+	txBodyWithdrawalsKey  = 5
+
+	txCredentialKeyHashType    = 0
+	txCredentialScriptHashType = 1
+)
+
+var ErrInvalidTransaction = errors.New("invalid transaction")
+
+type TxCertificateType uint64
+
+const (
+	StakeRegistrationCertificate       TxCertificateType = 0
+	StakeDeregistrationCertificate     TxCertificateType = 1
+	StakeDelegationCertificate         TxCertificateType = 2
+	PoolRegistrationCertificate        TxCertificateType = 3
+	PoolRetirementCertificate          TxCertificateType = 4
+	RegistrationCertificate            TxCertificateType = 7
+	UnregistrationCertificate          TxCertificateType = 8
+	VoteDelegationCertificate          TxCertificateType = 9
+	StakeVoteDelegationCertificate     TxCertificateType = 10
+	StakeRegDelegationCertificate      TxCertificateType = 11
+	VoteRegDelegationCertificate       TxCertificateType = 12
+	StakeVoteRegDelegationCertificate  TxCertificateType = 13
+	AuthCommitteeHotCertificate        TxCertificateType = 14
+	ResignCommitteeColdCertificate     TxCertificateType = 15
+	DRepRegistrationCertificate        TxCertificateType = 16
+	DRepUnregistrationCertificate      TxCertificateType = 17
+	DRepUpdateCertificate              TxCertificateType = 18
+	txCertificateTypeWithStakeCredLast                   = StakeVoteRegDelegationCertificate
+)
+
+// TxCredential is stake (or payment) credential - key hash or script hash
+type TxCredential struct {
+	Hash     string `json:"hash"`
+	IsScript bool   `json:"isScript"`
+}
+
+type TxDRepType uint64
+
+const (
+	DRepKeyHashType            TxDRepType = 0
+	DRepScriptHashType         TxDRepType = 1
+	DRepAlwaysAbstainType      TxDRepType = 2
+	DRepAlwaysNoConfidenceType TxDRepType = 3
+)
+
+// TxDRep is delegated representative - key hash, script hash, always abstain or always no confidence
+type TxDRep struct {
+	Type TxDRepType `json:"type"`
+	Hash string     `json:"hash,omitempty"`
+}
+
+// TxAnchor is url and blake2b-256 hash of the off-chain metadata
+type TxAnchor struct {
+	URL      string `json:"url"`
+	DataHash string `json:"dataHash"`
+}
+
+// TxCertificate is decoded certificate. Fields which are not part of the certificate type are empty
+type TxCertificate struct {
+	Type            TxCertificateType `json:"type"`
+	StakeCredential *TxCredential     `json:"stakeCredential,omitempty"`
+	PoolKeyHash     string            `json:"poolKeyHash,omitempty"`
+	DRep            *TxDRep           `json:"drep,omitempty"`
+	DRepCredential  *TxCredential     `json:"drepCredential,omitempty"`
+	Deposit         uint64            `json:"deposit,omitempty"`
+	Anchor          *TxAnchor         `json:"anchor,omitempty"`
+	Raw             []byte            `json:"-"` // certificate cbor
+}
+
+type TxWithdrawal struct {
+	Address string `json:"addr"`
+	Amount  uint64 `json:"amount"`
+}
+
+// MintTokenAmount is minted (positive amount) or burned (negative amount) token
+type MintTokenAmount struct {
+	Token
+	Amount int64 `json:"val"`
+}
+
+func NewMintTokenAmount(token Token, amount int64) MintTokenAmount {
+	return MintTokenAmount{
+		Token:  token,
+		Amount: amount,
+	}
+}
+
+func (mt MintTokenAmount) TokenName() string {
+	return mt.Token.String()
+}
+
+func (mt MintTokenAmount) String() string {
+	return fmt.Sprintf("%d %s.%s", mt.Amount, mt.PolicyID, hex.EncodeToString([]byte(mt.Name)))
+}
+
+type TxVKeyWitness struct {
+	VKey      []byte `json:"vkey"`
+	Signature []byte `json:"signature"`
+}
+
+// Transaction is decoded cardano transaction
+type Transaction struct {
+	Hash          string                 `json:"hash"`
+	Inputs        []TxInput              `json:"inputs"`
+	Outputs       []TxOutput             `json:"outputs"`
+	Fee           uint64                 `json:"fee"`
+	TimeToLive    uint64                 `json:"ttl,omitempty"`
+	ValidityStart uint64                 `json:"validityStart,omitempty"`
+	Mint          []MintTokenAmount      `json:"mint,omitempty"`
+	Certificates  []TxCertificate        `json:"certificates,omitempty"`
+	Withdrawals   []TxWithdrawal         `json:"withdrawals,omitempty"`
+	VKeyWitnesses []TxVKeyWitness        `json:"vkeyWitnesses,omitempty"`
+	NativeScripts []PolicyScript         `json:"nativeScripts,omitempty"`
+	Metadata      map[uint64]interface{} `json:"metadata,omitempty"`
+	IsValid       bool                   `json:"isValid"`
+
+	ScriptDataHash   string         `json:"scriptDataHash,omitempty"`
+	CollateralInputs []TxInput      `json:"collateralInputs,omitempty"`
+	CollateralReturn *TxOutput      `json:"collateralReturn,omitempty"`
+	TotalCollateral  uint64         `json:"totalCollateral,omitempty"`
+	ReferenceInputs  []TxInput      `json:"referenceInputs,omitempty"`
+	RequiredSigners  []string       `json:"requiredSigners,omitempty"`
+	PlutusScripts    []PlutusScript `json:"plutusScripts,omitempty"`
+	Datums           [][]byte       `json:"datums,omitempty"`
+	Redeemers        []TxRedeemer   `json:"redeemers,omitempty"`
+
+	VotingProcedures []TxVotingProcedure `json:"votingProcedures,omitempty"`
+	Proposals        []TxProposal        `json:"proposals,omitempty"`
+}
+
+// NewTransaction decodes transaction cbor (witnessed or not)
+func NewTransaction(txRaw []byte) (*Transaction, error) {
+	var txParts []cbor.RawMessage
+
+	if err := cbor.Unmarshal(txRaw, &txParts); err != nil {
+		return nil, errors.Join(ErrInvalidTransaction, err)
+	}
+
+	// shelley era transactions do not have validity flag
+	if len(txParts) == 3 {
+		txParts = []cbor.RawMessage{txParts[0], txParts[1], cbor.RawMessage{0xf5}, txParts[2]}
+	} else if len(txParts) != 4 {
+		return nil, fmt.Errorf("%w: expected 4 elements, got %d", ErrInvalidTransaction, len(txParts))
+	}
+
+	txHash := blake2b.Sum256(txParts[0])
+
+	tx := &Transaction{
+		Hash: hex.EncodeToString(txHash[:]),
+	}
+
+	if err := cbor.Unmarshal(txParts[2], &tx.IsValid); err != nil {
+		return nil, errors.Join(ErrInvalidTransaction, err)
+	}
+
+	if err := tx.decodeBody(txParts[0]); err != nil {
+		return nil, errors.Join(ErrInvalidTransaction, err)
+	}
+
+	if err := tx.decodeWitnessSet(txParts[1]); err != nil {
+		return nil, errors.Join(ErrInvalidTransaction, err)
+	}
+
+	if err := tx.decodeAuxiliaryData(txParts[3]); err != nil {
+		return nil, errors.Join(ErrInvalidTransaction, err)
+	}
+
+	return tx, nil
+}
+
+func (tx *Transaction) decodeBody(data []byte) (err error) {
+	var body map[uint64]cbor.RawMessage
+
+	if err := cbor.Unmarshal(data, &body); err != nil {
+		return err
+	}
+
+	if tx.Inputs, err = decodeTxInputs(body[txBodyInputsKey]); err != nil {
+		return fmt.Errorf("inputs: %w", err)
+	}
+
+	var outputs []cbor.RawMessage
+
+	if err := cbor.Unmarshal(body[txBodyOutputsKey], &outputs); err != nil {
+		return fmt.Errorf("outputs: %w", err)
+	}
+
+	tx.Outputs = make([]TxOutput, len(outputs))
+
+	for i, output := range outputs {
+		if tx.Outputs[i], err = decodeTxOutput(output); err != nil {
+			return fmt.Errorf("output %d: %w", i, err)
+		}
+	}
+
+	if err := cbor.Unmarshal(body[txBodyFeeKey], &tx.Fee); err != nil {
+		return fmt.Errorf("fee: %w", err)
+	}
+
+	if err := unmarshalOptionalCbor(body[txBodyTimeToLiveKey], &tx.TimeToLive); err != nil {
+		return fmt.Errorf("ttl: %w", err)
+	}
+
+	if err := unmarshalOptionalCbor(body[txBodyValidityStartKey], &tx.ValidityStart); err != nil {
+		return fmt.Errorf("validity start: %w", err)
+	}
+
+	if tx.Mint, err = decodeTxMint(body[txBodyMintKey]); err != nil {
+		return fmt.Errorf("mint: %w", err)
+	}
+
+	if tx.Certificates, err = decodeTxCertificates(body[txBodyCertificatesKey]); err != nil {
+		return fmt.Errorf("certificates: %w", err)
+	}
+
+	if tx.Withdrawals, err = decodeTxWithdrawals(body[txBodyWithdrawalsKey]); err != nil {
+		return fmt.Errorf("withdrawals: %w", err)
+	}
+
+	var scriptDataHash []byte
+
+	if err := unmarshalOptionalCbor(body[txBodyScriptDataHashKey], &scriptDataHash); err != nil {
+		return fmt.Errorf("script data hash: %w", err)
+	}
+
+	tx.ScriptDataHash = hex.EncodeToString(scriptDataHash)
+
+	if tx.CollateralInputs, err = decodeTxInputs(body[txBodyCollateralKey]); err != nil {
+		return fmt.Errorf("collateral inputs: %w", err)
+	}
+
+	if len(tx.CollateralInputs) == 0 {
+		tx.CollateralInputs = nil
+	}
+
+	if data := body[txBodyCollateralReturnKey]; len(data) > 0 {
+		collateralReturn, err := decodeTxOutput(data)
+		if err != nil {
+			return fmt.Errorf("collateral return: %w", err)
+		}
+
+		tx.CollateralReturn = &collateralReturn
+	}
+
+	if err := unmarshalOptionalCbor(body[txBodyTotalCollateralKey], &tx.TotalCollateral); err != nil {
+		return fmt.Errorf("total collateral: %w", err)
+	}
+
+	if tx.RequiredSigners, err = decodeTxRequiredSigners(body[txBodyRequiredSignersKey]); err != nil {
+		return fmt.Errorf("required signers: %w", err)
+	}
+
+	if tx.ReferenceInputs, err = decodeTxInputs(body[txBodyReferenceInputsKey]); err != nil {
+		return fmt.Errorf("reference inputs: %w", err)
+	}
+
+	if len(tx.ReferenceInputs) == 0 {
+		tx.ReferenceInputs = nil
+	}
+
+	if tx.VotingProcedures, err = decodeTxVotingProcedures(body[txBodyVotingProceduresKey]); err != nil {
+		return fmt.Errorf("voting procedures: %w", err)
+	}
+
+	if tx.Proposals, err = decodeTxProposals(body[txBodyProposalProceduresKey]); err != nil {
+		return fmt.Errorf("proposals: %w", err)
+	}
+
+	if len(tx.Proposals) == 0 {
+		tx.Proposals = nil
+	}
+
+	return nil
+}
+
+func (tx *Transaction) decodeWitnessSet(data []byte) error {
+	var witnessSet map[uint64]cbor.RawMessage
+
+	if err := cbor.Unmarshal(data, &witnessSet); err != nil {
+		return err
+	}
+
+	vkeyWitnesses, _, err := decodeCborSet(witnessSet[txWitnessVKeyWitnesses])
+	if err != nil {
+		return fmt.Errorf("vkey witnesses: %w", err)
+	}
+
+	for _, witness := range vkeyWitnesses {
+		signature, vkey, err := TxWitnessRaw(witness).GetSignatureAndVKey()
+		if err != nil {
+			return fmt.Errorf("vkey witnesses: %w", err)
+		}
+
+		tx.VKeyWitnesses = append(tx.VKeyWitnesses, TxVKeyWitness{VKey: vkey, Signature: signature})
+	}
+
+	nativeScripts, _, err := decodeCborSet(witnessSet[txWitnessNativeScripts])
+	if err != nil {
+		return fmt.Errorf("native scripts: %w", err)
+	}
+
+	for _, script := range nativeScripts {
+		ps, err := NewPolicyScriptFromCBOR(script)
+		if err != nil {
+			return fmt.Errorf("native scripts: %w", err)
+		}
+
+		tx.NativeScripts = append(tx.NativeScripts, *ps)
+	}
+
+	for _, version := range []PlutusScriptVersion{PlutusV1, PlutusV2, PlutusV3} {
+		key, _ := version.getWitnessSetKey()
+
+		scripts, _, err := decodeCborSet(witnessSet[key])
+		if err != nil {
+			return fmt.Errorf("%s scripts: %w", version, err)
+		}
+
+		for _, script := range scripts {
+			var scriptBytes []byte
+
+			if err := cbor.Unmarshal(script, &scriptBytes); err != nil {
+				return fmt.Errorf("%s scripts: %w", version, err)
+			}
+
+			tx.PlutusScripts = append(tx.PlutusScripts, NewPlutusScript(version, scriptBytes))
+		}
+	}
+
+	datums, _, err := decodeCborSet(witnessSet[txWitnessPlutusData])
+	if err != nil {
+		return fmt.Errorf("datums: %w", err)
+	}
+
+	for _, datum := range datums {
+		tx.Datums = append(tx.Datums, datum)
+	}
+
+	if tx.Redeemers, err = decodeTxRedeemers(witnessSet[txWitnessRedeemers]); err != nil {
+		return fmt.Errorf("redeemers: %w", err)
+	}
+
+	return nil
+}
+
+// decodeTxRedeemers decodes redeemers in map (conway) or legacy array format
+func decodeTxRedeemers(data []byte) ([]TxRedeemer, error) {
+	type exUnits struct {
+		_      struct{} `cbor:",toarray"`
+		Memory uint64
+		Steps  uint64
+	}
+
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	if data[0]&0xe0 != cborMajorTypeMap {
+		var items []struct {
+			_       struct{} `cbor:",toarray"`
+			Tag     TxRedeemerTag
+			Index   uint32
+			Data    cbor.RawMessage
+			ExUnits exUnits
+		}
+
+		if err := cbor.Unmarshal(data, &items); err != nil {
+			return nil, err
+		}
+
+		result := make([]TxRedeemer, len(items))
+
+		for i, x := range items {
+			result[i] = TxRedeemer{
+				Tag: x.Tag, Index: x.Index, Data: x.Data, ExUnits: NewTxExUnits(x.ExUnits.Memory, x.ExUnits.Steps),
+			}
+		}
+
+		return result, nil
+	}
+
+	items, err := decodeCborMapEntries(data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]TxRedeemer, 0, len(items))
+
+	for _, item := range items {
+		var (
+			redeemerKey struct {
+				_     struct{} `cbor:",toarray"`
+				Tag   TxRedeemerTag
+				Index uint32
+			}
+			redeemerValue struct {
+				_       struct{} `cbor:",toarray"`
+				Data    cbor.RawMessage
+				ExUnits exUnits
+			}
+		)
+
+		if err := cbor.Unmarshal(item[0], &redeemerKey); err != nil {
+			return nil, err
+		}
+
+		if err := cbor.Unmarshal(item[1], &redeemerValue); err != nil {
+			return nil, err
+		}
+
+		result = append(result, TxRedeemer{
+			Tag:     redeemerKey.Tag,
+			Index:   redeemerKey.Index,
+			Data:    redeemerValue.Data,
+			ExUnits: NewTxExUnits(redeemerValue.ExUnits.Memory, redeemerValue.ExUnits.Steps),
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Tag != result[j].Tag {
+			return result[i].Tag < result[j].Tag
+		}
+
+		return result[i].Index < result[j].Index
+	})
+
+	return result, nil
+}
+
+// decodeAuxiliaryData decodes metadata from shelley, shelley-ma or alonzo auxiliary data format
+func (tx *Transaction) decodeAuxiliaryData(data []byte) error {
+	var auxData interface{}
+
+	if err := cbor.Unmarshal(data, &auxData); err != nil {
+		return err
+	}
+
+	var metadata interface{}
+
+	switch aux := auxData.(type) {
+	case nil:
+		return nil
+	case map[interface{}]interface{}:
+		metadata = aux
+	case []interface{}:
+		if len(aux) == 0 {
+			return fmt.Errorf("%w: empty auxiliary data", ErrInvalidMetadata)
+		}
+
+		metadata = aux[0]
+	case cbor.Tag:
+		content, ok := aux.Content.(map[interface{}]interface{})
+		if aux.Number != cborAuxiliaryDataAlonzoTag || !ok {
+			return fmt.Errorf("%w: unsupported auxiliary data", ErrInvalidMetadata)
+		}
+
+		metadata = content[uint64(0)]
+	default:
+		return fmt.Errorf("%w: unsupported auxiliary data", ErrInvalidMetadata)
+	}
+
+	if metadata == nil {
+		return nil
+	}
+
+	labels, ok := metadata.(map[interface{}]interface{})
+	if !ok {
+		return fmt.Errorf("%w: metadata is not a map", ErrInvalidMetadata)
+	}
+
+	tx.Metadata = make(map[uint64]interface{}, len(labels))
+
+	for key, value := range labels {
+		label, ok := key.(uint64)
+		if !ok {
+			return fmt.Errorf("%w: label %v is not a number", ErrInvalidMetadata, key)
+		}
+
+		tx.Metadata[label] = getMetadataJSONValue(value)
+	}
+
+	return nil
+}
+
+func decodeTxInputs(data []byte) ([]TxInput, error) {
+	inputs, _, err := decodeCborSet(data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]TxInput, len(inputs))
+
+	for i, input := range inputs {
+		var txInput struct {
+			_     struct{} `cbor:",toarray"`
+			Hash  []byte
+			Index uint32
+		}
+
+		if err := cbor.Unmarshal(input, &txInput); err != nil {
+			return nil, err
+		}
+
+		result[i] = NewTxInput(hex.EncodeToString(txInput.Hash), txInput.Index)
+	}
+
+	return result, nil
+}
+
+// decodeTxOutput decodes legacy (array) or post alonzo (map) transaction output
+func decodeTxOutput(data []byte) (TxOutput, error) {
+	var addrRaw, valueRaw, datumRaw, datumHashRaw, scriptRefRaw cbor.RawMessage
+
+	if len(data) > 0 && data[0]&0xe0 == cborMajorTypeMap {
+		var output map[uint64]cbor.RawMessage
+
+		if err := cbor.Unmarshal(data, &output); err != nil {
+			return TxOutput{}, err
+		}
+
+		addrRaw, valueRaw = output[txOutputAddressKey], output[txOutputValueKey]
+		datumRaw, scriptRefRaw = output[txOutputDatumKey], output[txOutputScriptRefKey]
+	} else {
+		var output []cbor.RawMessage
+
+		if err := cbor.Unmarshal(data, &output); err != nil {
+			return TxOutput{}, err
+		} else if len(output) < 2 {
+			return TxOutput{}, fmt.Errorf("expected at least 2 elements, got %d", len(output))
+		}
+
+		addrRaw, valueRaw = output[0], output[1]
+
+		// alonzo legacy output: [address, value, datum hash]
+		if len(output) > 2 {
+			datumHashRaw = output[2]
+		}
+	}
+
+	var addrBytes []byte
+
+	if err := cbor.Unmarshal(addrRaw, &addrBytes); err != nil {
+		return TxOutput{}, err
+	}
+
+	addr, err := NewCardanoAddress(addrBytes)
+	if err != nil {
+		return TxOutput{}, err
+	}
+
+	amount, tokens, err := decodeTxValue(valueRaw)
+	if err != nil {
+		return TxOutput{}, err
+	}
+
+	result := NewTxOutput(addr.String(), amount, tokens...)
+
+	if len(datumRaw) > 0 {
+		var datumOption struct {
+			_     struct{} `cbor:",toarray"`
+			Type  uint64
+			Value cbor.RawMessage
+		}
+
+		if err := cbor.Unmarshal(datumRaw, &datumOption); err != nil {
+			return TxOutput{}, err
+		}
+
+		switch datumOption.Type {
+		case txOutputDatumHash:
+			datumHashRaw = datumOption.Value
+		case txOutputDatumInline:
+			result.Datum, err = decodeCborEncodedData(datumOption.Value)
+			if err != nil {
+				return TxOutput{}, err
+			}
+		default:
+			return TxOutput{}, fmt.Errorf("unknown datum option: %d", datumOption.Type)
+		}
+	}
+
+	if len(datumHashRaw) > 0 {
+		var datumHash []byte
+
+		if err := cbor.Unmarshal(datumHashRaw, &datumHash); err != nil {
+			return TxOutput{}, err
+		}
+
+		result.DatumHash = hex.EncodeToString(datumHash)
+	}
+
+	if len(scriptRefRaw) > 0 {
+		scriptBytes, err := decodeCborEncodedData(scriptRefRaw)
+		if err != nil {
+			return TxOutput{}, err
+		}
+
+		var script TxReferenceScript
+
+		if err := script.UnmarshalCBOR(scriptBytes); err != nil {
+			return TxOutput{}, err
+		}
+
+		result.ReferenceScript = &script
+	}
+
+	return result, nil
+}
+
+// decodeCborEncodedData decodes #6.24(bytes) and returns inner cbor bytes
+func decodeCborEncodedData(data []byte) ([]byte, error) {
+	var tag cbor.Tag
+
+	if err := cbor.Unmarshal(data, &tag); err != nil {
+		return nil, err
+	} else if tag.Number != cborEncodedDataTag {
+		return nil, fmt.Errorf("expected cbor tag %d, got %d", cborEncodedDataTag, tag.Number)
+	}
+
+	content, ok := tag.Content.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("invalid cbor encoded data")
+	}
+
+	return content, nil
+}
+
+// decodeTxValue decodes coin or [coin, multiasset] value
+func decodeTxValue(data []byte) (uint64, []TokenAmount, error) {
+	var amount uint64
+
+	if err := cbor.Unmarshal(data, &amount); err == nil {
+		return amount, nil, nil
+	}
+
+	var value struct {
+		_          struct{} `cbor:",toarray"`
+		Amount     uint64
+		MultiAsset map[cbor.ByteString]map[cbor.ByteString]uint64
+	}
+
+	if err := cbor.Unmarshal(data, &value); err != nil {
+		return 0, nil, err
+	}
+
+	var tokens []TokenAmount
+
+	forEachMultiAsset(value.MultiAsset, func(token Token, amount uint64) {
+		tokens = append(tokens, NewTokenAmount(token, amount))
+	})
+
+	return value.Amount, tokens, nil
+}
+
+func decodeTxMint(data []byte) ([]MintTokenAmount, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var multiAsset map[cbor.ByteString]map[cbor.ByteString]int64
+
+	if err := cbor.Unmarshal(data, &multiAsset); err != nil {
+		return nil, err
+	}
+
+	var result []MintTokenAmount
+
+	forEachMultiAsset(multiAsset, func(token Token, amount int64) {
+		result = append(result, MintTokenAmount{Token: token, Amount: amount})
+	})
+
+	return result, nil
+}
+
+func decodeTxCertificates(data []byte) ([]TxCertificate, error) {
+	certificates, _, err := decodeCborSet(data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]TxCertificate, len(certificates))
+
+	for i, certificate := range certificates {
+		if result[i], err = decodeTxCertificate(certificate); err != nil {
+			return nil, fmt.Errorf("certificate %d: %w", i, err)
+		}
+	}
+
+	return result, nil
+}
+
+func decodeTxCertificate(data []byte) (TxCertificate, error) {
+	var fields []cbor.RawMessage
+
+	if err := cbor.Unmarshal(data, &fields); err != nil {
+		return TxCertificate{}, err
+	} else if len(fields) == 0 {
+		return TxCertificate{}, errors.New("empty certificate")
+	}
+
+	certificate := TxCertificate{
+		Raw: data,
+	}
+
+	if err := cbor.Unmarshal(fields[0], &certificate.Type); err != nil {
+		return TxCertificate{}, err
+	}
+
+	switch {
+	case certificate.Type >= DRepRegistrationCertificate && certificate.Type <= DRepUpdateCertificate:
+		return decodeTxDRepCertificate(certificate, fields)
+	// all the certificates up to stake_vote_reg_deleg_cert start with stake credential
+	case certificate.Type > txCertificateTypeWithStakeCredLast ||
+		certificate.Type == PoolRegistrationCertificate || certificate.Type == PoolRetirementCertificate:
+		return certificate, nil
+	}
+
+	if len(fields) < 2 {
+		return TxCertificate{}, fmt.Errorf("missing stake credential for certificate type %d", certificate.Type)
+	}
+
+	stakeCredential, err := decodeTxCredential(fields[1])
+	if err != nil {
+		return TxCertificate{}, err
+	}
+
+	certificate.StakeCredential = &stakeCredential
+
+	// pool key hash
+	switch certificate.Type {
+	case StakeDelegationCertificate, StakeVoteDelegationCertificate,
+		StakeRegDelegationCertificate, StakeVoteRegDelegationCertificate:
+		var poolKeyHash []byte
+
+		if len(fields) < 3 {
+			return TxCertificate{}, fmt.Errorf("missing pool key hash for certificate type %d", certificate.Type)
+		}
+
+		if err := cbor.Unmarshal(fields[2], &poolKeyHash); err != nil {
+			return TxCertificate{}, err
+		}
+
+		certificate.PoolKeyHash = hex.EncodeToString(poolKeyHash)
+	}
+
+	// drep follows stake credential and pool key hash
+	switch certificate.Type {
+	case VoteDelegationCertificate, StakeVoteDelegationCertificate,
+		VoteRegDelegationCertificate, StakeVoteRegDelegationCertificate:
+		drepIndx := 2
+		if certificate.PoolKeyHash != "" {
+			drepIndx++
+		}
+
+		if len(fields) <= drepIndx {
+			return TxCertificate{}, fmt.Errorf("missing drep for certificate type %d", certificate.Type)
+		}
+
+		drep, err := decodeTxDRep(fields[drepIndx])
+		if err != nil {
+			return TxCertificate{}, err
+		}
+
+		certificate.DRep = &drep
+	}
+
+	// deposit is always the last field
+	switch certificate.Type {
+	case RegistrationCertificate, UnregistrationCertificate, StakeRegDelegationCertificate,
+		VoteRegDelegationCertificate, StakeVoteRegDelegationCertificate:
+		if err := cbor.Unmarshal(fields[len(fields)-1], &certificate.Deposit); err != nil {
+			return TxCertificate{}, err
+		}
+	}
+
+	return certificate, nil
+}
+
+// decodeTxDRepCertificate decodes reg_drep_cert [16, drep_credential, coin, anchor / null],
+// unreg_drep_cert [17, drep_credential, coin] and update_drep_cert [18, drep_credential, anchor / null]
+func decodeTxDRepCertificate(certificate TxCertificate, fields []cbor.RawMessage) (TxCertificate, error) {
+	expectedLen := 3
+	if certificate.Type == DRepRegistrationCertificate {
+		expectedLen = 4
+	}
+
+	if len(fields) != expectedLen {
+		return TxCertificate{}, fmt.Errorf("invalid fields count %d for certificate type %d", len(fields), certificate.Type)
+	}
+
+	drepCredential, err := decodeTxCredential(fields[1])
+	if err != nil {
+		return TxCertificate{}, err
+	}
+
+	certificate.DRepCredential = &drepCredential
+
+	if certificate.Type != DRepUpdateCertificate {
+		if err := cbor.Unmarshal(fields[2], &certificate.Deposit); err != nil {
+			return TxCertificate{}, err
+		}
+	}
+
+	if certificate.Type != DRepUnregistrationCertificate {
+		if certificate.Anchor, err = decodeTxAnchor(fields[len(fields)-1]); err != nil {
+			return TxCertificate{}, err
+		}
+	}
+
+	return certificate, nil
+}
+
+// decodeTxDRep decodes [0, keyhash], [1, scripthash], [2] or [3]
+func decodeTxDRep(data []byte) (TxDRep, error) {
+	var fields []cbor.RawMessage
+
+	if err := cbor.Unmarshal(data, &fields); err != nil {
+		return TxDRep{}, err
+	} else if len(fields) == 0 {
+		return TxDRep{}, errors.New("empty drep")
+	}
+
+	var drep TxDRep
+
+	if err := cbor.Unmarshal(fields[0], &drep.Type); err != nil {
+		return TxDRep{}, err
+	}
+
+	switch drep.Type {
+	case DRepKeyHashType, DRepScriptHashType:
+		var hash []byte
+
+		if len(fields) != 2 {
+			return TxDRep{}, fmt.Errorf("missing hash for drep type %d", drep.Type)
+		}
+
+		if err := cbor.Unmarshal(fields[1], &hash); err != nil {
+			return TxDRep{}, err
+		}
+
+		drep.Hash = hex.EncodeToString(hash)
+	case DRepAlwaysAbstainType, DRepAlwaysNoConfidenceType:
+	default:
+		return TxDRep{}, fmt.Errorf("unknown drep type: %d", drep.Type)
+	}
+
+	return drep, nil
+}
+
+// decodeTxAnchor decodes [url, data hash] or null
+func decodeTxAnchor(data []byte) (*TxAnchor, error) {
+	var anchor *struct {
+		_        struct{} `cbor:",toarray"`
+		URL      string
+		DataHash []byte
+	}
+
+	if err := cbor.Unmarshal(data, &anchor); err != nil {
+		return nil, err
+	} else if anchor == nil {
+		return nil, nil
+	}
+
+	return &TxAnchor{
+		URL:      anchor.URL,
+		DataHash: hex.EncodeToString(anchor.DataHash),
+	}, nil
+}
+
+// decodeTxCredential decodes [0, keyhash] or [1, scripthash]
+func decodeTxCredential(data []byte) (TxCredential, error) {
+	var credential struct {
+		_    struct{} `cbor:",toarray"`
+		Type uint64
+		Hash []byte
+	}
+
+	if err := cbor.Unmarshal(data, &credential); err != nil {
+		return TxCredential{}, err
+	}
+
+	if credential.Type != txCredentialKeyHashType && credential.Type != txCredentialScriptHashType {
+		return TxCredential{}, fmt.Errorf("unknown credential type: %d", credential.Type)
+	}
+
+	return TxCredential{
+		Hash:     hex.EncodeToString(credential.Hash),
+		IsScript: credential.Type == txCredentialScriptHashType,
+	}, nil
+}
+
+func decodeTxRequiredSigners(data []byte) ([]string, error) {
+	keyHashes, _, err := decodeCborSet(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+
+	for _, keyHashBytes := range keyHashes {
+		var keyHash []byte
+
+		if err := cbor.Unmarshal(keyHashBytes, &keyHash); err != nil {
+			return nil, err
+		}
+
+		result = append(result, hex.EncodeToString(keyHash))
+	}
+
+	return result, nil
+}
+
+func decodeTxWithdrawals(data []byte) ([]TxWithdrawal, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var withdrawals map[cbor.ByteString]uint64
+
+	if err := cbor.Unmarshal(data, &withdrawals); err != nil {
+		return nil, err
+	}
+
+	rewardAccounts := make([]string, 0, len(withdrawals))
+	for rewardAccount := range withdrawals {
+		rewardAccounts = append(rewardAccounts, string(rewardAccount))
+	}
+
+	sort.Strings(rewardAccounts)
+
+	result := make([]TxWithdrawal, len(rewardAccounts))
+
+	for i, rewardAccount := range rewardAccounts {
+		addr, err := NewCardanoAddress([]byte(rewardAccount))
+		if err != nil {
+			return nil, err
+		}
+
+		result[i] = TxWithdrawal{
+			Address: addr.String(),
+			Amount:  withdrawals[cbor.ByteString(rewardAccount)],
+		}
+	}
+
+	return result, nil
+}
+
+// forEachMultiAsset iterates multi asset ordered by policy id and asset name
+func forEachMultiAsset[T any](
+	multiAsset map[cbor.ByteString]map[cbor.ByteString]T, handler func(token Token, amount T),
+) {
+	policyIDs := make([]string, 0, len(multiAsset))
+	for policyID := range multiAsset {
+		policyIDs = append(policyIDs, string(policyID))
+	}
+
+	sort.Strings(policyIDs)
+
+	for _, policyID := range policyIDs {
+		assets := multiAsset[cbor.ByteString(policyID)]
+
+		names := make([]string, 0, len(assets))
+		for name := range assets {
+			names = append(names, string(name))
+		}
+
+		sort.Slice(names, func(i, j int) bool {
+			if len(names[i]) != len(names[j]) {
+				return len(names[i]) < len(names[j])
+			}
+
+			return names[i] < names[j]
+		})
+
+		for _, name := range names {
+			handler(NewToken(hex.EncodeToString([]byte(policyID)), name), assets[cbor.ByteString(name)])
+		}
+	}
+}
+
+// getMetadataJSONValue converts decoded metadatum to value of cardano-cli no schema json format
+func getMetadataJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		return "0x" + hex.EncodeToString(v)
+	case big.Int:
+		return &v
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = getMetadataJSONValue(item)
+		}
+
+		return result
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[getMetadataJSONKey(key)] = getMetadataJSONValue(item)
+		}
+
+		return result
+	default:
+		return v
+	}
+}
+
+func getMetadataJSONKey(key interface{}) string {
+	switch k := key.(type) {
+	case string:
+		return k
+	case []byte:
+		return "0x" + hex.EncodeToString(k)
+	case uint64:
+		return strconv.FormatUint(k, 10)
+	case int64:
+		return strconv.FormatInt(k, 10)
+	case big.Int:
+		return k.String()
+	default:
+		return fmt.Sprint(k)
+	}
+}
+
+func unmarshalOptionalCbor(data []byte, value interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	return cbor.Unmarshal(data, value)
+}
@@ -0,0 +1,203 @@
+package core
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTransaction(t *testing.T) {
+	t.Parallel()
+
+	t.Run("witnessed transaction with metadata", func(t *testing.T) {
+		t.Parallel()
+
+		txRaw, err := hex.DecodeString("84a500818258201f55818892cc447cbf9fc27e04899ea98795538889555d3846a8071f4fdb75eb01018282581d70c4aab1955b120811d634e3a1b282ea090537d9e753842e8f46c280041a00200b2082583900712c77c7e146b95a569f2f7edf1dd81df2545edecb132701f17f84d4694c18049dcafc175d262c06eac9f52b86f205e38e8bfca6e6a545611a055e8308021a0002e908031a0152a319075820cb1b53bb62ee65e8ae893d04331dcc70d745298a32fcedf5ff9cc7a12d8471e3a10081825820c73cd59dbfba2e07577ad69621e964d404c7bef56f69e1691438abd37356199958408233a747b14fc78ba32fbe8501b842d3290c591a565f589dbeec1c1e8b3dfe27de19002784c6c7020871fd07a5dd70e1003b6d1449255985c823464123085a00f5d90103a100a101a5616466766563746f726266611a0010c8e06173837828616464725f74657374317170636a63613738753972746a6b6a6b6e756868616863616d71776c793478287a376d6d39337866637037396c636634726666737671663877326c73743436663376716d34766e61781c6674736d6571746375773330373264653439673473737a333437377a61746662726964676562747881a26161827828766563746f725f7465737431766772677868347333356135706476306463347a6771333363726e33781934656d6e6b326537766e656e73663474657a7133746b6d396d616d1a000f4240")
+		require.NoError(t, err)
+
+		tx, err := NewTransaction(txRaw)
+		require.NoError(t, err)
+
+		txHash, err := GetTxHash(txRaw)
+		require.NoError(t, err)
+
+		assert.Equal(t, txHash, tx.Hash)
+		assert.Equal(t, []TxInput{
+			NewTxInput("1f55818892cc447cbf9fc27e04899ea98795538889555d3846a8071f4fdb75eb", 1),
+		}, tx.Inputs)
+		assert.Equal(t, []TxOutput{
+			NewTxOutput("addr_test1wrz24vv4tvfqsywkxn36rv5zagys2d7euafcgt50gmpgqpq4ju9uv", 2_100_000),
+			NewTxOutput("addr_test1qpcjca78u9rtjkjknuhhahcamqwly4z7mm93xfcp79lcf4rffsvqf8w2lst46f3vqm4vnaftsmeqtcuw3072de49g4ssz3477z", 90_080_008),
+		}, tx.Outputs)
+		assert.Equal(t, uint64(190728), tx.Fee)
+		assert.Equal(t, uint64(22192921), tx.TimeToLive)
+		assert.True(t, tx.IsValid)
+		require.Len(t, tx.VKeyWitnesses, 1)
+		assert.Equal(t, "c73cd59dbfba2e07577ad69621e964d404c7bef56f69e1691438abd373561999", hex.EncodeToString(tx.VKeyWitnesses[0].VKey))
+		require.NoError(t, VerifyMessage(mustDecodeHex(t, tx.Hash), tx.VKeyWitnesses[0].VKey, tx.VKeyWitnesses[0].Signature))
+
+		require.Contains(t, tx.Metadata, uint64(1))
+
+		metadata, ok := tx.Metadata[1].(map[string]interface{})
+		require.True(t, ok)
+
+		assert.Equal(t, "bridge", metadata["t"])
+		assert.Equal(t, "vector", metadata["d"])
+		assert.Equal(t, uint64(1100000), metadata["fa"])
+	})
+
+	t.Run("conway transaction with mint and native script", func(t *testing.T) {
+		t.Parallel()
+
+		txRaw, err := hex.DecodeString("84a400d9010281825820e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f01018182581d60244877c1aeefc7fd5405a6e14d927d91758d45e37c20fa2ac89cb167821a000f4240a1581c29f8873beb52e126f207a2dfd50f7cff556806b5b4cba9834a7b26a8a246526f7574653318c848526f7574653334351864021a00030d4009a1581c29f8873beb52e126f207a2dfd50f7cff556806b5b4cba9834a7b26a8a246526f7574653318c848526f7574653334351864a101d9010281830301818200581cd6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21f5f6")
+		require.NoError(t, err)
+
+		tx, err := NewTransaction(txRaw)
+		require.NoError(t, err)
+
+		policyID := "29f8873beb52e126f207a2dfd50f7cff556806b5b4cba9834a7b26a8"
+
+		assert.Equal(t, []TxOutput{
+			NewTxOutput("addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u", 1_000_000,
+				NewTokenAmount(NewToken(policyID, "Route3"), 200),
+				NewTokenAmount(NewToken(policyID, "Route345"), 100)),
+		}, tx.Outputs)
+		assert.Equal(t, []MintTokenAmount{
+			{Token: NewToken(policyID, "Route3"), Amount: 200},
+			{Token: NewToken(policyID, "Route345"), Amount: 100},
+		}, tx.Mint)
+		assert.Equal(t, []PolicyScript{
+			*NewPolicyScript([]string{"d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21"}, 1),
+		}, tx.NativeScripts)
+		assert.Empty(t, tx.VKeyWitnesses)
+		assert.Nil(t, tx.Metadata)
+	})
+
+	t.Run("certificates, withdrawals, validity start and burn", func(t *testing.T) {
+		t.Parallel()
+
+		stakeKeyHash := mustDecodeHex(t, "d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21")
+		poolKeyHash := mustDecodeHex(t, "cba89c7084bf0ce4bf404346b668a7e83c8c9c250d1cafd8d8996e41")
+		policyID := mustDecodeHex(t, "29f8873beb52e126f207a2dfd50f7cff556806b5b4cba9834a7b26a8")
+
+		rewardAddr, err := CardanoAddressInfo{
+			AddressType: RewardAddress,
+			Network:     TestNetNetwork,
+			Stake:       &CardanoAddressPayload{Payload: [KeyHashSize]byte(stakeKeyHash)},
+		}.ToCardanoAddress()
+		require.NoError(t, err)
+
+		txRaw, err := cbor.Marshal([]interface{}{
+			map[uint64]interface{}{
+				txBodyInputsKey:  [][]interface{}{{mustDecodeHex(t, "e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f"), 0}},
+				txBodyOutputsKey: []interface{}{},
+				txBodyFeeKey:     170_000,
+				txBodyCertificatesKey: []interface{}{
+					[]interface{}{7, []interface{}{0, stakeKeyHash}, 2_000_000},
+					[]interface{}{2, []interface{}{0, stakeKeyHash}, poolKeyHash},
+					[]interface{}{1, []interface{}{1, policyID}},
+				},
+				txBodyWithdrawalsKey:   map[cbor.ByteString]uint64{cbor.ByteString(rewardAddr.GetBytes()): 5_000},
+				txBodyValidityStartKey: 1_000,
+				txBodyMintKey: map[cbor.ByteString]map[cbor.ByteString]int64{
+					cbor.ByteString(policyID): {"Route3": -50},
+				},
+			},
+			map[uint64]interface{}{},
+			false,
+			nil,
+		})
+		require.NoError(t, err)
+
+		tx, err := NewTransaction(txRaw)
+		require.NoError(t, err)
+
+		assert.False(t, tx.IsValid)
+		assert.Equal(t, uint64(1_000), tx.ValidityStart)
+		assert.Equal(t, uint64(0), tx.TimeToLive)
+		assert.Empty(t, tx.Outputs)
+		assert.Equal(t, []MintTokenAmount{
+			{Token: NewToken(hex.EncodeToString(policyID), "Route3"), Amount: -50},
+		}, tx.Mint)
+		assert.Equal(t, []TxWithdrawal{{Address: rewardAddr.String(), Amount: 5_000}}, tx.Withdrawals)
+
+		require.Len(t, tx.Certificates, 3)
+
+		stakeCredential := &TxCredential{Hash: hex.EncodeToString(stakeKeyHash)}
+
+		assert.Equal(t, RegistrationCertificate, tx.Certificates[0].Type)
+		assert.Equal(t, stakeCredential, tx.Certificates[0].StakeCredential)
+		assert.Equal(t, uint64(2_000_000), tx.Certificates[0].Deposit)
+		assert.Equal(t, StakeDelegationCertificate, tx.Certificates[1].Type)
+		assert.Equal(t, stakeCredential, tx.Certificates[1].StakeCredential)
+		assert.Equal(t, hex.EncodeToString(poolKeyHash), tx.Certificates[1].PoolKeyHash)
+		assert.Equal(t, StakeDeregistrationCertificate, tx.Certificates[2].Type)
+		assert.Equal(t, &TxCredential{Hash: hex.EncodeToString(policyID), IsScript: true}, tx.Certificates[2].StakeCredential)
+	})
+
+	t.Run("non-canonical native script", func(t *testing.T) {
+		t.Parallel()
+
+		keyHash := "d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21"
+		// indefinite length arrays for both the all script and its sub scripts
+		scriptBytes := mustDecodeHex(t, "9f019f8200581c"+keyHash+"ffff")
+
+		txRaw, err := cbor.Marshal([]interface{}{
+			map[uint64]interface{}{
+				txBodyInputsKey:  [][]interface{}{{mustDecodeHex(t, "e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f"), 0}},
+				txBodyOutputsKey: []interface{}{},
+				txBodyFeeKey:     170_000,
+			},
+			map[uint64]interface{}{
+				txWitnessNativeScripts: []cbor.RawMessage{scriptBytes},
+			},
+			true,
+			nil,
+		})
+		require.NoError(t, err)
+
+		tx, err := NewTransaction(txRaw)
+		require.NoError(t, err)
+
+		require.Len(t, tx.NativeScripts, 1)
+		assert.Equal(t, PolicyScriptAllType, tx.NativeScripts[0].Type)
+		require.Len(t, tx.NativeScripts[0].Scripts, 1)
+		assert.Equal(t, keyHash, tx.NativeScripts[0].Scripts[0].KeyHash)
+
+		expectedPolicyID, err := getNativeScriptHash(scriptBytes)
+		require.NoError(t, err)
+
+		policyID, err := tx.NativeScripts[0].PolicyID()
+		require.NoError(t, err)
+
+		assert.Equal(t, expectedPolicyID, policyID)
+
+		description, err := DescribeTx(txRaw)
+		require.NoError(t, err)
+
+		require.Len(t, description.PolicyScripts, 1)
+		assert.Equal(t, expectedPolicyID, description.PolicyScripts[0].PolicyID)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewTransaction([]byte{0x83, 0x01, 0x02})
+		require.ErrorIs(t, err, ErrInvalidTransaction)
+
+		_, err = NewTransaction([]byte{0x01})
+		require.ErrorIs(t, err, ErrInvalidTransaction)
+	})
+}
+
+func mustDecodeHex(t *testing.T, value string) []byte {
+	t.Helper()
+
+	bytes, err := hex.DecodeString(value)
+	require.NoError(t, err)
+
+	return bytes
+}
@@ -0,0 +1,752 @@
+package core
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+var txCertificateTypeNames = map[TxCertificateType]string{
+	StakeRegistrationCertificate:      "stake_registration",
+	StakeDeregistrationCertificate:    "stake_deregistration",
+	StakeDelegationCertificate:        "stake_delegation",
+	PoolRegistrationCertificate:       "pool_registration",
+	PoolRetirementCertificate:         "pool_retirement",
+	RegistrationCertificate:           "reg_cert",
+	UnregistrationCertificate:         "unreg_cert",
+	VoteDelegationCertificate:         "vote_deleg_cert",
+	StakeVoteDelegationCertificate:    "stake_vote_deleg_cert",
+	StakeRegDelegationCertificate:     "stake_reg_deleg_cert",
+	VoteRegDelegationCertificate:      "vote_reg_deleg_cert",
+	StakeVoteRegDelegationCertificate: "stake_vote_reg_deleg_cert",
+	AuthCommitteeHotCertificate:       "auth_committee_hot_cert",
+	ResignCommitteeColdCertificate:    "resign_committee_cold_cert",
+	DRepRegistrationCertificate:       "reg_drep_cert",
+	DRepUnregistrationCertificate:     "unreg_drep_cert",
+	DRepUpdateCertificate:             "update_drep_cert",
+}
+
+var txVoterTypeNames = map[TxVoterType]string{
+	CommitteeHotKeyHashVoter:    "committee_key",
+	CommitteeHotScriptHashVoter: "committee_script",
+	DRepKeyHashVoter:            "drep_key",
+	DRepScriptHashVoter:         "drep_script",
+	StakePoolVoter:              "pool",
+}
+
+var txVoteNames = map[TxVote]string{
+	VoteNo:      "no",
+	VoteYes:     "yes",
+	VoteAbstain: "abstain",
+}
+
+var txGovActionTypeNames = map[TxGovActionType]string{
+	ParameterChangeGovAction:     "parameter_change",
+	HardForkInitiationGovAction:  "hard_fork_initiation",
+	TreasuryWithdrawalsGovAction: "treasury_withdrawals",
+	NoConfidenceGovAction:        "no_confidence",
+	UpdateCommitteeGovAction:     "update_committee",
+	NewConstitutionGovAction:     "new_constitution",
+	InfoGovAction:                "info",
+}
+
+func (t TxCertificateType) String() string {
+	if name, exists := txCertificateTypeNames[t]; exists {
+		return name
+	}
+
+	return fmt.Sprintf("certificate_%d", uint64(t))
+}
+
+func (t TxVoterType) String() string {
+	if name, exists := txVoterTypeNames[t]; exists {
+		return name
+	}
+
+	return fmt.Sprintf("voter_%d", uint64(t))
+}
+
+func (v TxVote) String() string {
+	if name, exists := txVoteNames[v]; exists {
+		return name
+	}
+
+	return fmt.Sprintf("vote_%d", uint64(v))
+}
+
+func (t TxGovActionType) String() string {
+	if name, exists := txGovActionTypeNames[t]; exists {
+		return name
+	}
+
+	return fmt.Sprintf("gov_action_%d", uint64(t))
+}
+
+type TxTokenDescription struct {
+	PolicyID string `json:"policyId"`
+	Name     string `json:"name"`
+	NameHex  string `json:"nameHex"`
+	Amount   int64  `json:"amount"`
+}
+
+func newTxTokenDescription(token Token, amount int64) TxTokenDescription {
+	return TxTokenDescription{
+		PolicyID: token.PolicyID,
+		Name:     token.Name,
+		NameHex:  hex.EncodeToString([]byte(token.Name)),
+		Amount:   amount,
+	}
+}
+
+func (t TxTokenDescription) String() string {
+	return fmt.Sprintf("%d %s.%s (%s)", t.Amount, t.PolicyID, t.NameHex, t.Name)
+}
+
+type TxValueDescription struct {
+	Addr   string               `json:"addr,omitempty"`
+	Amount int64                `json:"amount"`
+	Tokens []TxTokenDescription `json:"tokens,omitempty"`
+}
+
+func (v TxValueDescription) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("%s %d lovelace", v.Addr, v.Amount))
+
+	for _, token := range v.Tokens {
+		sb.WriteString(" + ")
+		sb.WriteString(token.String())
+	}
+
+	return sb.String()
+}
+
+type TxInputDescription struct {
+	TxInput
+	// Value is nil if utxo of the input is not known
+	Value *TxValueDescription `json:"value,omitempty"`
+}
+
+type TxPolicyScriptDescription struct {
+	PolicyID            string   `json:"policyId"`
+	Type                string   `json:"type"`
+	SignedKeyHashes     []string `json:"signed"`
+	MissingKeyHashes    []string `json:"missing"`
+	RemainingSignatures int      `json:"remainingSignatures"`
+}
+
+// TxDescription is human readable representation of transaction
+type TxDescription struct {
+	Hash              string                      `json:"hash"`
+	Fee               uint64                      `json:"fee"`
+	TimeToLive        uint64                      `json:"ttl,omitempty"`
+	ValidityStart     uint64                      `json:"validityStart,omitempty"`
+	Inputs            []TxInputDescription        `json:"inputs"`
+	Outputs           []TxValueDescription        `json:"outputs"`
+	TotalInputAmount  uint64                      `json:"totalInputAmount,omitempty"`
+	TotalOutputAmount uint64                      `json:"totalOutputAmount"`
+	Mint              []TxTokenDescription        `json:"mint,omitempty"`
+	Certificates      []TxCertificate             `json:"certificates,omitempty"`
+	Withdrawals       []TxWithdrawal              `json:"withdrawals,omitempty"`
+	VotingProcedures  []TxVotingProcedure         `json:"votingProcedures,omitempty"`
+	Proposals         []TxProposal                `json:"proposals,omitempty"`
+	ValueFlow         []TxValueDescription        `json:"valueFlow"`
+	PolicyScripts     []TxPolicyScriptDescription `json:"policyScripts,omitempty"`
+	Signers           []string                    `json:"signers,omitempty"`
+	Metadata          map[uint64]interface{}      `json:"metadata,omitempty"`
+	IsValid           bool                        `json:"isValid"`
+}
+
+type describeTxConfig struct {
+	utxos map[TxInput]TxOutput
+}
+
+type DescribeTxOption func(c *describeTxConfig)
+
+// WithDescribeTxUtxos provides utxos of addr so inputs can be included into value flow
+func WithDescribeTxUtxos(addr string, utxos []Utxo) DescribeTxOption {
+	return func(c *describeTxConfig) {
+		for _, utxo := range utxos {
+			c.utxos[NewTxInput(utxo.Hash, utxo.Index)] = NewTxOutput(addr, utxo.Amount, utxo.Tokens...)
+		}
+	}
+}
+
+// DescribeTx decodes transaction cbor into human readable description
+func DescribeTx(txRaw []byte, options ...DescribeTxOption) (*TxDescription, error) {
+	tx, err := NewTransaction(txRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	config := describeTxConfig{
+		utxos: map[TxInput]TxOutput{},
+	}
+
+	for _, option := range options {
+		option(&config)
+	}
+
+	description := &TxDescription{
+		Hash:             tx.Hash,
+		Fee:              tx.Fee,
+		TimeToLive:       tx.TimeToLive,
+		ValidityStart:    tx.ValidityStart,
+		Inputs:           make([]TxInputDescription, len(tx.Inputs)),
+		Outputs:          make([]TxValueDescription, len(tx.Outputs)),
+		Certificates:     tx.Certificates,
+		Withdrawals:      tx.Withdrawals,
+		VotingProcedures: tx.VotingProcedures,
+		Proposals:        tx.Proposals,
+		Metadata:         tx.Metadata,
+		IsValid:          tx.IsValid,
+	}
+
+	valueFlow := newTxValueFlow()
+
+	for i, input := range tx.Inputs {
+		description.Inputs[i] = TxInputDescription{TxInput: input}
+
+		if output, exists := config.utxos[input]; exists {
+			value, err := newTxValueDescription(output)
+			if err != nil {
+				return nil, err
+			}
+
+			description.Inputs[i].Value = &value
+			description.TotalInputAmount += output.Amount
+
+			valueFlow.add(output, -1)
+		}
+	}
+
+	for i, output := range tx.Outputs {
+		description.Outputs[i], err = newTxValueDescription(output)
+		if err != nil {
+			return nil, err
+		}
+
+		description.TotalOutputAmount += output.Amount
+
+		valueFlow.add(output, 1)
+	}
+
+	for _, mint := range tx.Mint {
+		description.Mint = append(description.Mint, newTxTokenDescription(mint.Token, mint.Amount))
+	}
+
+	description.ValueFlow, err = valueFlow.getDescriptions()
+	if err != nil {
+		return nil, err
+	}
+
+	signers := map[string]bool{}
+
+	for _, witness := range tx.VKeyWitnesses {
+		keyHash, err := GetKeyHash(witness.VKey)
+		if err != nil {
+			return nil, err
+		}
+
+		signers[keyHash] = true
+		description.Signers = append(description.Signers, keyHash)
+	}
+
+	sort.Strings(description.Signers)
+
+	for _, policyScript := range tx.NativeScripts {
+		scriptDescription, err := newTxPolicyScriptDescription(policyScript, signers)
+		if err != nil {
+			return nil, err
+		}
+
+		description.PolicyScripts = append(description.PolicyScripts, scriptDescription)
+	}
+
+	return description, nil
+}
+
+// ToJSON returns stable json representation of the transaction description
+func (d TxDescription) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+func (d TxDescription) String() string {
+	var sb strings.Builder
+
+	for _, section := range d.getSections() {
+		if !section.isList {
+			sb.WriteString(fmt.Sprintf("%s: %s\n", section.name, strings.Join(section.lines, "")))
+
+			continue
+		}
+
+		sb.WriteString(section.name)
+		sb.WriteString(":\n")
+
+		for _, line := range section.lines {
+			sb.WriteString("  ")
+			sb.WriteString(line)
+			sb.WriteRune('\n')
+		}
+	}
+
+	return sb.String()
+}
+
+type txDescriptionSection struct {
+	name   string
+	lines  []string
+	isList bool
+}
+
+func (d TxDescription) getSections() []txDescriptionSection {
+	sections := []txDescriptionSection{
+		{name: "hash", lines: []string{d.Hash}},
+		{name: "fee", lines: []string{fmt.Sprint(d.Fee)}},
+		{name: "ttl", lines: []string{fmt.Sprint(d.TimeToLive)}},
+		{name: "validity start", lines: []string{fmt.Sprint(d.ValidityStart)}},
+		{name: "valid", lines: []string{fmt.Sprint(d.IsValid)}},
+	}
+
+	addList := func(name string, count int, getLine func(i int) string) {
+		section := txDescriptionSection{name: name, isList: true}
+
+		for i := 0; i < count; i++ {
+			section.lines = append(section.lines, getLine(i))
+		}
+
+		sections = append(sections, section)
+	}
+
+	addList("inputs", len(d.Inputs), func(i int) string {
+		if d.Inputs[i].Value == nil {
+			return d.Inputs[i].String()
+		}
+
+		return fmt.Sprintf("%s %s", d.Inputs[i].String(), d.Inputs[i].Value.String())
+	})
+	addList("outputs", len(d.Outputs), func(i int) string {
+		return d.Outputs[i].String()
+	})
+
+	sections = append(sections, txDescriptionSection{
+		name: "total output", lines: []string{fmt.Sprintf("%d lovelace", d.TotalOutputAmount)},
+	})
+
+	addList("mint", len(d.Mint), func(i int) string {
+		return d.Mint[i].String()
+	})
+	addList("certificates", len(d.Certificates), func(i int) string {
+		return getTxCertificateDescription(d.Certificates[i])
+	})
+	addList("withdrawals", len(d.Withdrawals), func(i int) string {
+		return fmt.Sprintf("%s %d lovelace", d.Withdrawals[i].Address, d.Withdrawals[i].Amount)
+	})
+	addList("votes", len(d.VotingProcedures), func(i int) string {
+		vote := d.VotingProcedures[i]
+
+		return fmt.Sprintf("%s:%s %s %s", vote.Voter.Type, vote.Voter.Hash, vote.GovActionID, vote.Vote)
+	})
+	addList("proposals", len(d.Proposals), func(i int) string {
+		proposal := d.Proposals[i]
+
+		return fmt.Sprintf("%s deposit:%d return:%s anchor:%s", proposal.Action.Type, proposal.Deposit,
+			proposal.RewardAddress, proposal.Anchor.URL)
+	})
+	addList("value flow", len(d.ValueFlow), func(i int) string {
+		return d.ValueFlow[i].String()
+	})
+	addList("policy scripts", len(d.PolicyScripts), func(i int) string {
+		ps := d.PolicyScripts[i]
+
+		return fmt.Sprintf("%s %s signed=[%s] missing=[%s] remaining=%d", ps.PolicyID, ps.Type,
+			strings.Join(ps.SignedKeyHashes, ","), strings.Join(ps.MissingKeyHashes, ","), ps.RemainingSignatures)
+	})
+	addList("signers", len(d.Signers), func(i int) string {
+		return d.Signers[i]
+	})
+
+	labels := make([]uint64, 0, len(d.Metadata))
+	for label := range d.Metadata {
+		labels = append(labels, label)
+	}
+
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i] < labels[j]
+	})
+
+	addList("metadata", len(labels), func(i int) string {
+		// json encoder sorts map keys so the output is stable
+		value, err := json.Marshal(d.Metadata[labels[i]])
+		if err != nil {
+			value = []byte(fmt.Sprint(d.Metadata[labels[i]]))
+		}
+
+		return fmt.Sprintf("%d: %s", labels[i], value)
+	})
+
+	return sections
+}
+
+// TxDiffEntry contains lines of one transaction description section which were removed or added
+type TxDiffEntry struct {
+	Section string   `json:"section"`
+	Removed []string `json:"removed,omitempty"`
+	Added   []string `json:"added,omitempty"`
+}
+
+type TxDiff []TxDiffEntry
+
+// DiffTx compares descriptions of two transactions (for example original and rebuilt batch)
+func DiffTx(oldTxRaw, newTxRaw []byte, options ...DescribeTxOption) (TxDiff, error) {
+	oldDescription, err := DescribeTx(oldTxRaw, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	newDescription, err := DescribeTx(newTxRaw, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return oldDescription.Diff(*newDescription), nil
+}
+
+// Diff returns sections which are different in the other transaction description
+func (d TxDescription) Diff(other TxDescription) TxDiff {
+	var (
+		diff          TxDiff
+		otherSections = other.getSections()
+	)
+
+	for i, section := range d.getSections() {
+		removed, added := getLinesDiff(section.lines, otherSections[i].lines)
+		if len(removed) > 0 || len(added) > 0 {
+			diff = append(diff, TxDiffEntry{
+				Section: section.name,
+				Removed: removed,
+				Added:   added,
+			})
+		}
+	}
+
+	return diff
+}
+
+func (d TxDiff) String() string {
+	var sb strings.Builder
+
+	for _, entry := range d {
+		sb.WriteString(entry.Section)
+		sb.WriteString(":\n")
+
+		for _, line := range entry.Removed {
+			sb.WriteString("- ")
+			sb.WriteString(line)
+			sb.WriteRune('\n')
+		}
+
+		for _, line := range entry.Added {
+			sb.WriteString("+ ")
+			sb.WriteString(line)
+			sb.WriteRune('\n')
+		}
+	}
+
+	return sb.String()
+}
+
+// getLinesDiff returns lines which exist only in old and lines which exist only in new (order is preserved)
+func getLinesDiff(oldLines, newLines []string) (removed []string, added []string) {
+	return getMissingLines(oldLines, newLines), getMissingLines(newLines, oldLines)
+}
+
+// getMissingLines returns lines which are not matched by other lines (duplicates are matched one by one)
+func getMissingLines(lines, otherLines []string) (result []string) {
+	counts := make(map[string]int, len(otherLines))
+
+	for _, line := range otherLines {
+		counts[line]++
+	}
+
+	for _, line := range lines {
+		if counts[line] > 0 {
+			counts[line]--
+		} else {
+			result = append(result, line)
+		}
+	}
+
+	return result
+}
+
+func getTxCertificateDescription(certificate TxCertificate) string {
+	var sb strings.Builder
+
+	sb.WriteString(certificate.Type.String())
+
+	if certificate.StakeCredential != nil {
+		if certificate.StakeCredential.IsScript {
+			sb.WriteString(" script:")
+		} else {
+			sb.WriteString(" key:")
+		}
+
+		sb.WriteString(certificate.StakeCredential.Hash)
+	}
+
+	if certificate.DRepCredential != nil {
+		if certificate.DRepCredential.IsScript {
+			sb.WriteString(" drep_script:")
+		} else {
+			sb.WriteString(" drep_key:")
+		}
+
+		sb.WriteString(certificate.DRepCredential.Hash)
+	}
+
+	if certificate.PoolKeyHash != "" {
+		sb.WriteString(" pool:")
+		sb.WriteString(certificate.PoolKeyHash)
+	}
+
+	if certificate.DRep != nil {
+		switch certificate.DRep.Type {
+		case DRepAlwaysAbstainType:
+			sb.WriteString(" drep:always_abstain")
+		case DRepAlwaysNoConfidenceType:
+			sb.WriteString(" drep:always_no_confidence")
+		case DRepScriptHashType:
+			sb.WriteString(" drep:script:")
+			sb.WriteString(certificate.DRep.Hash)
+		default:
+			sb.WriteString(" drep:key:")
+			sb.WriteString(certificate.DRep.Hash)
+		}
+	}
+
+	if certificate.Deposit > 0 {
+		sb.WriteString(fmt.Sprintf(" deposit:%d", certificate.Deposit))
+	}
+
+	if certificate.Anchor != nil {
+		sb.WriteString(" anchor:")
+		sb.WriteString(certificate.Anchor.URL)
+	}
+
+	return sb.String()
+}
+
+func newTxValueDescription(output TxOutput) (TxValueDescription, error) {
+	if output.Amount > math.MaxInt64 {
+		return TxValueDescription{}, fmt.Errorf("%w: %s has %d lovelace", ErrAmountOutOfRange, output.Addr, output.Amount)
+	}
+
+	value := TxValueDescription{
+		Addr:   output.Addr,
+		Amount: int64(output.Amount),
+	}
+
+	for _, token := range output.Tokens {
+		if token.Amount > math.MaxInt64 {
+			return TxValueDescription{}, fmt.Errorf("%w: %s has %d %s",
+				ErrAmountOutOfRange, output.Addr, token.Amount, token.Token)
+		}
+
+		value.Tokens = append(value.Tokens, newTxTokenDescription(token.Token, int64(token.Amount)))
+	}
+
+	return value, nil
+}
+
+func newTxPolicyScriptDescription(
+	policyScript PolicyScript, signers map[string]bool,
+) (TxPolicyScriptDescription, error) {
+	policyID, err := policyScript.PolicyID()
+	if err != nil {
+		return TxPolicyScriptDescription{}, err
+	}
+
+	description := TxPolicyScriptDescription{
+		PolicyID:            policyID,
+		Type:                policyScript.Type,
+		SignedKeyHashes:     []string{},
+		MissingKeyHashes:    []string{},
+		RemainingSignatures: getRemainingSignaturesCount(policyScript, signers),
+	}
+
+	for _, keyHash := range getPolicyScriptKeyHashes(policyScript) {
+		if signers[keyHash] {
+			description.SignedKeyHashes = append(description.SignedKeyHashes, keyHash)
+		} else {
+			description.MissingKeyHashes = append(description.MissingKeyHashes, keyHash)
+		}
+	}
+
+	return description, nil
+}
+
+// getPolicyScriptKeyHashes returns distinct sorted key hashes of all the sig scripts
+func getPolicyScriptKeyHashes(policyScript PolicyScript) []string {
+	var (
+		keyHashes []string
+		collect   func(ps PolicyScript)
+		seen      = map[string]bool{}
+	)
+
+	collect = func(ps PolicyScript) {
+		if ps.Type == PolicyScriptSigType && !seen[ps.KeyHash] {
+			seen[ps.KeyHash] = true
+			keyHashes = append(keyHashes, ps.KeyHash)
+		}
+
+		for _, script := range ps.Scripts {
+			collect(script)
+		}
+	}
+
+	collect(policyScript)
+	sort.Strings(keyHashes)
+
+	return keyHashes
+}
+
+// getRemainingSignaturesCount returns minimal number of additional signatures needed to satisfy policy script
+// time locks are considered satisfied
+func getRemainingSignaturesCount(ps PolicyScript, signers map[string]bool) int {
+	getChildren := func() []int {
+		counts := make([]int, len(ps.Scripts))
+		for i, script := range ps.Scripts {
+			counts[i] = getRemainingSignaturesCount(script, signers)
+		}
+
+		sort.Ints(counts)
+
+		return counts
+	}
+
+	switch ps.Type {
+	case PolicyScriptSigType:
+		if signers[ps.KeyHash] {
+			return 0
+		}
+
+		return 1
+	case PolicyScriptAllType:
+		cnt := 0
+		for _, x := range getChildren() {
+			cnt += x
+		}
+
+		return cnt
+	case PolicyScriptAnyType:
+		if children := getChildren(); len(children) > 0 {
+			return children[0]
+		}
+
+		return 0
+	case PolicyScriptAtLeastType:
+		cnt := 0
+		for i, x := range getChildren() {
+			if i >= ps.Required {
+				break
+			}
+
+			cnt += x
+		}
+
+		return cnt
+	default:
+		return 0
+	}
+}
+
+type txValueFlow struct {
+	addresses []string
+	amounts   map[string]*big.Int
+	tokens    map[string]map[Token]*big.Int
+}
+
+func newTxValueFlow() *txValueFlow {
+	return &txValueFlow{
+		amounts: map[string]*big.Int{},
+		tokens:  map[string]map[Token]*big.Int{},
+	}
+}
+
+// add adds (sign > 0) or subtracts (sign < 0) output value; sums are kept in big.Int so they can not wrap
+func (vf *txValueFlow) add(output TxOutput, sign int) {
+	if _, exists := vf.amounts[output.Addr]; !exists {
+		vf.addresses = append(vf.addresses, output.Addr)
+		vf.amounts[output.Addr] = new(big.Int)
+		vf.tokens[output.Addr] = map[Token]*big.Int{}
+	}
+
+	addSigned := func(sum *big.Int, amount uint64) {
+		value := new(big.Int).SetUint64(amount)
+		if sign < 0 {
+			value.Neg(value)
+		}
+
+		sum.Add(sum, value)
+	}
+
+	addSigned(vf.amounts[output.Addr], output.Amount)
+
+	for _, token := range output.Tokens {
+		if _, exists := vf.tokens[output.Addr][token.Token]; !exists {
+			vf.tokens[output.Addr][token.Token] = new(big.Int)
+		}
+
+		addSigned(vf.tokens[output.Addr][token.Token], token.Amount)
+	}
+}
+
+func (vf *txValueFlow) getDescriptions() ([]TxValueDescription, error) {
+	result := []TxValueDescription{}
+
+	sort.Strings(vf.addresses)
+
+	for _, addr := range vf.addresses {
+		if !vf.amounts[addr].IsInt64() {
+			return nil, fmt.Errorf("%w: %s lovelace flow is %s", ErrAmountOutOfRange, addr, vf.amounts[addr])
+		}
+
+		value := TxValueDescription{
+			Addr:   addr,
+			Amount: vf.amounts[addr].Int64(),
+		}
+
+		tokens := make([]Token, 0, len(vf.tokens[addr]))
+
+		for token, amount := range vf.tokens[addr] {
+			if amount.Sign() != 0 {
+				tokens = append(tokens, token)
+			}
+		}
+
+		sort.Slice(tokens, func(i, j int) bool {
+			return tokens[i].String() < tokens[j].String()
+		})
+
+		for _, token := range tokens {
+			amount := vf.tokens[addr][token]
+			if !amount.IsInt64() {
+				return nil, fmt.Errorf("%w: %s %s flow is %s", ErrAmountOutOfRange, addr, token, amount)
+			}
+
+			value.Tokens = append(value.Tokens, newTxTokenDescription(token, amount.Int64()))
+		}
+
+		if value.Amount != 0 || len(value.Tokens) > 0 {
+			result = append(result, value)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,181 @@
+package core
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribeTx(t *testing.T) {
+	t.Parallel()
+
+	const (
+		receiverAddr = "addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u"
+		inputHash    = "e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f"
+		policyID     = "29f8873beb52e126f207a2dfd50f7cff556806b5b4cba9834a7b26a8"
+	)
+
+	var (
+		wallets   = [5]*Wallet{}
+		keyHashes = make([]string, len(wallets))
+		err       error
+	)
+
+	for i := range wallets {
+		wallets[i], err = GenerateWallet(false)
+		require.NoError(t, err)
+
+		keyHashes[i], err = GetKeyHash(wallets[i].VerificationKey)
+		require.NoError(t, err)
+	}
+
+	policyScript := NewPolicyScript(keyHashes, 4)
+
+	multisigAddr, err := NewPolicyScriptAddressFromScript(TestNetNetwork, policyScript)
+	require.NoError(t, err)
+
+	token := NewToken(policyID, "Route3")
+	utxos := []Utxo{
+		{Hash: inputHash, Index: 1, Amount: 10_000_000, Tokens: []TokenAmount{NewTokenAmount(token, 500)}},
+	}
+
+	buildTx := func(sendAmount uint64) []byte {
+		t.Helper()
+
+		builder, err := NewTxBuilder(ResolveCardanoCliBinary(TestNetNetwork))
+		require.NoError(t, err)
+
+		defer builder.Dispose()
+
+		builder.SetProtocolParameters(protocolParameters).SetTimeToLive(1000).SetFee(200_000)
+		builder.AddInputsWithScript(policyScript, NewTxInput(inputHash, 1))
+		builder.AddOutputs(
+			NewTxOutput(receiverAddr, sendAmount, NewTokenAmount(token, 100)),
+			NewTxOutput(multisigAddr.String(), 10_000_000-200_000-sendAmount, NewTokenAmount(token, 400)),
+		)
+
+		txRaw, _, err := builder.Build()
+		require.NoError(t, err)
+
+		witnesses := make([][]byte, 2)
+
+		for i := range witnesses {
+			witnesses[i], err = builder.CreateTxWitness(txRaw, wallets[i])
+			require.NoError(t, err)
+		}
+
+		txSigned, err := builder.AssembleTxWitnesses(txRaw, witnesses)
+		require.NoError(t, err)
+
+		return txSigned
+	}
+
+	txRaw := buildTx(3_000_000)
+
+	description, err := DescribeTx(txRaw, WithDescribeTxUtxos(multisigAddr.String(), utxos))
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(200_000), description.Fee)
+	assert.Equal(t, uint64(1000), description.TimeToLive)
+	assert.Equal(t, uint64(10_000_000), description.TotalInputAmount)
+	assert.Equal(t, uint64(9_800_000), description.TotalOutputAmount)
+	require.Len(t, description.Inputs, 1)
+	require.NotNil(t, description.Inputs[0].Value)
+	assert.Equal(t, multisigAddr.String(), description.Inputs[0].Value.Addr)
+	assert.Equal(t, []TxTokenDescription{
+		{PolicyID: policyID, Name: "Route3", NameHex: "526f75746533", Amount: 100},
+	}, description.Outputs[0].Tokens)
+
+	assert.ElementsMatch(t, []TxValueDescription{
+		{
+			Addr:   multisigAddr.String(),
+			Amount: -3_200_000,
+			Tokens: []TxTokenDescription{{PolicyID: policyID, Name: "Route3", NameHex: "526f75746533", Amount: -100}},
+		},
+		{
+			Addr:   receiverAddr,
+			Amount: 3_000_000,
+			Tokens: []TxTokenDescription{{PolicyID: policyID, Name: "Route3", NameHex: "526f75746533", Amount: 100}},
+		},
+	}, description.ValueFlow)
+
+	require.Len(t, description.PolicyScripts, 1)
+
+	psDescription := description.PolicyScripts[0]
+	policyScriptID, err := policyScript.PolicyID()
+	require.NoError(t, err)
+
+	assert.Equal(t, policyScriptID, psDescription.PolicyID)
+	assert.ElementsMatch(t, keyHashes[:2], psDescription.SignedKeyHashes)
+	assert.ElementsMatch(t, keyHashes[2:], psDescription.MissingKeyHashes)
+	assert.Equal(t, 2, psDescription.RemainingSignatures)
+	assert.ElementsMatch(t, keyHashes[:2], description.Signers)
+
+	jsonBytes, err := description.ToJSON()
+	require.NoError(t, err)
+
+	descriptionAgain, err := DescribeTx(txRaw, WithDescribeTxUtxos(multisigAddr.String(), utxos))
+	require.NoError(t, err)
+
+	jsonBytesAgain, err := descriptionAgain.ToJSON()
+	require.NoError(t, err)
+
+	assert.Equal(t, string(jsonBytes), string(jsonBytesAgain))
+	assert.Equal(t, description.String(), descriptionAgain.String())
+	assert.Contains(t, description.String(), "  "+receiverAddr+" 3000000 lovelace + 100 "+policyID+".526f75746533 (Route3)\n")
+
+	t.Run("diff", func(t *testing.T) {
+		t.Parallel()
+
+		diff, err := DiffTx(txRaw, txRaw)
+		require.NoError(t, err)
+		assert.Empty(t, diff)
+
+		diff, err = DiffTx(txRaw, buildTx(4_000_000), WithDescribeTxUtxos(multisigAddr.String(), utxos))
+		require.NoError(t, err)
+
+		sections := make([]string, len(diff))
+		for i, entry := range diff {
+			sections[i] = entry.Section
+		}
+
+		assert.Equal(t, []string{"hash", "outputs", "value flow"}, sections)
+		assert.Equal(t, []string{
+			receiverAddr + " 3000000 lovelace + 100 " + policyID + ".526f75746533 (Route3)",
+			multisigAddr.String() + " 6800000 lovelace + 400 " + policyID + ".526f75746533 (Route3)",
+		}, diff[1].Removed)
+		assert.Equal(t, []string{
+			receiverAddr + " 4000000 lovelace + 100 " + policyID + ".526f75746533 (Route3)",
+			multisigAddr.String() + " 5800000 lovelace + 400 " + policyID + ".526f75746533 (Route3)",
+		}, diff[1].Added)
+		assert.Contains(t, diff.String(), "- "+receiverAddr+" 3000000 lovelace")
+		assert.Contains(t, diff.String(), "+ "+receiverAddr+" 4000000 lovelace")
+	})
+	t.Run("amounts out of int64 range", func(t *testing.T) {
+		t.Parallel()
+
+		builder := newTestTxBuilder(t, protocolParameters)
+		builder.AddOutputs(NewTxOutput(receiverAddr, 1_000_000, NewTokenAmount(token, math.MaxInt64+1)))
+
+		txRaw, _, err := builder.Build()
+		require.NoError(t, err)
+
+		_, err = DescribeTx(txRaw)
+		require.ErrorIs(t, err, ErrAmountOutOfRange)
+
+		// every output fits into int64 but their sum for the address does not
+		builder = newTestTxBuilder(t, protocolParameters)
+		builder.AddOutputs(
+			NewTxOutput(receiverAddr, 1_000_000, NewTokenAmount(token, math.MaxInt64)),
+			NewTxOutput(receiverAddr, 1_000_000, NewTokenAmount(token, 1)),
+		)
+
+		txRaw, _, err = builder.Build()
+		require.NoError(t, err)
+
+		_, err = DescribeTx(txRaw)
+		require.ErrorIs(t, err, ErrAmountOutOfRange)
+	})
+}
@@ -13,8 +13,10 @@ var (
 
 const defaultTimeToLiveInc = 200
 
+// SetProtocolParametersAndTTL sets protocol parameters and time to live relative to the current tip
+// if validityStartDec is passed, validity start is set to the tip slot decreased by it
 func (b *TxBuilder) SetProtocolParametersAndTTL(
-	ctx context.Context, retriever ITxDataRetriever, timeToLiveInc uint64,
+	ctx context.Context, retriever ITxDataRetriever, timeToLiveInc uint64, validityStartDec ...uint64,
 ) error {
 	if timeToLiveInc == 0 {
 		timeToLiveInc = defaultTimeToLiveInc
@@ -32,6 +34,10 @@ func (b *TxBuilder) SetProtocolParametersAndTTL(
 
 	b.SetProtocolParameters(protocolParams).SetTimeToLive(tip.Slot + timeToLiveInc)
 
+	if len(validityStartDec) > 0 {
+		b.SetValidityStart(tip.Slot - min(tip.Slot, validityStartDec[0]))
+	}
+
 	return nil
 }
 
@@ -1,9 +1,11 @@
 package core
 
 import (
+	"context"
 	"sort"
 	"testing"
 
+	"github.com/igorcrevar/go-cardano-tx/core/clitest"
 	"github.com/stretchr/testify/require"
 )
 
@@ -326,3 +328,87 @@ func TestGetUTXOsForAmount(t *testing.T) {
 		require.Equal(t, uint64(11_020_039), txOutputs.Sum[tokenAmount1.TokenName()])
 	})
 }
+
+func TestSetProtocolParametersAndTTL(t *testing.T) {
+	t.Parallel()
+
+	fakeCli := clitest.NewFakeCardanoCli().
+		Handle(clitest.Response{Stdout: clitest.QueryTipOutput(10, 1, 5_000, "Conway")}, "query", "tip").
+		Handle(clitest.Response{Stdout: string(protocolParameters)}, "query", "protocol-parameters")
+
+	provider, err := NewTxProviderCli(2, "node.socket", "cardano-cli-fake", WithCommandRunner(fakeCli))
+	require.NoError(t, err)
+
+	defer provider.Dispose()
+
+	policyScript := &PolicyScript{
+		Type: PolicyScriptAllType,
+		Scripts: []PolicyScript{
+			{Type: PolicyScriptAfterType, Slot: 4_950},
+			{Type: PolicyScriptSigType, KeyHash: "d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21"},
+		},
+	}
+
+	build := func(t *testing.T, validityStartDec ...uint64) (*Transaction, uint64, error) {
+		t.Helper()
+
+		builder, err := NewTxBuilder(ResolveCardanoCliBinary(TestNetNetwork))
+		require.NoError(t, err)
+
+		defer builder.Dispose()
+
+		require.NoError(t, builder.SetProtocolParametersAndTTL(context.Background(), provider, 100, validityStartDec...))
+
+		builder.AddInputsWithScript(policyScript,
+			NewTxInput("e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f", 1))
+		builder.AddOutputs(NewTxOutput("addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u", 2_000_000))
+
+		fee, err := builder.CalculateFee(0)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		txRaw, _, err := builder.SetFee(fee).Build()
+		if err != nil {
+			return nil, 0, err
+		}
+
+		tx, err := NewTransaction(txRaw)
+		require.NoError(t, err)
+
+		return tx, fee, nil
+	}
+
+	t.Run("without validity start", func(t *testing.T) {
+		t.Parallel()
+
+		tx, fee, err := build(t)
+		require.NoError(t, err)
+
+		// validity start is set from the time lock
+		require.Equal(t, uint64(4_950), tx.ValidityStart)
+		require.Equal(t, fee, tx.Fee)
+	})
+
+	t.Run("validity start before time lock", func(t *testing.T) {
+		t.Parallel()
+
+		tx, _, err := build(t, 51)
+		require.NoError(t, err)
+
+		require.Equal(t, uint64(4_950), tx.ValidityStart)
+		require.Equal(t, uint64(5_100), tx.TimeToLive)
+	})
+
+	t.Run("with validity start", func(t *testing.T) {
+		t.Parallel()
+
+		tx, fee, err := build(t, 50)
+		require.NoError(t, err)
+
+		require.Equal(t, uint64(4_950), tx.ValidityStart)
+		require.Equal(t, uint64(5_100), tx.TimeToLive)
+		require.Equal(t, fee, tx.Fee)
+		require.Len(t, tx.NativeScripts, 1)
+	})
+}
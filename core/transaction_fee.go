@@ -0,0 +1,186 @@
+package core
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+)
+
+const (
+	// vkey witness is encoded as [vkey (32 bytes), signature (64 bytes)]
+	vkeyWitnessSize = 1 + (2 + KeySize) + (2 + 64)
+	// fee placeholder is encoded with the same number of bytes as any fee up to 4294 ada
+	feePlaceholder = math.MaxUint32
+
+	// default size of reference scripts fee tier and multiplier of the price for the next tier
+	defaultRefScriptFeeRange      = 25_600
+	defaultRefScriptFeeMultiplier = 1.2
+)
+
+// CalculateTxFee calculates minimal fee for transaction of txSize bytes (witnesses included)
+// which spends or references scripts of referenceScriptsSize bytes in total
+func CalculateTxFee(protocolParams ProtocolParameters, txSize uint64, referenceScriptsSize uint64) uint64 {
+	return protocolParams.TxFeeFixed + protocolParams.TxFeePerByte*txSize +
+		GetReferenceScriptsFee(protocolParams, referenceScriptsSize)
+}
+
+// GetReferenceScriptsFee calculates conway era tiered fee for reference scripts:
+// every MinFeeRefScriptRange (25600 by default) bytes the price per byte
+// is multiplied by MinFeeRefScriptMultiplier (1.2 by default)
+func GetReferenceScriptsFee(protocolParams ProtocolParameters, referenceScriptsSize uint64) uint64 {
+	if referenceScriptsSize == 0 || protocolParams.MinFeeRefScriptCostPerByte == 0 {
+		return 0
+	}
+
+	tierRange, multiplierValue := protocolParams.MinFeeRefScriptRange, protocolParams.MinFeeRefScriptMultiplier
+	if tierRange == 0 {
+		tierRange = defaultRefScriptFeeRange
+	}
+
+	if multiplierValue == 0 {
+		multiplierValue = defaultRefScriptFeeMultiplier
+	}
+
+	tierPrice, ok := new(big.Rat).SetString(strconv.FormatFloat(protocolParams.MinFeeRefScriptCostPerByte, 'f', -1, 64))
+	if !ok {
+		return 0
+	}
+
+	multiplier, ok := new(big.Rat).SetString(strconv.FormatFloat(multiplierValue, 'f', -1, 64))
+	if !ok {
+		return 0
+	}
+
+	fee := new(big.Rat)
+	sizeIncrement := new(big.Rat).SetUint64(tierRange)
+
+	for referenceScriptsSize >= tierRange {
+		fee.Add(fee, new(big.Rat).Mul(sizeIncrement, tierPrice))
+		tierPrice.Mul(tierPrice, multiplier)
+
+		referenceScriptsSize -= tierRange
+	}
+
+	fee.Add(fee, new(big.Rat).Mul(new(big.Rat).SetUint64(referenceScriptsSize), tierPrice))
+
+	// floor
+	return new(big.Int).Quo(fee.Num(), fee.Denom()).Uint64()
+}
+
+// getVKeyWitnessesSize returns number of bytes which witnessCount vkey witnesses add to the transaction
+func getVKeyWitnessesSize(witnessCount int, isConway bool) uint64 {
+	if witnessCount <= 0 {
+		return 0
+	}
+
+	// witness set key + array header + witnesses
+	size := uint64(1 + len(getCborHeader(cborMajorTypeArray, uint64(witnessCount))) + witnessCount*vkeyWitnessSize)
+	if isConway {
+		size += uint64(len(cborSetTagPrefix))
+	}
+
+	return size
+}
+
+// calculateFeeNative calculates fee from draft transaction and estimated witnesses size
+func (b *TxBuilder) calculateFeeNative(witnessCount int) (uint64, error) {
+	protocolParams, err := b.getProtocolParameters()
+	if err != nil {
+		return 0, err
+	}
+
+	txRaw, _, err := b.buildRawTxNative(feePlaceholder)
+	if err != nil {
+		return 0, err
+	}
+
+	if witnessCount == 0 {
+		witnessCount, err = b.getWitnessCount()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	referenceScriptsSize, err := b.getReferenceScriptsSize()
+	if err != nil {
+		return 0, err
+	}
+
+	txSize := uint64(len(txRaw)) + getVKeyWitnessesSize(witnessCount, protocolParams.IsConwayEra())
+
+	return CalculateTxFee(protocolParams, txSize, referenceScriptsSize) +
+		GetExecutionUnitsFee(protocolParams, b.getTotalExUnits()), nil
+}
+
+// getWitnessCount estimates vkey witness count from policy scripts (inputs, mints, certificates, withdrawals, votes),
+// stake and voter keys and required signers. It is used by all backends so they estimate the same fee.
+// Policy script is counted for every input (or other item) it witnesses, same as cardano-cli fee estimation did,
+// and only sub scripts satisfiable in validity interval are counted
+func (b *TxBuilder) getWitnessCount() (int, error) {
+	var (
+		witnessCount int
+		hasKeyInput  bool
+	)
+
+	addPolicyScript := func(policyScript IPolicyScript) error {
+		cnt, err := getPolicyScriptCountInInterval(policyScript, b.validityStart, b.timeToLive)
+		if err != nil {
+			return err
+		}
+
+		witnessCount += cnt
+
+		return nil
+	}
+
+	for _, inp := range b.inputs {
+		if inp.policyScript != nil {
+			if err := addPolicyScript(inp.policyScript); err != nil {
+				return 0, err
+			}
+		} else if inp.plutusWitness == nil {
+			hasKeyInput = true
+		}
+	}
+
+	// owner of key locked inputs (collateral inputs included) is unknown, at least one witness is required
+	if hasKeyInput || len(b.collateralInputs) > 0 {
+		witnessCount++
+	}
+
+	for _, policyScript := range b.mints.policyScripts {
+		if err := addPolicyScript(policyScript); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, cert := range b.certificates {
+		if cert.policyScript != nil {
+			if err := addPolicyScript(cert.policyScript); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	for _, w := range b.withdrawals {
+		if w.policyScript != nil {
+			if err := addPolicyScript(w.policyScript); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	for _, v := range b.votes {
+		if v.policyScript != nil {
+			if err := addPolicyScript(v.policyScript); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	// every stake key which witnesses certificates or withdrawals and every drep or voter key
+	witnessCount += len(b.getStakeKeyHashesToSign()) + len(b.getGovernanceKeyHashesToSign())
+	witnessCount += len(b.getRequiredSigners())
+
+	return max(witnessCount, 1), nil
+}
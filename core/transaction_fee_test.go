@@ -0,0 +1,76 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetReferenceScriptsFee(t *testing.T) {
+	t.Parallel()
+
+	protocolParams := ProtocolParameters{
+		TxFeeFixed:                 155381,
+		TxFeePerByte:               44,
+		MinFeeRefScriptCostPerByte: 15,
+	}
+
+	require.Equal(t, uint64(0), GetReferenceScriptsFee(protocolParams, 0))
+	require.Equal(t, uint64(15_000), GetReferenceScriptsFee(protocolParams, 1_000))
+	require.Equal(t, uint64(384_000), GetReferenceScriptsFee(protocolParams, 25_600))
+	// 25600 * 15 + 25600 * 18 + 100 * 21.6
+	require.Equal(t, uint64(846_960), GetReferenceScriptsFee(protocolParams, 25_600*2+100))
+	// 25600 * 15 + 1 * 18
+	require.Equal(t, uint64(384_018), GetReferenceScriptsFee(protocolParams, 25_601))
+
+	require.Equal(t, uint64(155381+44*1000), CalculateTxFee(protocolParams, 1000, 0))
+	require.Equal(t, uint64(155381+44*1000+15_000), CalculateTxFee(protocolParams, 1000, 1000))
+
+	// tiers from protocol parameters: 1000 * 15 + 1000 * 30 + 500 * 60
+	protocolParams.MinFeeRefScriptRange, protocolParams.MinFeeRefScriptMultiplier = 1000, 2
+
+	require.Equal(t, uint64(75_000), GetReferenceScriptsFee(protocolParams, 2_500))
+
+	protocolParams.MinFeeRefScriptCostPerByte = 0
+
+	require.Equal(t, uint64(0), GetReferenceScriptsFee(protocolParams, 1_000))
+}
+
+func Test_TxBuilder_CalculateFeeNative(t *testing.T) {
+	t.Parallel()
+
+	builder, err := NewTxBuilder(ResolveCardanoCliBinary(TestNetNetwork))
+	require.NoError(t, err)
+
+	defer builder.Dispose()
+
+	builder.SetProtocolParameters(protocolParameters).SetTimeToLive(28096)
+	builder.AddInputs(NewTxInput("e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f", 0))
+	builder.AddOutputs(NewTxOutput("addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u", 1_000_000))
+
+	fee, err := builder.CalculateFee(0)
+	require.NoError(t, err)
+
+	builder.SetFee(fee)
+
+	txRaw, _, err := builder.Build()
+	require.NoError(t, err)
+
+	// fee placeholder is encoded with 5 bytes as the real fee
+	require.Equal(t, CalculateTxFee(ProtocolParameters{
+		TxFeeFixed:   155381,
+		TxFeePerByte: 44,
+	}, uint64(len(txRaw)+1+1+vkeyWitnessSize), 0), fee)
+
+	feeTwoWitnesses, err := builder.CalculateFee(2)
+	require.NoError(t, err)
+
+	require.Equal(t, fee+44*vkeyWitnessSize, feeTwoWitnesses)
+
+	builder.SetReferenceScriptsSize(1_000)
+
+	feeRefScripts, err := builder.CalculateFee(0)
+	require.NoError(t, err)
+
+	require.Equal(t, fee, feeRefScripts) // minFeeRefScriptCostPerByte is not set
+}
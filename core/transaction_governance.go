@@ -0,0 +1,790 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// transaction body keys of conway governance
+const (
+	txBodyVotingProceduresKey   = 19
+	txBodyProposalProceduresKey = 20
+)
+
+type TxVoterType uint64
+
+const (
+	CommitteeHotKeyHashVoter    TxVoterType = 0
+	CommitteeHotScriptHashVoter TxVoterType = 1
+	DRepKeyHashVoter            TxVoterType = 2
+	DRepScriptHashVoter         TxVoterType = 3
+	StakePoolVoter              TxVoterType = 4
+)
+
+type TxVote uint64
+
+const (
+	VoteNo      TxVote = 0
+	VoteYes     TxVote = 1
+	VoteAbstain TxVote = 2
+)
+
+type TxGovActionType uint64
+
+const (
+	ParameterChangeGovAction     TxGovActionType = 0
+	HardForkInitiationGovAction  TxGovActionType = 1
+	TreasuryWithdrawalsGovAction TxGovActionType = 2
+	NoConfidenceGovAction        TxGovActionType = 3
+	UpdateCommitteeGovAction     TxGovActionType = 4
+	NewConstitutionGovAction     TxGovActionType = 5
+	InfoGovAction                TxGovActionType = 6
+)
+
+// TxVoter is committee member (hot credential), drep or stake pool which votes
+type TxVoter struct {
+	Type TxVoterType `json:"type"`
+	Hash string      `json:"hash"`
+}
+
+// TxGovActionID identifies governance action by transaction which proposed it and proposal index
+type TxGovActionID struct {
+	TxHash string `json:"txHash"`
+	Index  uint32 `json:"index"`
+}
+
+// TxVotingProcedure is vote of the voter for the governance action
+type TxVotingProcedure struct {
+	Voter       TxVoter       `json:"voter"`
+	GovActionID TxGovActionID `json:"govActionId"`
+	Vote        TxVote        `json:"vote"`
+	Anchor      *TxAnchor     `json:"anchor,omitempty"`
+}
+
+// TxProtocolParamUpdate holds protocol parameters changed by parameter change action. Nil fields are not changed.
+// Parameters without own field (e.g. cost models or execution prices) are kept as cbor in Other
+type TxProtocolParamUpdate struct {
+	TxFeePerByte           *uint64 `cbor:"0,keyasint,omitempty" json:"txFeePerByte,omitempty"`
+	TxFeeFixed             *uint64 `cbor:"1,keyasint,omitempty" json:"txFeeFixed,omitempty"`
+	MaxBlockBodySize       *uint64 `cbor:"2,keyasint,omitempty" json:"maxBlockBodySize,omitempty"`
+	MaxTxSize              *uint64 `cbor:"3,keyasint,omitempty" json:"maxTxSize,omitempty"`
+	MaxBlockHeaderSize     *uint64 `cbor:"4,keyasint,omitempty" json:"maxBlockHeaderSize,omitempty"`
+	StakeAddressDeposit    *uint64 `cbor:"5,keyasint,omitempty" json:"stakeAddressDeposit,omitempty"`
+	StakePoolDeposit       *uint64 `cbor:"6,keyasint,omitempty" json:"stakePoolDeposit,omitempty"`
+	PoolRetireMaxEpoch     *uint64 `cbor:"7,keyasint,omitempty" json:"poolRetireMaxEpoch,omitempty"`
+	StakePoolTargetNum     *uint64 `cbor:"8,keyasint,omitempty" json:"stakePoolTargetNum,omitempty"`
+	MinPoolCost            *uint64 `cbor:"16,keyasint,omitempty" json:"minPoolCost,omitempty"`
+	UtxoCostPerByte        *uint64 `cbor:"17,keyasint,omitempty" json:"utxoCostPerByte,omitempty"`
+	MaxValueSize           *uint64 `cbor:"22,keyasint,omitempty" json:"maxValueSize,omitempty"`
+	CollateralPercentage   *uint64 `cbor:"23,keyasint,omitempty" json:"collateralPercentage,omitempty"`
+	MaxCollateralInputs    *uint64 `cbor:"24,keyasint,omitempty" json:"maxCollateralInputs,omitempty"`
+	CommitteeMinSize       *uint64 `cbor:"27,keyasint,omitempty" json:"committeeMinSize,omitempty"`
+	CommitteeMaxTermLength *uint64 `cbor:"28,keyasint,omitempty" json:"committeeMaxTermLength,omitempty"`
+	GovActionLifetime      *uint64 `cbor:"29,keyasint,omitempty" json:"govActionLifetime,omitempty"`
+	GovActionDeposit       *uint64 `cbor:"30,keyasint,omitempty" json:"govActionDeposit,omitempty"`
+	DRepDeposit            *uint64 `cbor:"31,keyasint,omitempty" json:"dRepDeposit,omitempty"`
+	DRepActivity           *uint64 `cbor:"32,keyasint,omitempty" json:"dRepActivity,omitempty"`
+
+	Other map[uint64]cbor.RawMessage `cbor:"-" json:"other,omitempty"`
+}
+
+// txProtocolParamUpdateFields has default cbor encoding of the modeled parameters
+type txProtocolParamUpdateFields TxProtocolParamUpdate
+
+// MarshalCBOR encodes modeled and other parameters ordered by their keys
+func (u TxProtocolParamUpdate) MarshalCBOR() ([]byte, error) {
+	params, err := u.getCborParams()
+	if err != nil {
+		return nil, err
+	} else if len(params) == 0 {
+		return nil, errors.New("empty protocol parameters update")
+	}
+
+	keys := make([]uint64, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+
+	result := make(cborOrderedMap, len(keys))
+	for i, key := range keys {
+		result[i] = cborKeyValue{Key: key, Value: params[key]}
+	}
+
+	return cbor.Marshal(result)
+}
+
+// UnmarshalCBOR decodes modeled parameters into fields and keeps all the others in Other
+func (u *TxProtocolParamUpdate) UnmarshalCBOR(data []byte) error {
+	var (
+		params map[uint64]cbor.RawMessage
+		fields txProtocolParamUpdateFields
+	)
+
+	if err := cbor.Unmarshal(data, &params); err != nil {
+		return err
+	}
+
+	if err := cbor.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	modeled, err := TxProtocolParamUpdate(fields).getCborParams()
+	if err != nil {
+		return err
+	}
+
+	*u = TxProtocolParamUpdate(fields)
+
+	for key, value := range params {
+		if _, exists := modeled[key]; !exists {
+			if u.Other == nil {
+				u.Other = map[uint64]cbor.RawMessage{}
+			}
+
+			u.Other[key] = value
+		}
+	}
+
+	return nil
+}
+
+func (u TxProtocolParamUpdate) getCborParams() (map[uint64]cbor.RawMessage, error) {
+	fieldsBytes, err := cbor.Marshal(txProtocolParamUpdateFields(u))
+	if err != nil {
+		return nil, err
+	}
+
+	var params map[uint64]cbor.RawMessage
+
+	if err := cbor.Unmarshal(fieldsBytes, &params); err != nil {
+		return nil, err
+	}
+
+	for key, value := range u.Other {
+		if _, exists := params[key]; exists {
+			return nil, fmt.Errorf("protocol parameter %d is set both as field and in other", key)
+		}
+
+		params[key] = value
+	}
+
+	return params, nil
+}
+
+// TxGovAction is governance action. Fields which are not part of the action type are empty
+type TxGovAction struct {
+	Type         TxGovActionType        `json:"type"`
+	PrevActionID *TxGovActionID         `json:"prevActionId,omitempty"`
+	ParamUpdate  *TxProtocolParamUpdate `json:"paramUpdate,omitempty"`
+	Withdrawals  []TxWithdrawal         `json:"withdrawals,omitempty"`
+	PolicyHash   string                 `json:"policyHash,omitempty"`
+	Raw          []byte                 `json:"-"` // governance action cbor
+}
+
+// TxProposal is governance action proposal. Deposit is returned to the reward address
+type TxProposal struct {
+	Deposit       uint64      `json:"deposit"`
+	RewardAddress string      `json:"rewardAddress"`
+	Action        TxGovAction `json:"action"`
+	Anchor        TxAnchor    `json:"anchor"`
+}
+
+func NewTxVoter(voterType TxVoterType, hash string) TxVoter {
+	return TxVoter{
+		Type: voterType,
+		Hash: hash,
+	}
+}
+
+// NewDRepVoter creates drep voter from drep credential (key hash or script hash)
+func NewDRepVoter(drepCredential TxCredential) TxVoter {
+	if drepCredential.IsScript {
+		return NewTxVoter(DRepScriptHashVoter, drepCredential.Hash)
+	}
+
+	return NewTxVoter(DRepKeyHashVoter, drepCredential.Hash)
+}
+
+// NewCommitteeVoter creates constitutional committee voter from hot credential (key hash or script hash)
+func NewCommitteeVoter(hotCredential TxCredential) TxVoter {
+	if hotCredential.IsScript {
+		return NewTxVoter(CommitteeHotScriptHashVoter, hotCredential.Hash)
+	}
+
+	return NewTxVoter(CommitteeHotKeyHashVoter, hotCredential.Hash)
+}
+
+// NewStakePoolVoter creates stake pool operator voter from bech32 (pool1...) or hex pool id
+func NewStakePoolVoter(poolID string) (TxVoter, error) {
+	poolKeyHash, err := GetPoolKeyHash(poolID)
+	if err != nil {
+		return TxVoter{}, err
+	}
+
+	return NewTxVoter(StakePoolVoter, poolKeyHash), nil
+}
+
+func (v TxVoter) isScript() bool {
+	return v.Type == CommitteeHotScriptHashVoter || v.Type == DRepScriptHashVoter
+}
+
+func (v TxVoter) MarshalCBOR() ([]byte, error) {
+	if v.Type > StakePoolVoter {
+		return nil, fmt.Errorf("unknown voter type: %d", v.Type)
+	}
+
+	credential, err := NewTxCredential(v.Hash, false).getCbor()
+	if err != nil {
+		return nil, err
+	}
+
+	return cbor.Marshal([]interface{}{uint64(v.Type), credential[1]})
+}
+
+func NewTxGovActionID(txHash string, index uint32) TxGovActionID {
+	return TxGovActionID{
+		TxHash: txHash,
+		Index:  index,
+	}
+}
+
+func (id TxGovActionID) String() string {
+	return fmt.Sprintf("%s#%d", id.TxHash, id.Index)
+}
+
+func (id TxGovActionID) MarshalCBOR() ([]byte, error) {
+	txHash, err := hex.DecodeString(id.TxHash)
+	if err != nil {
+		return nil, err
+	} else if len(txHash) != TxHashSize {
+		return nil, fmt.Errorf("invalid governance action transaction hash: %s", id.TxHash)
+	}
+
+	return cbor.Marshal([]interface{}{txHash, id.Index})
+}
+
+// NewTxVotingProcedure creates vote of the voter for the governance action. Anchor is optional
+func NewTxVotingProcedure(voter TxVoter, govActionID TxGovActionID, vote TxVote, anchor *TxAnchor) TxVotingProcedure {
+	return TxVotingProcedure{
+		Voter:       voter,
+		GovActionID: govActionID,
+		Vote:        vote,
+		Anchor:      anchor,
+	}
+}
+
+// NewInfoProposal creates info governance action proposal
+func NewInfoProposal(deposit uint64, rewardAddress string, anchor TxAnchor) TxProposal {
+	return TxProposal{
+		Deposit:       deposit,
+		RewardAddress: rewardAddress,
+		Action:        TxGovAction{Type: InfoGovAction},
+		Anchor:        anchor,
+	}
+}
+
+// NewTreasuryWithdrawalsProposal creates proposal which withdraws lovelace from treasury to reward addresses.
+// policyHash is hash of the guardrails script (empty if there is none)
+func NewTreasuryWithdrawalsProposal(
+	deposit uint64, rewardAddress string, anchor TxAnchor, withdrawals []TxWithdrawal, policyHash string,
+) TxProposal {
+	return TxProposal{
+		Deposit:       deposit,
+		RewardAddress: rewardAddress,
+		Action: TxGovAction{
+			Type:        TreasuryWithdrawalsGovAction,
+			Withdrawals: withdrawals,
+			PolicyHash:  policyHash,
+		},
+		Anchor: anchor,
+	}
+}
+
+// NewParameterChangeProposal creates proposal which changes protocol parameters.
+// prevActionID is the last enacted parameter change action (nil if there is none)
+// and policyHash is hash of the guardrails script (empty if there is none)
+func NewParameterChangeProposal(
+	deposit uint64, rewardAddress string, anchor TxAnchor,
+	prevActionID *TxGovActionID, paramUpdate TxProtocolParamUpdate, policyHash string,
+) TxProposal {
+	return TxProposal{
+		Deposit:       deposit,
+		RewardAddress: rewardAddress,
+		Action: TxGovAction{
+			Type:         ParameterChangeGovAction,
+			PrevActionID: prevActionID,
+			ParamUpdate:  &paramUpdate,
+			PolicyHash:   policyHash,
+		},
+		Anchor: anchor,
+	}
+}
+
+// MarshalCBOR encodes governance action. Action types which can not be created by this library are encoded from Raw
+func (a TxGovAction) MarshalCBOR() ([]byte, error) {
+	var fields []interface{}
+
+	switch a.Type {
+	case ParameterChangeGovAction:
+		if a.ParamUpdate == nil {
+			return nil, errors.New("missing protocol parameters update")
+		}
+
+		fields = []interface{}{uint64(a.Type), getGovActionIDCbor(a.PrevActionID), a.ParamUpdate}
+	case TreasuryWithdrawalsGovAction:
+		withdrawals, err := getTxWithdrawalsCbor(a.Withdrawals)
+		if err != nil {
+			return nil, err
+		}
+
+		fields = []interface{}{uint64(a.Type), withdrawals}
+	case InfoGovAction:
+		fields = []interface{}{uint64(a.Type)}
+	default:
+		if a.Raw == nil {
+			return nil, fmt.Errorf("unsupported governance action type %d", a.Type)
+		}
+
+		return a.Raw, nil
+	}
+
+	if a.Type == ParameterChangeGovAction || a.Type == TreasuryWithdrawalsGovAction {
+		var policyHash interface{}
+
+		if a.PolicyHash != "" {
+			policyHashBytes, err := hex.DecodeString(a.PolicyHash)
+			if err != nil {
+				return nil, err
+			} else if len(policyHashBytes) != KeyHashSize {
+				return nil, fmt.Errorf("invalid policy hash: %s", a.PolicyHash)
+			}
+
+			policyHash = policyHashBytes
+		}
+
+		fields = append(fields, policyHash)
+	}
+
+	return cbor.Marshal(fields)
+}
+
+func (p TxProposal) MarshalCBOR() ([]byte, error) {
+	rewardAddress, _, err := NewTxWithdrawal(p.RewardAddress, 0).getStakeCredential()
+	if err != nil {
+		return nil, err
+	}
+
+	anchor, err := getAnchorCbor(&p.Anchor)
+	if err != nil {
+		return nil, err
+	}
+
+	return cbor.Marshal([]interface{}{p.Deposit, rewardAddress.GetBytes(), p.Action, anchor})
+}
+
+// getGovActionIDCbor returns governance action id or nil (encoded as null) if it is not set
+func getGovActionIDCbor(id *TxGovActionID) interface{} {
+	if id == nil {
+		return nil
+	}
+
+	return *id
+}
+
+type txVotingProcedureWithScript struct {
+	vote         TxVotingProcedure
+	policyScript IPolicyScript
+}
+
+// AddProposals adds governance action proposals
+func (b *TxBuilder) AddProposals(proposals ...TxProposal) *TxBuilder {
+	b.proposals = append(b.proposals, proposals...)
+
+	return b
+}
+
+// AddVotes adds votes of key hash voters
+func (b *TxBuilder) AddVotes(votes ...TxVotingProcedure) *TxBuilder {
+	for _, vote := range votes {
+		b.votes = append(b.votes, txVotingProcedureWithScript{
+			vote: vote,
+		})
+	}
+
+	return b
+}
+
+// AddVotesWithScript adds votes of the native script voter (drep or committee member)
+func (b *TxBuilder) AddVotesWithScript(script IPolicyScript, votes ...TxVotingProcedure) *TxBuilder {
+	for _, vote := range votes {
+		b.votes = append(b.votes, txVotingProcedureWithScript{
+			vote:         vote,
+			policyScript: script,
+		})
+	}
+
+	return b
+}
+
+// getVotingProceduresCbor returns voter => (governance action id => voting procedure) map.
+// Voters and governance action ids are ordered by their cbor
+func getVotingProceduresCbor(votes []TxVotingProcedure) (cborOrderedMap, error) {
+	votesByVoter := map[string]cborOrderedMap{}
+
+	for _, vote := range votes {
+		voterBytes, err := vote.Voter.MarshalCBOR()
+		if err != nil {
+			return nil, err
+		}
+
+		govActionIDBytes, err := vote.GovActionID.MarshalCBOR()
+		if err != nil {
+			return nil, err
+		}
+
+		anchor, err := getAnchorCbor(vote.Anchor)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, kv := range votesByVoter[string(voterBytes)] {
+			if bytes.Equal(kv.Key.(cbor.RawMessage), govActionIDBytes) { //nolint:forcetypeassert
+				return nil, fmt.Errorf("duplicate vote of %s for %s", vote.Voter.Hash, vote.GovActionID)
+			}
+		}
+
+		votesByVoter[string(voterBytes)] = append(votesByVoter[string(voterBytes)], cborKeyValue{
+			Key:   cbor.RawMessage(govActionIDBytes),
+			Value: []interface{}{uint64(vote.Vote), anchor},
+		})
+	}
+
+	result := make(cborOrderedMap, 0, len(votesByVoter))
+
+	for voter, govActions := range votesByVoter {
+		sortCborOrderedMapByRawKey(govActions)
+
+		result = append(result, cborKeyValue{Key: cbor.RawMessage(voter), Value: govActions})
+	}
+
+	sortCborOrderedMapByRawKey(result)
+
+	return result, nil
+}
+
+func sortCborOrderedMapByRawKey(m cborOrderedMap) {
+	sort.Slice(m, func(i, j int) bool {
+		return bytes.Compare(m[i].Key.(cbor.RawMessage), m[j].Key.(cbor.RawMessage)) < 0 //nolint:forcetypeassert
+	})
+}
+
+func (b *TxBuilder) getVotes() []TxVotingProcedure {
+	votes := make([]TxVotingProcedure, len(b.votes))
+	for i, v := range b.votes {
+		votes[i] = v.vote
+	}
+
+	return votes
+}
+
+// getGovernanceKeyHashesToSign returns key hashes of drep credentials and voters which must witness the transaction
+func (b *TxBuilder) getGovernanceKeyHashesToSign() (keyHashes []string) {
+	seen := map[string]bool{}
+
+	add := func(hash string) {
+		if !seen[hash] {
+			seen[hash] = true
+			keyHashes = append(keyHashes, hash)
+		}
+	}
+
+	for _, cert := range b.certificates {
+		if credential := cert.certificate.DRepCredential; credential != nil && !credential.IsScript {
+			add(credential.Hash)
+		}
+	}
+
+	for _, v := range b.votes {
+		if !v.vote.Voter.isScript() {
+			add(v.vote.Voter.Hash)
+		}
+	}
+
+	return keyHashes
+}
+
+// applyGovernance adds cardano-cli build-raw arguments for votes and proposals
+func (b *TxBuilder) applyGovernance(args *[]string) error {
+	writeTextEnvelope := func(fileName, envelopeType string, cborBytes []byte) (string, error) {
+		content, err := json.Marshal(map[string]interface{}{
+			"type":        envelopeType,
+			"description": "",
+			"cborHex":     hex.EncodeToString(cborBytes),
+		})
+		if err != nil {
+			return "", err
+		}
+
+		filePath := filepath.Join(b.baseDirectory, fileName)
+
+		return filePath, os.WriteFile(filePath, content, FilePermission)
+	}
+
+	for i, v := range b.votes {
+		votingProcedures, err := getVotingProceduresCbor([]TxVotingProcedure{v.vote})
+		if err != nil {
+			return err
+		}
+
+		votingProceduresBytes, err := votingProcedures.MarshalCBOR()
+		if err != nil {
+			return err
+		}
+
+		voteFilePath, err := writeTextEnvelope(
+			fmt.Sprintf("vote_%d.json", i), "Governance voting procedures", votingProceduresBytes)
+		if err != nil {
+			return err
+		}
+
+		*args = append(*args, "--vote-file", voteFilePath)
+
+		if v.policyScript == nil {
+			continue
+		}
+
+		policyScriptJSON, err := v.policyScript.GetPolicyScriptJSON()
+		if err != nil {
+			return err
+		}
+
+		policyFilePath := filepath.Join(b.baseDirectory, fmt.Sprintf("vote_policy_%d.json", i))
+		if err := os.WriteFile(policyFilePath, policyScriptJSON, FilePermission); err != nil {
+			return err
+		}
+
+		*args = append(*args, "--vote-script-file", policyFilePath)
+	}
+
+	for i, proposal := range b.proposals {
+		proposalBytes, err := proposal.MarshalCBOR()
+		if err != nil {
+			return fmt.Errorf("proposal %d: %w", i, err)
+		}
+
+		proposalFilePath, err := writeTextEnvelope(
+			fmt.Sprintf("proposal_%d.json", i), "Governance proposal", proposalBytes)
+		if err != nil {
+			return err
+		}
+
+		*args = append(*args, "--proposal-file", proposalFilePath)
+	}
+
+	return nil
+}
+
+// decodeTxVotingProcedures decodes voter => (governance action id => voting procedure) map
+func decodeTxVotingProcedures(data []byte) ([]TxVotingProcedure, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	voters, err := decodeCborMapEntries(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []TxVotingProcedure
+
+	for _, voterEntry := range voters {
+		var voter struct {
+			_    struct{} `cbor:",toarray"`
+			Type TxVoterType
+			Hash []byte
+		}
+
+		if err := cbor.Unmarshal(voterEntry[0], &voter); err != nil {
+			return nil, err
+		}
+
+		govActions, err := decodeCborMapEntries(voterEntry[1])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, govActionEntry := range govActions {
+			var votingProcedure struct {
+				_      struct{} `cbor:",toarray"`
+				Vote   TxVote
+				Anchor cbor.RawMessage
+			}
+
+			govActionID, err := decodeTxGovActionID(govActionEntry[0])
+			if err != nil {
+				return nil, err
+			}
+
+			if err := cbor.Unmarshal(govActionEntry[1], &votingProcedure); err != nil {
+				return nil, err
+			}
+
+			anchor, err := decodeTxAnchor(votingProcedure.Anchor)
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, TxVotingProcedure{
+				Voter:       NewTxVoter(voter.Type, hex.EncodeToString(voter.Hash)),
+				GovActionID: *govActionID,
+				Vote:        votingProcedure.Vote,
+				Anchor:      anchor,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// decodeTxGovActionID decodes [transaction id, index] or null
+func decodeTxGovActionID(data []byte) (*TxGovActionID, error) {
+	var id *struct {
+		_      struct{} `cbor:",toarray"`
+		TxHash []byte
+		Index  uint32
+	}
+
+	if err := cbor.Unmarshal(data, &id); err != nil {
+		return nil, err
+	} else if id == nil {
+		return nil, nil
+	}
+
+	return &TxGovActionID{
+		TxHash: hex.EncodeToString(id.TxHash),
+		Index:  id.Index,
+	}, nil
+}
+
+func decodeTxProposals(data []byte) ([]TxProposal, error) {
+	proposals, _, err := decodeCborSet(data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]TxProposal, len(proposals))
+
+	for i, proposalBytes := range proposals {
+		var proposal struct {
+			_             struct{} `cbor:",toarray"`
+			Deposit       uint64
+			RewardAccount []byte
+			Action        cbor.RawMessage
+			Anchor        cbor.RawMessage
+		}
+
+		if err := cbor.Unmarshal(proposalBytes, &proposal); err != nil {
+			return nil, fmt.Errorf("proposal %d: %w", i, err)
+		}
+
+		rewardAddress, err := NewCardanoAddress(proposal.RewardAccount)
+		if err != nil {
+			return nil, fmt.Errorf("proposal %d: %w", i, err)
+		}
+
+		action, err := decodeTxGovAction(proposal.Action)
+		if err != nil {
+			return nil, fmt.Errorf("proposal %d: %w", i, err)
+		}
+
+		anchor, err := decodeTxAnchor(proposal.Anchor)
+		if err != nil {
+			return nil, fmt.Errorf("proposal %d: %w", i, err)
+		} else if anchor == nil {
+			return nil, fmt.Errorf("proposal %d: missing anchor", i)
+		}
+
+		result[i] = TxProposal{
+			Deposit:       proposal.Deposit,
+			RewardAddress: rewardAddress.String(),
+			Action:        action,
+			Anchor:        *anchor,
+		}
+	}
+
+	return result, nil
+}
+
+func decodeTxGovAction(data []byte) (TxGovAction, error) {
+	var fields []cbor.RawMessage
+
+	if err := cbor.Unmarshal(data, &fields); err != nil {
+		return TxGovAction{}, err
+	} else if len(fields) == 0 {
+		return TxGovAction{}, errors.New("empty governance action")
+	}
+
+	action := TxGovAction{
+		Raw: data,
+	}
+
+	if err := cbor.Unmarshal(fields[0], &action.Type); err != nil {
+		return TxGovAction{}, err
+	}
+
+	switch action.Type {
+	case ParameterChangeGovAction:
+		if len(fields) != 4 {
+			return TxGovAction{}, fmt.Errorf("invalid fields count %d for governance action type %d",
+				len(fields), action.Type)
+		}
+
+		prevActionID, err := decodeTxGovActionID(fields[1])
+		if err != nil {
+			return TxGovAction{}, err
+		}
+
+		action.PrevActionID = prevActionID
+		action.ParamUpdate = &TxProtocolParamUpdate{}
+
+		if err := cbor.Unmarshal(fields[2], action.ParamUpdate); err != nil {
+			return TxGovAction{}, err
+		}
+	case TreasuryWithdrawalsGovAction:
+		if len(fields) != 3 {
+			return TxGovAction{}, fmt.Errorf("invalid fields count %d for governance action type %d",
+				len(fields), action.Type)
+		}
+
+		withdrawals, err := decodeTxWithdrawals(fields[1])
+		if err != nil {
+			return TxGovAction{}, err
+		}
+
+		action.Withdrawals = withdrawals
+	default:
+		return action, nil
+	}
+
+	var policyHash []byte
+
+	if err := cbor.Unmarshal(fields[len(fields)-1], &policyHash); err != nil {
+		return TxGovAction{}, err
+	}
+
+	action.PolicyHash = hex.EncodeToString(policyHash)
+
+	return action, nil
+}
@@ -0,0 +1,255 @@
+package core
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/igorcrevar/go-cardano-tx/core/clitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TxBuilder_Governance(t *testing.T) {
+	t.Parallel()
+
+	const (
+		poolKeyHash = "2c4e1fc7ae53aa0ebeb14a4e8d5d8a5fd4fa0bd9fd3c6e25d8d4e06e"
+		policyHash  = "fa24fb305126805cf2164c161d852a0e7330cf988f1fe558cf7d4a64"
+	)
+
+	wallet, err := GenerateWallet(true)
+	require.NoError(t, err)
+
+	rewardAddress, err := NewRewardAddress(TestNetNetwork, wallet.StakeVerificationKey)
+	require.NoError(t, err)
+
+	anchor := NewTxAnchor("https://example.com/proposal.json", strings.Repeat("cd", 32))
+	govActionID := NewTxGovActionID(testInputHash, 1)
+
+	t.Run("proposals", func(t *testing.T) {
+		t.Parallel()
+
+		maxTxSize, drepDeposit := uint64(32_768), uint64(500_000_000)
+		paramUpdate := TxProtocolParamUpdate{MaxTxSize: &maxTxSize, DRepDeposit: &drepDeposit}
+
+		proposals := []TxProposal{
+			NewInfoProposal(100_000_000, rewardAddress.String(), anchor),
+			NewTreasuryWithdrawalsProposal(100_000_000, rewardAddress.String(), anchor,
+				[]TxWithdrawal{NewTxWithdrawal(rewardAddress.String(), 5_000_000)}, policyHash),
+			NewParameterChangeProposal(100_000_000, rewardAddress.String(), anchor,
+				&govActionID, paramUpdate, ""),
+		}
+
+		builder := newTestTxBuilder(t, conwayProtocolParameters)
+		builder.AddProposals(proposals...)
+
+		consumed, produced, err := builder.GetImplicitCoin()
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(0), consumed)
+		assert.Equal(t, uint64(300_000_000), produced)
+
+		txRaw, _, err := builder.Build()
+		require.NoError(t, err)
+
+		tx, err := NewTransaction(txRaw)
+		require.NoError(t, err)
+
+		require.Len(t, tx.Proposals, 3)
+
+		for i, proposal := range tx.Proposals {
+			assert.Equal(t, proposals[i].Deposit, proposal.Deposit)
+			assert.Equal(t, proposals[i].RewardAddress, proposal.RewardAddress)
+			assert.Equal(t, proposals[i].Anchor, proposal.Anchor)
+			assert.Equal(t, proposals[i].Action.Type, proposal.Action.Type)
+
+			proposal.Action.Raw = nil
+
+			assert.Equal(t, proposals[i].Action, proposal.Action)
+		}
+	})
+
+	t.Run("votes", func(t *testing.T) {
+		t.Parallel()
+
+		drepWallet, err := GenerateDRepWallet()
+		require.NoError(t, err)
+
+		drepCredential, err := drepWallet.GetCredential()
+		require.NoError(t, err)
+
+		multisigDRep := NewPolicyScript([]string{
+			"2411b7ef3c33d35c2f1e1a2fa4ef0a63e1aa5fe9ac9cd8ca4d1c1b09",
+			"9fd3c6e25d8d4e06e6d5f40a12c4e1fc7ae53aa0ebeb14a4e8d5d8a5",
+		}, 2)
+
+		multisigDRepCredential, err := NewTxCredentialFromScript(multisigDRep)
+		require.NoError(t, err)
+
+		poolVoter, err := NewStakePoolVoter(poolKeyHash)
+		require.NoError(t, err)
+
+		votes := []TxVotingProcedure{
+			NewTxVotingProcedure(NewDRepVoter(drepCredential), govActionID, VoteYes, &anchor),
+			NewTxVotingProcedure(NewDRepVoter(multisigDRepCredential), govActionID, VoteNo, nil),
+			NewTxVotingProcedure(poolVoter, govActionID, VoteAbstain, nil),
+		}
+
+		builder := newTestTxBuilder(t, conwayProtocolParameters)
+		builder.AddVotes(votes[0], votes[2])
+		builder.AddVotesWithScript(multisigDRep, votes[1])
+
+		// payment key, drep key, pool key and two keys of the multisig drep
+		witnessCount, err := builder.getWitnessCount()
+		require.NoError(t, err)
+
+		assert.Equal(t, 5, witnessCount)
+
+		txRaw, _, err := builder.Build()
+		require.NoError(t, err)
+
+		txSigned, err := builder.SignTx(txRaw, []ITxSigner{wallet, drepWallet})
+		require.NoError(t, err)
+
+		tx, err := NewTransaction(txSigned)
+		require.NoError(t, err)
+
+		// voters are ordered by type: drep key hash, drep script hash, stake pool
+		assert.Equal(t, votes, tx.VotingProcedures)
+		assert.Len(t, tx.NativeScripts, 1)
+		assert.Len(t, tx.VKeyWitnesses, 2)
+
+		description, err := DescribeTx(txSigned)
+		require.NoError(t, err)
+
+		assert.Contains(t, description.String(), "pool:"+poolKeyHash+" "+govActionID.String()+" abstain")
+
+		builder = newTestTxBuilder(t, conwayProtocolParameters)
+		builder.AddVotes(votes[0], votes[0])
+
+		_, _, err = builder.Build()
+		require.ErrorContains(t, err, "duplicate vote")
+	})
+
+	t.Run("babbage", func(t *testing.T) {
+		t.Parallel()
+
+		builder := newTestTxBuilder(t, conwayProtocolParameters)
+		builder.SetProtocolParameters(protocolParameters)
+		builder.AddProposals(NewInfoProposal(100_000_000, rewardAddress.String(), anchor))
+
+		_, _, err := builder.Build()
+		require.ErrorContains(t, err, "not supported in Babbage era")
+	})
+
+	t.Run("cardano-cli", func(t *testing.T) {
+		t.Parallel()
+
+		var builder *TxBuilder
+
+		fakeCli := clitest.NewFakeCardanoCli().
+			HandleFunc(func(call clitest.Call) clitest.Response {
+				txRaw, _, err := builder.buildRawTxNative(0)
+				if err != nil {
+					return clitest.Response{Err: err}
+				}
+
+				return clitest.Response{Files: map[string][]byte{
+					"--out-file": clitest.TextEnvelope("Unwitnessed Tx ConwayEra", txRaw),
+				}}
+			}, "transaction", "build-raw").
+			Handle(clitest.Response{Stdout: strings.Repeat("ab", 32)}, "transaction", "txid").
+			Handle(clitest.Response{Stdout: "170000 Lovelace\n"}, "transaction", "calculate-min-fee")
+
+		multisigDRep := NewPolicyScript([]string{"2411b7ef3c33d35c2f1e1a2fa4ef0a63e1aa5fe9ac9cd8ca4d1c1b09"}, 1)
+
+		multisigDRepCredential, err := NewTxCredentialFromScript(multisigDRep)
+		require.NoError(t, err)
+
+		builder = newTestTxBuilder(t, conwayProtocolParameters, WithCardanoCliBuild(WithCommandRunner(fakeCli)))
+		builder.AddVotesWithScript(multisigDRep,
+			NewTxVotingProcedure(NewDRepVoter(multisigDRepCredential), govActionID, VoteYes, nil))
+		builder.AddProposals(NewInfoProposal(100_000_000, rewardAddress.String(), anchor))
+
+		poolVoter, err := NewStakePoolVoter(poolKeyHash)
+		require.NoError(t, err)
+
+		builder.AddVotes(NewTxVotingProcedure(poolVoter, govActionID, VoteNo, nil))
+
+		_, err = builder.CalculateFee(0)
+		require.NoError(t, err)
+
+		feeCalls := fakeCli.CallsOf("transaction", "calculate-min-fee")
+		require.Len(t, feeCalls, 1)
+
+		// payment key, pool key and the key of the multisig drep
+		assert.Equal(t, "3", feeCalls[0].Flag("--witness-count"))
+
+		_, _, err = builder.Build()
+		require.NoError(t, err)
+
+		calls := fakeCli.CallsOf("transaction", "build-raw")
+		require.Len(t, calls, 2)
+
+		assert.True(t, calls[1].HasFlag("--vote-script-file"))
+
+		content, err := os.ReadFile(calls[1].Flag("--vote-file"))
+		require.NoError(t, err)
+
+		assert.Contains(t, string(content), `"type":"Governance voting procedures"`)
+
+		content, err = os.ReadFile(calls[1].Flag("--proposal-file"))
+		require.NoError(t, err)
+
+		assert.Contains(t, string(content), `"type":"Governance proposal"`)
+	})
+}
+
+func TestTxProtocolParamUpdate(t *testing.T) {
+	t.Parallel()
+
+	txFeeFixed := uint64(44)
+
+	// tx fee fixed, pool pledge influence (rational) and cost models which have no field
+	paramsBytes, err := cbor.Marshal(cborOrderedMap{
+		{Key: uint64(1), Value: txFeeFixed},
+		{Key: uint64(9), Value: cbor.Tag{Number: 30, Content: []uint64{3, 10}}},
+		{Key: uint64(18), Value: map[uint64][]int64{0: {1, 2}}},
+	})
+	require.NoError(t, err)
+
+	var paramUpdate TxProtocolParamUpdate
+
+	require.NoError(t, cbor.Unmarshal(paramsBytes, &paramUpdate))
+
+	require.NotNil(t, paramUpdate.TxFeeFixed)
+	assert.Equal(t, txFeeFixed, *paramUpdate.TxFeeFixed)
+	assert.Nil(t, paramUpdate.TxFeePerByte)
+	require.Len(t, paramUpdate.Other, 2)
+	assert.Equal(t, mustDecodeHex(t, "d81e82030a"), []byte(paramUpdate.Other[9]))
+	assert.Contains(t, paramUpdate.Other, uint64(18))
+
+	encoded, err := cbor.Marshal(paramUpdate)
+	require.NoError(t, err)
+
+	assert.Equal(t, paramsBytes, encoded)
+
+	actionBytes, err := cbor.Marshal(TxGovAction{Type: ParameterChangeGovAction, ParamUpdate: &paramUpdate})
+	require.NoError(t, err)
+
+	action, err := decodeTxGovAction(actionBytes)
+	require.NoError(t, err)
+
+	assert.Equal(t, &paramUpdate, action.ParamUpdate)
+
+	_, err = cbor.Marshal(TxProtocolParamUpdate{})
+	require.ErrorContains(t, err, "empty protocol parameters update")
+
+	_, err = cbor.Marshal(TxProtocolParamUpdate{
+		TxFeeFixed: &txFeeFixed,
+		Other:      map[uint64]cbor.RawMessage{1: paramUpdate.Other[9]},
+	})
+	require.ErrorContains(t, err, "protocol parameter 1 is set both as field and in other")
+}
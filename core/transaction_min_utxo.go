@@ -0,0 +1,39 @@
+package core
+
+import (
+	"github.com/fxamacker/cbor/v2"
+)
+
+const (
+	// minUtxoOverheadSize is constant overhead (in bytes) of every utxo entry (babbage era onwards)
+	minUtxoOverheadSize = 160
+	// minUtxoMaxIterations limits recalculations, amount size can change only few times
+	minUtxoMaxIterations = 5
+)
+
+// CalculateMinUtxoForOutput calculates minimal lovelace amount for the output:
+// (160 + size of serialized output) * utxoCostPerByte
+// the amount of the output is replaced with calculated one until it does not change,
+// so the result does not depend on the initial amount of the output
+func CalculateMinUtxoForOutput(protocolParams ProtocolParameters, output TxOutput) (uint64, error) {
+	for i := 0; i < minUtxoMaxIterations; i++ {
+		outputCbor, err := getTxOutputCbor(output)
+		if err != nil {
+			return 0, err
+		}
+
+		outputBytes, err := cbor.Marshal(outputCbor)
+		if err != nil {
+			return 0, err
+		}
+
+		minUtxo := (minUtxoOverheadSize + uint64(len(outputBytes))) * protocolParams.UtxoCostPerByte
+		if minUtxo == output.Amount {
+			break
+		}
+
+		output.Amount = minUtxo
+	}
+
+	return output.Amount, nil
+}
@@ -0,0 +1,39 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculateMinUtxoForOutput(t *testing.T) {
+	t.Parallel()
+
+	protocolParams := ProtocolParameters{UtxoCostPerByte: 4310}
+	output := NewTxOutput("addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u", 0)
+
+	// (160 + 37) * 4310
+	minUtxo, err := CalculateMinUtxoForOutput(protocolParams, output)
+	require.NoError(t, err)
+	require.Equal(t, uint64(849070), minUtxo)
+	require.Equal(t, uint64(0), output.Amount)
+
+	output.Tokens = []TokenAmount{
+		NewTokenAmount(NewToken("29f8873beb52e126f207a2dfd50f7cff556806b5b4cba9834a7b26a8", "Route3"), 3),
+		NewTokenAmount(NewToken("29f8873beb52e126f207a2dfd50f7cff556806b5b4cba9834a7b26a8", "Route345"), 0),
+	}
+
+	// (160 + 78) * 4310, zero amount tokens are not part of the output
+	minUtxoTokens, err := CalculateMinUtxoForOutput(protocolParams, output)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1025780), minUtxoTokens)
+
+	output.Tokens[1].Amount = 5
+
+	minUtxoTokens, err = CalculateMinUtxoForOutput(protocolParams, output)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1025780+4310*10), minUtxoTokens)
+
+	_, err = CalculateMinUtxoForOutput(protocolParams, NewTxOutput("invalid", 0))
+	require.Error(t, err)
+}
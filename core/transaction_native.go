@@ -0,0 +1,722 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"golang.org/x/crypto/blake2b"
+)
+
+const TxHashSize = 32
+
+// transaction body keys
+const (
+	txBodyInputsKey           = 0
+	txBodyOutputsKey          = 1
+	txBodyFeeKey              = 2
+	txBodyTimeToLiveKey       = 3
+	txBodyAuxDataHashKey      = 7
+	txBodyValidityStartKey    = 8
+	txBodyMintKey             = 9
+	txBodyScriptDataHashKey   = 11
+	txBodyCollateralKey       = 13
+	txBodyRequiredSignersKey  = 14
+	txBodyCollateralReturnKey = 16
+	txBodyTotalCollateralKey  = 17
+	txBodyReferenceInputsKey  = 18
+
+	txOutputAddressKey   = 0
+	txOutputValueKey     = 1
+	txOutputDatumKey     = 2
+	txOutputScriptRefKey = 3
+	txOutputDatumHash    = 0
+	txOutputDatumInline  = 1
+
+	txWitnessVKeyWitnesses   = 0
+	txWitnessNativeScripts   = 1
+	txWitnessPlutusV1Scripts = 3
+	txWitnessPlutusData      = 4
+	txWitnessRedeemers       = 5
+	txWitnessPlutusV2Scripts = 6
+	txWitnessPlutusV3Scripts = 7
+)
+
+// buildRawTxNative builds transaction without cardano-cli
+// returns cbor of the unwitnessed transaction and its hash
+func (b *TxBuilder) buildRawTxNative(fee uint64) ([]byte, string, error) {
+	protocolParams, err := b.getProtocolParameters()
+	if err != nil {
+		return nil, "", err
+	}
+
+	isConway := protocolParams.IsConwayEra()
+
+	inputs, err := getTxInputsCbor(b.getInputs(), isConway)
+	if err != nil {
+		return nil, "", err
+	}
+
+	outputs := make([]interface{}, len(b.outputs))
+
+	for i, output := range b.outputs {
+		outputs[i], err = getTxOutputCbor(output)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	body := cborOrderedMap{
+		{Key: txBodyInputsKey, Value: inputs},
+		{Key: txBodyOutputsKey, Value: outputs},
+		{Key: txBodyFeeKey, Value: fee},
+	}
+
+	if b.timeToLive > 0 {
+		body = append(body, cborKeyValue{Key: txBodyTimeToLiveKey, Value: b.timeToLive})
+	}
+
+	if len(b.certificates) > 0 {
+		certificates, err := b.getCertificatesCbor(isConway)
+		if err != nil {
+			return nil, "", err
+		}
+
+		body = append(body, cborKeyValue{Key: txBodyCertificatesKey, Value: certificates})
+	}
+
+	if len(b.withdrawals) > 0 {
+		withdrawals, err := b.getWithdrawalsCbor()
+		if err != nil {
+			return nil, "", err
+		}
+
+		body = append(body, cborKeyValue{Key: txBodyWithdrawalsKey, Value: withdrawals})
+	}
+
+	var auxData interface{}
+
+	if b.metadata != nil {
+		auxDataBytes, err := getAuxiliaryDataCbor(b.metadata)
+		if err != nil {
+			return nil, "", err
+		}
+
+		auxDataHash := blake2b.Sum256(auxDataBytes)
+		auxData = cbor.RawMessage(auxDataBytes)
+		body = append(body, cborKeyValue{Key: txBodyAuxDataHashKey, Value: auxDataHash[:]})
+	}
+
+	if b.validityStart > 0 {
+		body = append(body, cborKeyValue{Key: txBodyValidityStartKey, Value: b.validityStart})
+	}
+
+	if len(b.mints.tokens) > 0 {
+		mint, err := getMintCbor(b.mints.tokens)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if len(mint) > 0 {
+			body = append(body, cborKeyValue{Key: txBodyMintKey, Value: mint})
+		}
+	}
+
+	witnessSet, err := b.getWitnessSetCbor(isConway)
+	if err != nil {
+		return nil, "", err
+	}
+
+	plutusWitnessSet, scriptDataHash, err := b.getPlutusWitnessSetCbor(protocolParams, isConway)
+	if err != nil {
+		return nil, "", err
+	}
+
+	witnessSet = append(witnessSet, plutusWitnessSet...)
+
+	if scriptDataHash != nil {
+		body = append(body, cborKeyValue{Key: txBodyScriptDataHashKey, Value: scriptDataHash})
+	}
+
+	if len(b.collateralInputs) > 0 {
+		collateralInputs, err := getTxInputsCbor(b.collateralInputs, isConway)
+		if err != nil {
+			return nil, "", err
+		}
+
+		body = append(body, cborKeyValue{Key: txBodyCollateralKey, Value: collateralInputs})
+	}
+
+	if requiredSigners := b.getRequiredSigners(); len(requiredSigners) > 0 {
+		requiredSignersCbor, err := getRequiredSignersCbor(requiredSigners, isConway)
+		if err != nil {
+			return nil, "", err
+		}
+
+		body = append(body, cborKeyValue{Key: txBodyRequiredSignersKey, Value: requiredSignersCbor})
+	}
+
+	if b.collateralReturn != nil {
+		collateralReturn, err := getTxOutputCbor(*b.collateralReturn)
+		if err != nil {
+			return nil, "", err
+		}
+
+		body = append(body, cborKeyValue{Key: txBodyCollateralReturnKey, Value: collateralReturn})
+	}
+
+	if b.totalCollateral > 0 {
+		body = append(body, cborKeyValue{Key: txBodyTotalCollateralKey, Value: b.totalCollateral})
+	}
+
+	if referenceInputs := b.getReferenceInputs(); len(referenceInputs) > 0 {
+		referenceInputsCbor, err := getTxInputsCbor(referenceInputs, isConway)
+		if err != nil {
+			return nil, "", err
+		}
+
+		body = append(body, cborKeyValue{Key: txBodyReferenceInputsKey, Value: referenceInputsCbor})
+	}
+
+	if (len(b.votes) > 0 || len(b.proposals) > 0) && !isConway {
+		return nil, "", fmt.Errorf("votes and proposals are not supported in %s era", protocolParams.GetEraName())
+	}
+
+	if len(b.votes) > 0 {
+		votingProcedures, err := getVotingProceduresCbor(b.getVotes())
+		if err != nil {
+			return nil, "", err
+		}
+
+		body = append(body, cborKeyValue{Key: txBodyVotingProceduresKey, Value: votingProcedures})
+	}
+
+	if len(b.proposals) > 0 {
+		body = append(body, cborKeyValue{Key: txBodyProposalProceduresKey, Value: newCborSet(b.proposals, isConway)})
+	}
+
+	bodyBytes, err := cbor.Marshal(body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	txRaw, err := cbor.Marshal([]interface{}{
+		cbor.RawMessage(bodyBytes), witnessSet, true, auxData,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	txHash := blake2b.Sum256(bodyBytes)
+
+	return txRaw, hex.EncodeToString(txHash[:]), nil
+}
+
+// getWitnessSetCbor returns witness set with all the native scripts required by inputs and mints
+func (b *TxBuilder) getWitnessSetCbor(isConway bool) (cborOrderedMap, error) {
+	scripts, err := b.getNativeScripts()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(scripts) == 0 {
+		return cborOrderedMap{}, nil
+	}
+
+	return cborOrderedMap{
+		{Key: txWitnessNativeScripts, Value: newCborSet(scripts, isConway)},
+	}, nil
+}
+
+// getNativeScripts returns distinct native scripts (cbor) of inputs, mints, certificates, withdrawals and votes ordered by script hash
+func (b *TxBuilder) getNativeScripts() ([]cbor.RawMessage, error) {
+	var scripts []cbor.RawMessage
+
+	for _, inp := range b.inputs {
+		if inp.policyScript != nil && inp.scriptRefInput == nil {
+			scriptBytes, err := getPolicyScriptCbor(inp.policyScript)
+			if err != nil {
+				return nil, err
+			}
+
+			scripts = append(scripts, scriptBytes)
+		}
+	}
+
+	for _, policyScript := range b.mints.policyScripts {
+		scriptBytes, err := getPolicyScriptCbor(policyScript)
+		if err != nil {
+			return nil, err
+		}
+
+		scripts = append(scripts, scriptBytes)
+	}
+
+	for _, cert := range b.certificates {
+		if cert.policyScript != nil {
+			scriptBytes, err := getPolicyScriptCbor(cert.policyScript)
+			if err != nil {
+				return nil, err
+			}
+
+			scripts = append(scripts, scriptBytes)
+		}
+	}
+
+	for _, w := range b.withdrawals {
+		if w.policyScript != nil {
+			scriptBytes, err := getPolicyScriptCbor(w.policyScript)
+			if err != nil {
+				return nil, err
+			}
+
+			scripts = append(scripts, scriptBytes)
+		}
+	}
+
+	for _, v := range b.votes {
+		if v.policyScript != nil {
+			scriptBytes, err := getPolicyScriptCbor(v.policyScript)
+			if err != nil {
+				return nil, err
+			}
+
+			scripts = append(scripts, scriptBytes)
+		}
+	}
+
+	return getSortedNativeScripts(scripts)
+}
+
+// assembleTxWitnessesNative adds vkey witnesses and native scripts of the builder to the witness set of transaction
+func (b *TxBuilder) assembleTxWitnessesNative(txRaw []byte, witnesses [][]byte) ([]byte, error) {
+	var tx []cbor.RawMessage
+
+	if err := cbor.Unmarshal(txRaw, &tx); err != nil {
+		return nil, err
+	}
+
+	if len(tx) != 4 {
+		return nil, fmt.Errorf("%w: expected 4 elements, got %d", ErrInvalidTransaction, len(tx))
+	}
+
+	witnessSet := map[uint64]cbor.RawMessage{}
+
+	if err := cbor.Unmarshal(tx[1], &witnessSet); err != nil {
+		return nil, err
+	}
+
+	isConway, err := isTxBodyWithTaggedSets(tx[0])
+	if err != nil {
+		return nil, err
+	}
+
+	vkeyWitnesses, _, err := decodeCborSet(witnessSet[txWitnessVKeyWitnesses])
+	if err != nil {
+		return nil, err
+	}
+
+	for _, witness := range witnesses {
+		vkeyWitnesses = append(vkeyWitnesses, witness)
+	}
+
+	vkeyWitnesses, err = getSortedVKeyWitnesses(vkeyWitnesses)
+	if err != nil {
+		return nil, err
+	}
+
+	nativeScripts, _, err := decodeCborSet(witnessSet[txWitnessNativeScripts])
+	if err != nil {
+		return nil, err
+	}
+
+	builderNativeScripts, err := b.getNativeScripts()
+	if err != nil {
+		return nil, err
+	}
+
+	nativeScripts, err = getSortedNativeScripts(append(nativeScripts, builderNativeScripts...))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vkeyWitnesses) > 0 {
+		witnessSet[txWitnessVKeyWitnesses], err = cbor.Marshal(newCborSet(vkeyWitnesses, isConway))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(nativeScripts) > 0 {
+		witnessSet[txWitnessNativeScripts], err = cbor.Marshal(newCborSet(nativeScripts, isConway))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	keys := make([]uint64, 0, len(witnessSet))
+	for key := range witnessSet {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+
+	newWitnessSet := make(cborOrderedMap, len(keys))
+	for i, key := range keys {
+		newWitnessSet[i] = cborKeyValue{Key: key, Value: witnessSet[key]}
+	}
+
+	return cbor.Marshal([]interface{}{tx[0], newWitnessSet, tx[2], tx[3]})
+}
+
+// getSortedVKeyWitnesses returns distinct vkey witnesses ordered by key hash (and signature)
+func getSortedVKeyWitnesses(witnesses []cbor.RawMessage) ([]cbor.RawMessage, error) {
+	type vkeyWitnessInfo struct {
+		raw       cbor.RawMessage
+		keyHash   []byte
+		signature []byte
+	}
+
+	items := make([]vkeyWitnessInfo, 0, len(witnesses))
+
+	for _, witness := range witnesses {
+		signature, vkey, err := TxWitnessRaw(witness).GetSignatureAndVKey()
+		if err != nil {
+			return nil, err
+		}
+
+		keyHash, err := GetKeyHashBytes(vkey)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, vkeyWitnessInfo{raw: witness, keyHash: keyHash, signature: signature})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if cmp := bytes.Compare(items[i].keyHash, items[j].keyHash); cmp != 0 {
+			return cmp < 0
+		}
+
+		return bytes.Compare(items[i].signature, items[j].signature) < 0
+	})
+
+	result := make([]cbor.RawMessage, 0, len(items))
+
+	for i, x := range items {
+		if i > 0 && bytes.Equal(items[i-1].keyHash, x.keyHash) && bytes.Equal(items[i-1].signature, x.signature) {
+			continue
+		}
+
+		result = append(result, x.raw)
+	}
+
+	return result, nil
+}
+
+// getSortedNativeScripts returns distinct native scripts ordered by script hash
+func getSortedNativeScripts(scripts []cbor.RawMessage) ([]cbor.RawMessage, error) {
+	type scriptWithHash struct {
+		script cbor.RawMessage
+		hash   []byte
+	}
+
+	items := make([]scriptWithHash, 0, len(scripts))
+
+	for _, script := range scripts {
+		scriptHash, err := GetKeyHashBytes(append([]byte{nativeScriptHashPrefix}, script...))
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, scriptWithHash{script: script, hash: scriptHash})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return bytes.Compare(items[i].hash, items[j].hash) < 0
+	})
+
+	result := make([]cbor.RawMessage, 0, len(items))
+
+	for i, x := range items {
+		if i > 0 && bytes.Equal(items[i-1].hash, x.hash) {
+			continue
+		}
+
+		result = append(result, x.script)
+	}
+
+	return result, nil
+}
+
+// isTxBodyWithTaggedSets returns true if inputs of the transaction body are tagged as set (conway era)
+func isTxBodyWithTaggedSets(bodyRaw []byte) (bool, error) {
+	var body map[uint64]cbor.RawMessage
+
+	if err := cbor.Unmarshal(bodyRaw, &body); err != nil {
+		return false, err
+	}
+
+	_, isTagged, err := decodeCborSet(body[txBodyInputsKey])
+
+	return isTagged, err
+}
+
+func getTxInputsCbor(inputs []TxInput, isConway bool) (cborSet[[]interface{}], error) {
+	sortedInputs, err := getSortedTxInputs(inputs)
+	if err != nil {
+		return cborSet[[]interface{}]{}, err
+	}
+
+	result := make([][]interface{}, len(sortedInputs))
+
+	for i, x := range sortedInputs {
+		hash, err := hex.DecodeString(x.Hash)
+		if err != nil {
+			return cborSet[[]interface{}]{}, err
+		}
+
+		result[i] = []interface{}{hash, x.Index}
+	}
+
+	return newCborSet(result, isConway), nil
+}
+
+// getSortedTxInputs returns distinct inputs ordered by hash and index (order of the inputs in transaction body)
+func getSortedTxInputs(inputs []TxInput) ([]TxInput, error) {
+	items := make([]TxInput, 0, len(inputs))
+
+	for _, inp := range inputs {
+		hash, err := hex.DecodeString(inp.Hash)
+		if err != nil {
+			return nil, err
+		} else if len(hash) != TxHashSize {
+			return nil, fmt.Errorf("invalid input hash: %s", inp.Hash)
+		}
+
+		items = append(items, NewTxInput(hex.EncodeToString(hash), inp.Index))
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return compareTxInputs(items[i], items[j]) < 0
+	})
+
+	result := make([]TxInput, 0, len(items))
+
+	for i, x := range items {
+		// skip duplicates
+		if i > 0 && items[i-1] == x {
+			continue
+		}
+
+		result = append(result, x)
+	}
+
+	return result, nil
+}
+
+// compareTxInputs compares inputs with lower case hashes
+func compareTxInputs(a, b TxInput) int {
+	if cmp := strings.Compare(a.Hash, b.Hash); cmp != 0 {
+		return cmp
+	}
+
+	switch {
+	case a.Index < b.Index:
+		return -1
+	case a.Index > b.Index:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// getTxOutputCbor returns output in legacy (array) format
+// or in post alonzo (map) format if output has datum or reference script
+func getTxOutputCbor(output TxOutput) (interface{}, error) {
+	addr, err := NewCardanoAddressFromString(output.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{} = output.Amount
+
+	if len(output.Tokens) > 0 {
+		multiAsset, err := getMultiAssetCbor(output.Tokens)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(multiAsset) > 0 {
+			value = []interface{}{output.Amount, multiAsset}
+		}
+	}
+
+	if !output.IsPostAlonzo() {
+		return []interface{}{addr.GetBytes(), value}, nil
+	}
+
+	result := cborOrderedMap{
+		{Key: txOutputAddressKey, Value: addr.GetBytes()},
+		{Key: txOutputValueKey, Value: value},
+	}
+
+	if output.Datum != nil {
+		result = append(result, cborKeyValue{
+			Key:   txOutputDatumKey,
+			Value: []interface{}{txOutputDatumInline, cbor.Tag{Number: cborEncodedDataTag, Content: output.Datum}},
+		})
+	} else if output.DatumHash != "" {
+		datumHash, err := hex.DecodeString(output.DatumHash)
+		if err != nil {
+			return nil, err
+		} else if len(datumHash) != TxHashSize {
+			return nil, fmt.Errorf("invalid datum hash: %s", output.DatumHash)
+		}
+
+		result = append(result, cborKeyValue{
+			Key:   txOutputDatumKey,
+			Value: []interface{}{txOutputDatumHash, datumHash},
+		})
+	}
+
+	if output.ReferenceScript != nil {
+		scriptBytes, err := output.ReferenceScript.MarshalCBOR()
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, cborKeyValue{
+			Key:   txOutputScriptRefKey,
+			Value: cbor.Tag{Number: cborEncodedDataTag, Content: scriptBytes},
+		})
+	}
+
+	return result, nil
+}
+
+// getMultiAssetCbor returns multi asset map ordered by policy id and canonically ordered asset names
+// tokens with zero amounts are skipped and same tokens are summed
+func getMultiAssetCbor(tokens []TokenAmount) (cborOrderedMap, error) {
+	assetTokens, amounts := make([]Token, len(tokens)), make([]uint64, len(tokens))
+	for i, token := range tokens {
+		assetTokens[i], amounts[i] = token.Token, token.Amount
+	}
+
+	return getMultiAssetCborFromAmounts(assetTokens, amounts)
+}
+
+// getMintCbor returns multi asset with signed amounts (negative amount is burned)
+func getMintCbor(tokens []MintTokenAmount) (cborOrderedMap, error) {
+	assetTokens, amounts := make([]Token, len(tokens)), make([]int64, len(tokens))
+	for i, token := range tokens {
+		assetTokens[i], amounts[i] = token.Token, token.Amount
+	}
+
+	return getMultiAssetCborFromAmounts(assetTokens, amounts)
+}
+
+// getMultiAssetCborFromAmounts returns policy id => (asset name => amount) map. Amounts of the same token are summed
+// and tokens which amounts sum to zero are omitted
+func getMultiAssetCborFromAmounts[T uint64 | int64](tokens []Token, amounts []T) (cborOrderedMap, error) {
+	type assetInfo struct {
+		name   []byte
+		amount T
+	}
+
+	policies := map[string][]assetInfo{}
+
+	for i, token := range tokens {
+		if amounts[i] == 0 {
+			continue
+		}
+
+		policyIDBytes, err := hex.DecodeString(token.PolicyID)
+		if err != nil || len(policyIDBytes) != KeyHashSize {
+			return nil, fmt.Errorf("invalid policy id: %s", token.PolicyID)
+		}
+
+		policyID := hex.EncodeToString(policyIDBytes)
+		assets, found := policies[policyID], false
+
+		for j, asset := range assets {
+			if string(asset.name) == token.Name {
+				assets[j].amount += amounts[i]
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			policies[policyID] = append(assets, assetInfo{name: []byte(token.Name), amount: amounts[i]})
+		}
+	}
+
+	policyIDs := make([]string, 0, len(policies))
+	for policyID, assets := range policies {
+		nonZeroAssets := assets[:0]
+
+		for _, asset := range assets {
+			if asset.amount != 0 {
+				nonZeroAssets = append(nonZeroAssets, asset)
+			}
+		}
+
+		if len(nonZeroAssets) == 0 {
+			delete(policies, policyID)
+
+			continue
+		}
+
+		policies[policyID] = nonZeroAssets
+		policyIDs = append(policyIDs, policyID)
+	}
+
+	sort.Strings(policyIDs)
+
+	result := make(cborOrderedMap, len(policyIDs))
+
+	for i, policyID := range policyIDs {
+		assets := policies[policyID]
+
+		sort.Slice(assets, func(i, j int) bool {
+			if len(assets[i].name) != len(assets[j].name) {
+				return len(assets[i].name) < len(assets[j].name)
+			}
+
+			return bytes.Compare(assets[i].name, assets[j].name) < 0
+		})
+
+		assetsMap := make(cborOrderedMap, len(assets))
+		for j, asset := range assets {
+			assetsMap[j] = cborKeyValue{Key: asset.name, Value: asset.amount}
+		}
+
+		policyIDBytes, _ := hex.DecodeString(policyID)
+		result[i] = cborKeyValue{Key: policyIDBytes, Value: assetsMap}
+	}
+
+	return result, nil
+}
+
+// getRequiredSignersCbor returns set of key hashes
+func getRequiredSignersCbor(keyHashes []string, isConway bool) (interface{}, error) {
+	result := make([][]byte, len(keyHashes))
+
+	for i, keyHash := range keyHashes {
+		keyHashBytes, err := hex.DecodeString(keyHash)
+		if err != nil || len(keyHashBytes) != KeyHashSize {
+			return nil, fmt.Errorf("invalid required signer key hash: %s", keyHash)
+		}
+
+		result[i] = keyHashBytes
+	}
+
+	return newCborSet(result, isConway), nil
+}
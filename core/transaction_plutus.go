@@ -0,0 +1,680 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"golang.org/x/crypto/blake2b"
+)
+
+// PlutusScriptVersion is plutus language version
+type PlutusScriptVersion uint8
+
+const (
+	PlutusV1 PlutusScriptVersion = 1
+	PlutusV2 PlutusScriptVersion = 2
+	PlutusV3 PlutusScriptVersion = 3
+)
+
+const plutusScriptJSONTypePrefix = "PlutusScript"
+
+// String returns language name as used in protocol parameters cost models (PlutusV1, PlutusV2, ...)
+func (v PlutusScriptVersion) String() string {
+	return fmt.Sprintf("PlutusV%d", v)
+}
+
+func (v PlutusScriptVersion) getWitnessSetKey() (uint64, error) {
+	switch v {
+	case PlutusV1:
+		return txWitnessPlutusV1Scripts, nil
+	case PlutusV2:
+		return txWitnessPlutusV2Scripts, nil
+	case PlutusV3:
+		return txWitnessPlutusV3Scripts, nil
+	default:
+		return 0, fmt.Errorf("unsupported plutus script version: %d", v)
+	}
+}
+
+// PlutusScript is plutus script of a language version
+type PlutusScript struct {
+	Version PlutusScriptVersion `json:"version"`
+	// Script is serialized script as it is in the witness set (cardano-cli cborHex without outer bytes header)
+	Script []byte `json:"script"`
+}
+
+func NewPlutusScript(version PlutusScriptVersion, script []byte) PlutusScript {
+	return PlutusScript{
+		Version: version,
+		Script:  script,
+	}
+}
+
+// NewPlutusScriptFromJSON creates plutus script from cardano-cli text envelope (PlutusScriptV1, V2 or V3)
+func NewPlutusScriptFromJSON(content []byte) (PlutusScript, error) {
+	var envelope struct {
+		Type    string `json:"type"`
+		CborHex string `json:"cborHex"`
+	}
+
+	if err := json.Unmarshal(content, &envelope); err != nil {
+		return PlutusScript{}, err
+	}
+
+	version, err := strconv.ParseUint(strings.TrimPrefix(envelope.Type, plutusScriptJSONTypePrefix+"V"), 10, 8)
+	if err != nil || !strings.HasPrefix(envelope.Type, plutusScriptJSONTypePrefix) {
+		return PlutusScript{}, fmt.Errorf("invalid plutus script type: %s", envelope.Type)
+	}
+
+	cborBytes, err := hex.DecodeString(envelope.CborHex)
+	if err != nil {
+		return PlutusScript{}, err
+	}
+
+	var script []byte
+
+	if err := cbor.Unmarshal(cborBytes, &script); err != nil {
+		return PlutusScript{}, err
+	}
+
+	ps := NewPlutusScript(PlutusScriptVersion(version), script)
+
+	if _, err := ps.Version.getWitnessSetKey(); err != nil {
+		return PlutusScript{}, err
+	}
+
+	return ps, nil
+}
+
+// ToJSON returns cardano-cli text envelope of the script
+func (ps PlutusScript) ToJSON() ([]byte, error) {
+	cborBytes, err := cbor.Marshal(ps.Script)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(map[string]string{
+		"type":        fmt.Sprintf("%sV%d", plutusScriptJSONTypePrefix, ps.Version),
+		"description": "",
+		"cborHex":     hex.EncodeToString(cborBytes),
+	}, "", "    ")
+}
+
+// Hash returns script hash (blake2b-224 of language version prefixed script)
+func (ps PlutusScript) Hash() (string, error) {
+	return GetKeyHash(append([]byte{byte(ps.Version)}, ps.Script...))
+}
+
+// TxReferenceScript is native or plutus script attached to an output
+type TxReferenceScript struct {
+	NativeScript *PolicyScript `json:"native,omitempty"`
+	PlutusScript *PlutusScript `json:"plutus,omitempty"`
+}
+
+func NewTxReferenceScriptNative(script PolicyScript) TxReferenceScript {
+	return TxReferenceScript{
+		NativeScript: &script,
+	}
+}
+
+func NewTxReferenceScriptPlutus(script PlutusScript) TxReferenceScript {
+	return TxReferenceScript{
+		PlutusScript: &script,
+	}
+}
+
+// ToJSON returns cardano-cli script file content
+func (rs TxReferenceScript) ToJSON() ([]byte, error) {
+	if rs.PlutusScript != nil {
+		return rs.PlutusScript.ToJSON()
+	}
+
+	if rs.NativeScript != nil {
+		return rs.NativeScript.GetPolicyScriptJSON()
+	}
+
+	return nil, errors.New("reference script not set")
+}
+
+// MarshalCBOR encodes reference script as [0, native_script] or [plutus version, script bytes]
+func (rs TxReferenceScript) MarshalCBOR() ([]byte, error) {
+	if rs.PlutusScript != nil {
+		if _, err := rs.PlutusScript.Version.getWitnessSetKey(); err != nil {
+			return nil, err
+		}
+
+		return cbor.Marshal([]interface{}{uint64(rs.PlutusScript.Version), rs.PlutusScript.Script})
+	}
+
+	if rs.NativeScript != nil {
+		scriptBytes, err := rs.NativeScript.MarshalCBOR()
+		if err != nil {
+			return nil, err
+		}
+
+		return cbor.Marshal([]interface{}{0, cbor.RawMessage(scriptBytes)})
+	}
+
+	return nil, errors.New("reference script not set")
+}
+
+// UnmarshalCBOR decodes reference script
+func (rs *TxReferenceScript) UnmarshalCBOR(data []byte) error {
+	var fields []cbor.RawMessage
+
+	if err := cbor.Unmarshal(data, &fields); err != nil {
+		return err
+	} else if len(fields) != 2 {
+		return fmt.Errorf("invalid reference script: expected 2 elements, got %d", len(fields))
+	}
+
+	var version uint64
+
+	if err := cbor.Unmarshal(fields[0], &version); err != nil {
+		return err
+	}
+
+	if version == 0 {
+		var script PolicyScript
+
+		if err := script.UnmarshalCBOR(fields[1]); err != nil {
+			return err
+		}
+
+		*rs = NewTxReferenceScriptNative(script)
+
+		return nil
+	}
+
+	var script []byte
+
+	if err := cbor.Unmarshal(fields[1], &script); err != nil {
+		return err
+	}
+
+	plutusScript := NewPlutusScript(PlutusScriptVersion(version), script)
+
+	if _, err := plutusScript.Version.getWitnessSetKey(); err != nil {
+		return err
+	}
+
+	*rs = NewTxReferenceScriptPlutus(plutusScript)
+
+	return nil
+}
+
+// TxExUnits are execution units (memory and cpu steps) of a redeemer
+type TxExUnits struct {
+	Memory uint64 `json:"memory"`
+	Steps  uint64 `json:"steps"`
+}
+
+func NewTxExUnits(memory, steps uint64) TxExUnits {
+	return TxExUnits{
+		Memory: memory,
+		Steps:  steps,
+	}
+}
+
+func (u TxExUnits) getCbor() []interface{} {
+	return []interface{}{u.Memory, u.Steps}
+}
+
+// TxRedeemerTag is purpose of the redeemer
+type TxRedeemerTag uint64
+
+const (
+	RedeemerTagSpend   TxRedeemerTag = 0
+	RedeemerTagMint    TxRedeemerTag = 1
+	RedeemerTagCert    TxRedeemerTag = 2
+	RedeemerTagReward  TxRedeemerTag = 3
+	RedeemerTagVoting  TxRedeemerTag = 4
+	RedeemerTagPropose TxRedeemerTag = 5
+)
+
+// TxRedeemerPointer points to the item (input, mint, ...) for which the script is executed
+type TxRedeemerPointer struct {
+	Tag   TxRedeemerTag `json:"tag"`
+	Index uint32        `json:"index"`
+}
+
+// ExUnitsBudgetError is returned if execution units of the transaction exceed max transaction execution units
+type ExUnitsBudgetError struct {
+	ExUnits    TxExUnits
+	MaxExUnits TxExUnits
+}
+
+func (e *ExUnitsBudgetError) Error() string {
+	return fmt.Sprintf("execution units budget exceeded: memory %d/%d, steps %d/%d",
+		e.ExUnits.Memory, e.MaxExUnits.Memory, e.ExUnits.Steps, e.MaxExUnits.Steps)
+}
+
+// TxRedeemer is redeemer (plutus data cbor) for the script executed for item (input, mint, ...) at Index
+type TxRedeemer struct {
+	Tag     TxRedeemerTag `json:"tag"`
+	Index   uint32        `json:"index"`
+	Data    []byte        `json:"data"`
+	ExUnits TxExUnits     `json:"exUnits"`
+}
+
+// TxPlutusScriptWitness is everything that is needed to spend plutus script locked output
+type TxPlutusScriptWitness struct {
+	// Script is spending script. If the script is referenced, its bytes are only used for reference scripts fee
+	Script PlutusScript
+	// ReferenceInput is utxo which holds the script as reference script. Nil if script is part of the witness set
+	ReferenceInput *TxInput
+	// Datum is plutus data cbor. Nil if output has inline datum
+	Datum []byte
+	// Redeemer is plutus data cbor
+	Redeemer []byte
+	ExUnits  TxExUnits
+}
+
+// Apply adds cardano-cli build-raw arguments for the plutus input at index indx
+func (w TxPlutusScriptWitness) Apply(args *[]string, basePath string, indx int) error {
+	// reference script flags are prefixed with --spending-reference-tx-in instead of --tx-in
+	flagPrefix := "--tx-in"
+
+	if w.ReferenceInput != nil {
+		flagPrefix = "--spending-reference-tx-in"
+
+		*args = append(*args,
+			"--spending-tx-in-reference", w.ReferenceInput.String(),
+			fmt.Sprintf("--spending-plutus-script-v%d", w.Script.Version))
+	} else {
+		scriptJSON, err := w.Script.ToJSON()
+		if err != nil {
+			return err
+		}
+
+		scriptFilePath := filepath.Join(basePath, fmt.Sprintf("plutus_%d.json", indx))
+		if err := os.WriteFile(scriptFilePath, scriptJSON, FilePermission); err != nil {
+			return err
+		}
+
+		*args = append(*args, "--tx-in-script-file", scriptFilePath)
+	}
+
+	redeemerFilePath := filepath.Join(basePath, fmt.Sprintf("redeemer_%d.cbor", indx))
+	if err := os.WriteFile(redeemerFilePath, w.Redeemer, FilePermission); err != nil {
+		return err
+	}
+
+	if w.Datum == nil {
+		*args = append(*args, flagPrefix+"-inline-datum-present")
+	} else {
+		datumFilePath := filepath.Join(basePath, fmt.Sprintf("datum_%d.cbor", indx))
+		if err := os.WriteFile(datumFilePath, w.Datum, FilePermission); err != nil {
+			return err
+		}
+
+		*args = append(*args, flagPrefix+"-datum-cbor-file", datumFilePath)
+	}
+
+	*args = append(*args,
+		flagPrefix+"-redeemer-cbor-file", redeemerFilePath,
+		flagPrefix+"-execution-units", fmt.Sprintf("(%d, %d)", w.ExUnits.Steps, w.ExUnits.Memory))
+
+	return nil
+}
+
+// GetExecutionUnitsFee calculates fee for execution units: ceil(priceMemory * memory + priceSteps * steps)
+func GetExecutionUnitsFee(protocolParams ProtocolParameters, exUnits TxExUnits) uint64 {
+	if exUnits.Memory == 0 && exUnits.Steps == 0 {
+		return 0
+	}
+
+	priceMemory, okMemory := new(big.Rat).SetString(
+		strconv.FormatFloat(protocolParams.ExecutionUnitPrices.PriceMemory, 'f', -1, 64))
+	priceSteps, okSteps := new(big.Rat).SetString(
+		strconv.FormatFloat(protocolParams.ExecutionUnitPrices.PriceSteps, 'f', -1, 64))
+
+	if !okMemory || !okSteps {
+		return 0
+	}
+
+	fee := new(big.Rat).Mul(priceMemory, new(big.Rat).SetUint64(exUnits.Memory))
+	fee.Add(fee, new(big.Rat).Mul(priceSteps, new(big.Rat).SetUint64(exUnits.Steps)))
+
+	return ceilRat(fee)
+}
+
+// CalculateCollateral calculates minimal total collateral for the fee: ceil(fee * collateralPercentage / 100)
+func CalculateCollateral(protocolParams ProtocolParameters, fee uint64) uint64 {
+	return ceilRat(new(big.Rat).SetFrac(
+		new(big.Int).Mul(new(big.Int).SetUint64(fee), new(big.Int).SetUint64(protocolParams.CollateralPercentage)),
+		big.NewInt(100)))
+}
+
+func ceilRat(value *big.Rat) uint64 {
+	quo, rem := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+	if rem.Sign() > 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+
+	return quo.Uint64()
+}
+
+// EvaluateExUnits builds transaction, evaluates its plutus scripts and sets execution units of all the redeemers
+// it should be called before the fee calculation
+func (b *TxBuilder) EvaluateExUnits(ctx context.Context, evaluator ITxEvaluator) error {
+	if b.protocolParameters == nil {
+		return errors.New("protocol parameters not set")
+	}
+
+	txRaw, _, err := b.backend.Build(b, b.fee)
+	if err != nil {
+		return err
+	}
+
+	exUnits, err := evaluator.EvaluateTx(ctx, txRaw)
+	if err != nil {
+		return err
+	}
+
+	sortedInputs, err := getSortedTxInputs(b.getInputs())
+	if err != nil {
+		return err
+	}
+
+	for _, inp := range b.inputs {
+		if inp.plutusWitness == nil {
+			continue
+		}
+
+		pointer := TxRedeemerPointer{Tag: RedeemerTagSpend, Index: getTxInputIndex(sortedInputs, inp.txInput)}
+
+		inputExUnits, exists := exUnits[pointer]
+		if !exists {
+			return fmt.Errorf("execution units not evaluated for input %s", inp.txInput)
+		}
+
+		inp.plutusWitness.ExUnits = inputExUnits
+	}
+
+	return b.CheckExUnits()
+}
+
+// CheckExUnits checks that total execution units do not exceed max transaction execution units
+func (b *TxBuilder) CheckExUnits() error {
+	protocolParams, err := b.getProtocolParameters()
+	if err != nil {
+		return err
+	}
+
+	exUnits := b.getTotalExUnits()
+	maxExUnits := NewTxExUnits(protocolParams.MaxTxExecutionUnits.Memory, protocolParams.MaxTxExecutionUnits.Steps)
+
+	if exUnits.Memory > maxExUnits.Memory || exUnits.Steps > maxExUnits.Steps {
+		return &ExUnitsBudgetError{ExUnits: exUnits, MaxExUnits: maxExUnits}
+	}
+
+	return nil
+}
+
+// getTotalExUnits returns sum of execution units of all the redeemers
+func (b *TxBuilder) getTotalExUnits() (total TxExUnits) {
+	for _, inp := range b.inputs {
+		if inp.plutusWitness != nil {
+			total.Memory += inp.plutusWitness.ExUnits.Memory
+			total.Steps += inp.plutusWitness.ExUnits.Steps
+		}
+	}
+
+	return total
+}
+
+// getRedeemers returns redeemers of plutus inputs ordered by their index in the transaction body
+func (b *TxBuilder) getRedeemers() ([]TxRedeemer, error) {
+	sortedInputs, err := getSortedTxInputs(b.getInputs())
+	if err != nil {
+		return nil, err
+	}
+
+	var redeemers []TxRedeemer
+
+	for _, inp := range b.inputs {
+		if inp.plutusWitness == nil {
+			continue
+		}
+
+		redeemers = append(redeemers, TxRedeemer{
+			Tag:     RedeemerTagSpend,
+			Index:   getTxInputIndex(sortedInputs, inp.txInput),
+			Data:    inp.plutusWitness.Redeemer,
+			ExUnits: inp.plutusWitness.ExUnits,
+		})
+	}
+
+	sort.SliceStable(redeemers, func(i, j int) bool {
+		return redeemers[i].Index < redeemers[j].Index
+	})
+
+	// same input added more than once has only one redeemer
+	result := make([]TxRedeemer, 0, len(redeemers))
+
+	for i, x := range redeemers {
+		if i == 0 || redeemers[i-1].Index != x.Index {
+			result = append(result, x)
+		}
+	}
+
+	return result, nil
+}
+
+// getTxInputIndex returns index of the input in the inputs ordered as in the transaction body
+func getTxInputIndex(sortedInputs []TxInput, input TxInput) uint32 {
+	input = NewTxInput(strings.ToLower(input.Hash), input.Index)
+	index := sort.Search(len(sortedInputs), func(i int) bool {
+		return compareTxInputs(sortedInputs[i], input) >= 0
+	})
+
+	return uint32(index) //nolint:gosec
+}
+
+// getPlutusWitnessSetCbor returns plutus scripts, datums and redeemers witness set entries
+// and script data hash (nil if transaction does not have redeemers and datums)
+func (b *TxBuilder) getPlutusWitnessSetCbor(
+	protocolParams ProtocolParameters, isConway bool,
+) (witnessSet cborOrderedMap, scriptDataHash []byte, err error) {
+	var (
+		scripts = map[uint64][]cbor.RawMessage{}
+		datums  []cbor.RawMessage
+	)
+
+	for _, inp := range b.inputs {
+		if inp.plutusWitness == nil {
+			continue
+		}
+
+		key, err := inp.plutusWitness.Script.Version.getWitnessSetKey()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if inp.plutusWitness.ReferenceInput != nil {
+			// plutus v1 scripts do not see reference inputs so the ledger rejects such transactions
+			if inp.plutusWitness.Script.Version == PlutusV1 {
+				return nil, nil, errors.New("PlutusV1 script can not be used as reference script")
+			}
+		} else {
+			scriptBytes, err := cbor.Marshal(inp.plutusWitness.Script.Script)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			scripts[key] = appendDistinctCbor(scripts[key], scriptBytes)
+		}
+
+		if inp.plutusWitness.Datum != nil {
+			datums = appendDistinctCbor(datums, inp.plutusWitness.Datum)
+		}
+	}
+
+	redeemers, err := b.getRedeemers()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(redeemers) == 0 && len(datums) == 0 {
+		return nil, nil, nil
+	}
+
+	redeemersBytes, err := getRedeemersCbor(redeemers, isConway)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var datumsBytes []byte
+
+	if len(datums) > 0 {
+		datumsBytes, err = cbor.Marshal(newCborSet(datums, isConway))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	languageViewsBytes, err := getLanguageViewsCbor(protocolParams, b.getPlutusScriptVersions())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, key := range []uint64{txWitnessPlutusV1Scripts, txWitnessPlutusData, txWitnessRedeemers,
+		txWitnessPlutusV2Scripts, txWitnessPlutusV3Scripts} {
+		switch {
+		case key == txWitnessPlutusData && datumsBytes != nil:
+			witnessSet = append(witnessSet, cborKeyValue{Key: key, Value: cbor.RawMessage(datumsBytes)})
+		case key == txWitnessRedeemers && len(redeemers) > 0:
+			witnessSet = append(witnessSet, cborKeyValue{Key: key, Value: cbor.RawMessage(redeemersBytes)})
+		case len(scripts[key]) > 0:
+			witnessSet = append(witnessSet, cborKeyValue{Key: key, Value: newCborSet(scripts[key], isConway)})
+		}
+	}
+
+	hash := blake2b.Sum256(bytes.Join([][]byte{redeemersBytes, datumsBytes, languageViewsBytes}, nil))
+
+	return witnessSet, hash[:], nil
+}
+
+// getPlutusScriptVersions returns distinct plutus versions of the scripts in the transaction
+func (b *TxBuilder) getPlutusScriptVersions() (versions []PlutusScriptVersion) {
+	seen := map[PlutusScriptVersion]bool{}
+
+	for _, inp := range b.inputs {
+		if inp.plutusWitness != nil && !seen[inp.plutusWitness.Script.Version] {
+			seen[inp.plutusWitness.Script.Version] = true
+			versions = append(versions, inp.plutusWitness.Script.Version)
+		}
+	}
+
+	return versions
+}
+
+// getRedeemersCbor returns redeemers in map format (conway) or in legacy array format
+func getRedeemersCbor(redeemers []TxRedeemer, isConway bool) ([]byte, error) {
+	if isConway {
+		result := make(cborOrderedMap, len(redeemers))
+
+		for i, x := range redeemers {
+			result[i] = cborKeyValue{
+				Key:   []interface{}{x.Tag, x.Index},
+				Value: []interface{}{cbor.RawMessage(x.Data), x.ExUnits.getCbor()},
+			}
+		}
+
+		return cbor.Marshal(result)
+	}
+
+	result := make([]interface{}, len(redeemers))
+
+	for i, x := range redeemers {
+		result[i] = []interface{}{x.Tag, x.Index, cbor.RawMessage(x.Data), x.ExUnits.getCbor()}
+	}
+
+	return cbor.Marshal(result)
+}
+
+// getLanguageViewsCbor returns cost models of the languages used in the transaction encoded for script data hash
+// PlutusV1 keeps its historical encoding: serialized key and indefinite list of costs wrapped in bytes
+func getLanguageViewsCbor(protocolParams ProtocolParameters, versions []PlutusScriptVersion) ([]byte, error) {
+	type languageView struct {
+		key   []byte
+		value interface{}
+	}
+
+	views := make([]languageView, 0, len(versions))
+
+	for _, version := range versions {
+		costModel, exists := protocolParams.CostModels[version.String()]
+		if !exists {
+			return nil, fmt.Errorf("cost model not found for %s", version)
+		}
+
+		if version != PlutusV1 {
+			key, err := cbor.Marshal(uint64(version) - 1)
+			if err != nil {
+				return nil, err
+			}
+
+			views = append(views, languageView{key: key, value: costModel})
+
+			continue
+		}
+
+		key, err := cbor.Marshal([]byte{0})
+		if err != nil {
+			return nil, err
+		}
+
+		value := []byte{0x9f}
+
+		for _, cost := range costModel {
+			costBytes, err := cbor.Marshal(cost)
+			if err != nil {
+				return nil, err
+			}
+
+			value = append(value, costBytes...)
+		}
+
+		views = append(views, languageView{key: key, value: append(value, 0xff)})
+	}
+
+	// canonical map keys order: shorter keys first
+	sort.Slice(views, func(i, j int) bool {
+		if len(views[i].key) != len(views[j].key) {
+			return len(views[i].key) < len(views[j].key)
+		}
+
+		return bytes.Compare(views[i].key, views[j].key) < 0
+	})
+
+	result := make(cborOrderedMap, len(views))
+
+	for i, x := range views {
+		result[i] = cborKeyValue{Key: cbor.RawMessage(x.key), Value: x.value}
+	}
+
+	return cbor.Marshal(result)
+}
+
+func appendDistinctCbor(items []cbor.RawMessage, item []byte) []cbor.RawMessage {
+	for _, x := range items {
+		if bytes.Equal(x, item) {
+			return items
+		}
+	}
+
+	return append(items, item)
+}
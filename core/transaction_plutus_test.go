@@ -0,0 +1,679 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/igorcrevar/go-cardano-tx/core/clitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+)
+
+func TestPlutusScript(t *testing.T) {
+	t.Parallel()
+
+	// always succeeds plutus v1 script
+	ps, err := NewPlutusScriptFromJSON([]byte(`{
+		"type": "PlutusScriptV1",
+		"description": "",
+		"cborHex": "4e4d01000033222220051200120011"
+	}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, PlutusV1, ps.Version)
+	assert.Equal(t, "4d01000033222220051200120011", hex.EncodeToString(ps.Script))
+
+	scriptHash, err := ps.Hash()
+	require.NoError(t, err)
+
+	assert.Equal(t, "67f33146617a5e61936081db3b2117cbf59bd2123748f58ac9678656", scriptHash)
+
+	content, err := ps.ToJSON()
+	require.NoError(t, err)
+
+	psCopy, err := NewPlutusScriptFromJSON(content)
+	require.NoError(t, err)
+
+	assert.Equal(t, ps, psCopy)
+
+	_, err = NewPlutusScriptFromJSON([]byte(`{"type": "SimpleScript", "cborHex": "4e4d01000033222220051200120011"}`))
+	require.ErrorContains(t, err, "invalid plutus script type")
+
+	_, err = NewPlutusScriptFromJSON([]byte(`{"type": "PlutusScriptV4", "cborHex": "4e4d01000033222220051200120011"}`))
+	require.ErrorContains(t, err, "unsupported plutus script version")
+}
+
+func TestGetExecutionUnitsFee(t *testing.T) {
+	t.Parallel()
+
+	var protocolParams ProtocolParameters
+
+	require.NoError(t, json.Unmarshal(protocolParameters, &protocolParams))
+
+	// 0.0577 * 1_000_000 + 0.0000721 * 500_000_001 = 57700 + 36050.0000721
+	assert.Equal(t, uint64(93751), GetExecutionUnitsFee(protocolParams, NewTxExUnits(1_000_000, 500_000_001)))
+	assert.Equal(t, uint64(93750), GetExecutionUnitsFee(protocolParams, NewTxExUnits(1_000_000, 500_000_000)))
+	assert.Equal(t, uint64(0), GetExecutionUnitsFee(protocolParams, TxExUnits{}))
+
+	assert.Equal(t, uint64(255_002), CalculateCollateral(protocolParams, 170_001))
+	assert.Equal(t, uint64(255_000), CalculateCollateral(protocolParams, 170_000))
+}
+
+func Test_TxBuilder_PlutusInputs(t *testing.T) {
+	t.Parallel()
+
+	const (
+		addr         = "addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u"
+		keyInputHash = "098236134e0f2077a6434dd9d7727126fa8b3627bcab3ae030a194d46eded73e"
+		scriptTxHash = "e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f"
+	)
+
+	witness := TxPlutusScriptWitness{
+		Script:   NewPlutusScript(PlutusV2, mustDecodeHex(t, "4d01000033222220051200120011")),
+		Datum:    []byte{0xd8, 0x79, 0x80}, // constr 0 []
+		Redeemer: []byte{0x00},
+		ExUnits:  NewTxExUnits(1_000_000, 500_000_000),
+	}
+
+	setup := func(t *testing.T, protocolParams []byte, options ...TxBuilderOption) *TxBuilder {
+		t.Helper()
+
+		builder, err := NewTxBuilder(ResolveCardanoCliBinary(TestNetNetwork), options...)
+		require.NoError(t, err)
+
+		builder.SetProtocolParameters(protocolParams).SetTimeToLive(1000)
+		builder.AddPlutusInputs(witness, NewTxInput(scriptTxHash, 0))
+		builder.AddInputs(NewTxInput(keyInputHash, 2), NewTxInput(scriptTxHash, 1))
+		builder.AddCollateralInputs(NewTxInput(keyInputHash, 3))
+		builder.SetCollateralReturn(NewTxOutput(addr, 4_000_000))
+		builder.SetTotalCollateral(1_000_000)
+		builder.AddOutputs(NewTxOutput(addr, 2_000_000))
+
+		return builder
+	}
+
+	for _, isConway := range []bool{false, true} {
+		protocolParams := protocolParameters
+		if isConway {
+			protocolParams = conwayProtocolParameters
+		}
+
+		builder := setup(t, protocolParams)
+		defer builder.Dispose()
+
+		fee, err := builder.CalculateFee(0)
+		require.NoError(t, err)
+
+		builder.SetFee(fee)
+
+		// key input and collateral input require one witness
+		feeOneWitness, err := builder.calculateFeeNative(1)
+		require.NoError(t, err)
+
+		require.Equal(t, fee, feeOneWitness)
+		require.Greater(t, fee, uint64(155381+93750))
+
+		txRaw, _, err := builder.Build()
+		require.NoError(t, err)
+
+		tx, err := NewTransaction(txRaw)
+		require.NoError(t, err)
+
+		assert.Equal(t, fee, tx.Fee)
+		assert.Equal(t, []TxInput{NewTxInput(keyInputHash, 3)}, tx.CollateralInputs)
+		assert.Equal(t, &TxOutput{Addr: addr, Amount: 4_000_000}, tx.CollateralReturn)
+		assert.Equal(t, uint64(1_000_000), tx.TotalCollateral)
+		assert.Equal(t, []PlutusScript{witness.Script}, tx.PlutusScripts)
+		assert.Equal(t, [][]byte{witness.Datum}, tx.Datums)
+		// script input is second one in the ordered inputs
+		assert.Equal(t, []TxRedeemer{{
+			Tag: RedeemerTagSpend, Index: 1, Data: witness.Redeemer, ExUnits: witness.ExUnits,
+		}}, tx.Redeemers)
+
+		var txParts []cbor.RawMessage
+
+		require.NoError(t, cbor.Unmarshal(txRaw, &txParts))
+
+		witnessSet := map[uint64]cbor.RawMessage{}
+
+		require.NoError(t, cbor.Unmarshal(txParts[1], &witnessSet))
+
+		// conway redeemers are encoded as map
+		assert.Equal(t, isConway, witnessSet[txWitnessRedeemers][0]&0xe0 == cborMajorTypeMap)
+
+		var parsedParams ProtocolParameters
+
+		require.NoError(t, json.Unmarshal(protocolParams, &parsedParams))
+
+		languageViews, err := getLanguageViewsCbor(parsedParams, []PlutusScriptVersion{PlutusV2})
+		require.NoError(t, err)
+
+		scriptDataHash := blake2b.Sum256(bytes.Join([][]byte{
+			witnessSet[txWitnessRedeemers], witnessSet[txWitnessPlutusData], languageViews}, nil))
+
+		assert.Equal(t, hex.EncodeToString(scriptDataHash[:]), tx.ScriptDataHash)
+
+		// signing keeps redeemers and datums untouched
+		wallet, err := GenerateWallet(false)
+		require.NoError(t, err)
+
+		txSigned, err := builder.SignTx(txRaw, []ITxSigner{wallet})
+		require.NoError(t, err)
+
+		txSignedDecoded, err := NewTransaction(txSigned)
+		require.NoError(t, err)
+
+		assert.Equal(t, tx.Redeemers, txSignedDecoded.Redeemers)
+		assert.Equal(t, tx.Datums, txSignedDecoded.Datums)
+		assert.Len(t, txSignedDecoded.VKeyWitnesses, 1)
+	}
+
+	t.Run("language views", func(t *testing.T) {
+		t.Parallel()
+
+		languageViews, err := getLanguageViewsCbor(ProtocolParameters{
+			CostModels: map[string][]int64{
+				"PlutusV1": {1, -2},
+				"PlutusV2": {3},
+				"PlutusV3": {4, 5},
+			},
+		}, []PlutusScriptVersion{PlutusV1, PlutusV3, PlutusV2})
+		require.NoError(t, err)
+
+		// {1: [3], 2: [4, 5], h'00': h'9f0121ff'}
+		assert.Equal(t, "a3018103028204054100449f0121ff", hex.EncodeToString(languageViews))
+
+		_, err = getLanguageViewsCbor(ProtocolParameters{}, []PlutusScriptVersion{PlutusV3})
+		require.ErrorContains(t, err, "cost model not found for PlutusV3")
+	})
+
+	t.Run("cardano-cli", func(t *testing.T) {
+		t.Parallel()
+
+		var builder *TxBuilder
+
+		fakeCli := clitest.NewFakeCardanoCli().
+			HandleFunc(func(call clitest.Call) clitest.Response {
+				txRaw, _, err := builder.buildRawTxNative(0)
+				if err != nil {
+					return clitest.Response{Err: err}
+				}
+
+				return clitest.Response{Files: map[string][]byte{
+					"--out-file": clitest.TextEnvelope("Unwitnessed Tx BabbageEra", txRaw),
+				}}
+			}, "transaction", "build-raw").
+			Handle(clitest.Response{Stdout: strings.Repeat("ab", 32)}, "transaction", "txid")
+
+		builder = setup(t, protocolParameters, WithCardanoCliBuild(WithCommandRunner(fakeCli)))
+		defer builder.Dispose()
+
+		_, _, err := builder.Build()
+		require.NoError(t, err)
+
+		calls := fakeCli.CallsOf("transaction", "build-raw")
+		require.Len(t, calls, 1)
+
+		scriptJSON, err := os.ReadFile(calls[0].Flag("--tx-in-script-file"))
+		require.NoError(t, err)
+
+		script, err := NewPlutusScriptFromJSON(scriptJSON)
+		require.NoError(t, err)
+
+		datum, err := os.ReadFile(calls[0].Flag("--tx-in-datum-cbor-file"))
+		require.NoError(t, err)
+
+		redeemer, err := os.ReadFile(calls[0].Flag("--tx-in-redeemer-cbor-file"))
+		require.NoError(t, err)
+
+		assert.Equal(t, witness.Script, script)
+		assert.Equal(t, witness.Datum, datum)
+		assert.Equal(t, witness.Redeemer, redeemer)
+		assert.Equal(t, "(500000000, 1000000)", calls[0].Flag("--tx-in-execution-units"))
+		assert.Equal(t, keyInputHash+"#3", calls[0].Flag("--tx-in-collateral"))
+		assert.Equal(t, addr+"+4000000", calls[0].Flag("--tx-out-return-collateral"))
+		assert.Equal(t, "1000000", calls[0].Flag("--tx-total-collateral"))
+	})
+}
+
+type txEvaluatorFunc func(ctx context.Context, txRaw []byte) (map[TxRedeemerPointer]TxExUnits, error)
+
+func (f txEvaluatorFunc) EvaluateTx(ctx context.Context, txRaw []byte) (map[TxRedeemerPointer]TxExUnits, error) {
+	return f(ctx, txRaw)
+}
+
+func Test_TxBuilder_EvaluateExUnits(t *testing.T) {
+	t.Parallel()
+
+	const (
+		addr         = "addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u"
+		scriptTxHash = "e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f"
+	)
+
+	setup := func(t *testing.T) *TxBuilder {
+		t.Helper()
+
+		builder, err := NewTxBuilder(ResolveCardanoCliBinary(TestNetNetwork))
+		require.NoError(t, err)
+
+		builder.SetProtocolParameters(protocolParameters).SetTimeToLive(1000)
+		builder.AddPlutusInputs(TxPlutusScriptWitness{
+			Script:   NewPlutusScript(PlutusV2, mustDecodeHex(t, "4d01000033222220051200120011")),
+			Redeemer: []byte{0x00},
+		}, NewTxInput(scriptTxHash, 2), NewTxInput(scriptTxHash, 1))
+		builder.AddCollateralInputs(NewTxInput(scriptTxHash, 3))
+		builder.AddOutputs(NewTxOutput(addr, 2_000_000))
+
+		return builder
+	}
+
+	evaluator := func(exUnits map[TxRedeemerPointer]TxExUnits) ITxEvaluator {
+		return txEvaluatorFunc(func(_ context.Context, txRaw []byte) (map[TxRedeemerPointer]TxExUnits, error) {
+			tx, err := NewTransaction(txRaw)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(tx.Redeemers) != 2 {
+				return nil, errors.New("redeemers expected")
+			}
+
+			return exUnits, nil
+		})
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		builder := setup(t)
+		defer builder.Dispose()
+
+		feeWithoutExUnits, err := builder.CalculateFee(0)
+		require.NoError(t, err)
+
+		require.NoError(t, builder.EvaluateExUnits(context.Background(), evaluator(map[TxRedeemerPointer]TxExUnits{
+			{Tag: RedeemerTagSpend, Index: 0}: NewTxExUnits(1_000, 2_000),
+			{Tag: RedeemerTagSpend, Index: 1}: NewTxExUnits(3_000, 4_000),
+		})))
+
+		fee, err := builder.CalculateFee(0)
+		require.NoError(t, err)
+
+		// execution units fee and few more bytes for encoding execution units
+		require.GreaterOrEqual(t, fee, feeWithoutExUnits+GetExecutionUnitsFee(ProtocolParameters{
+			ExecutionUnitPrices: NewProtocolParametersPriceMemorySteps(0.0577, 0.0000721),
+		}, NewTxExUnits(4_000, 6_000)))
+
+		txRaw, _, err := builder.SetFee(fee).Build()
+		require.NoError(t, err)
+
+		tx, err := NewTransaction(txRaw)
+		require.NoError(t, err)
+
+		require.Equal(t, []TxRedeemer{
+			{Tag: RedeemerTagSpend, Index: 0, Data: []byte{0x00}, ExUnits: NewTxExUnits(1_000, 2_000)},
+			{Tag: RedeemerTagSpend, Index: 1, Data: []byte{0x00}, ExUnits: NewTxExUnits(3_000, 4_000)},
+		}, tx.Redeemers)
+	})
+
+	t.Run("missing redeemer", func(t *testing.T) {
+		t.Parallel()
+
+		builder := setup(t)
+		defer builder.Dispose()
+
+		err := builder.EvaluateExUnits(context.Background(), evaluator(map[TxRedeemerPointer]TxExUnits{
+			{Tag: RedeemerTagSpend, Index: 0}: NewTxExUnits(1_000, 2_000),
+		}))
+		require.ErrorContains(t, err, "execution units not evaluated for input "+scriptTxHash+"#2")
+	})
+
+	t.Run("budget exceeded", func(t *testing.T) {
+		t.Parallel()
+
+		builder := setup(t)
+		defer builder.Dispose()
+
+		err := builder.EvaluateExUnits(context.Background(), evaluator(map[TxRedeemerPointer]TxExUnits{
+			{Tag: RedeemerTagSpend, Index: 0}: NewTxExUnits(10_000_000, 2_000),
+			{Tag: RedeemerTagSpend, Index: 1}: NewTxExUnits(10_000_000, 4_000),
+		}))
+
+		var budgetErr *ExUnitsBudgetError
+
+		require.True(t, errors.As(err, &budgetErr))
+		require.Equal(t, NewTxExUnits(20_000_000, 6_000), budgetErr.ExUnits)
+		require.Equal(t, NewTxExUnits(16_000_000, 10_000_000_000), budgetErr.MaxExUnits)
+
+		_, _, err = builder.Build()
+		require.True(t, errors.As(err, &budgetErr))
+	})
+}
+
+func Test_TxOutput_DatumAndReferenceScript(t *testing.T) {
+	t.Parallel()
+
+	const (
+		addr      = "addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u"
+		inputHash = "098236134e0f2077a6434dd9d7727126fa8b3627bcab3ae030a194d46eded73e"
+		datumHash = "923918e403bf43c34b4ef6b48eb2ee04babed17320d8d1b9ff9ad086e86f44ec"
+	)
+
+	plutusScript := NewPlutusScript(PlutusV2, mustDecodeHex(t, "4d01000033222220051200120011"))
+	nativeScript := PolicyScript{Type: PolicyScriptSigType, KeyHash: "2411b7ef3c33d35c2f1e1a2fa4ef0a63e1aa5fe9ac9cd8ca4d1c1b09"}
+
+	outputs := []TxOutput{
+		NewTxOutput(addr, 1_000_000),
+		NewTxOutput(addr, 2_000_000).WithInlineDatum([]byte{0xd8, 0x79, 0x80}),
+		NewTxOutput(addr, 3_000_000).WithDatumHash(datumHash),
+		NewTxOutput(addr, 4_000_000).WithReferenceScript(NewTxReferenceScriptPlutus(plutusScript)),
+		NewTxOutput(addr, 5_000_000).WithInlineDatum([]byte{0x00}).
+			WithReferenceScript(NewTxReferenceScriptNative(nativeScript)),
+	}
+
+	t.Run("min utxo", func(t *testing.T) {
+		t.Parallel()
+
+		protocolParams := ProtocolParameters{UtxoCostPerByte: 4310}
+
+		minUtxo, err := CalculateMinUtxoForOutput(protocolParams, outputs[0])
+		require.NoError(t, err)
+
+		for _, output := range outputs[1:] {
+			minUtxoOutput, err := CalculateMinUtxoForOutput(protocolParams, output)
+			require.NoError(t, err)
+
+			assert.Greater(t, minUtxoOutput, minUtxo)
+		}
+
+		// map keys 0 and 1, key 2 and datum option [1, #6.24(h'd87980')] add 2 + 1 + 8 bytes
+		minUtxoDatum, err := CalculateMinUtxoForOutput(protocolParams, outputs[1])
+		require.NoError(t, err)
+
+		assert.Equal(t, minUtxo+11*4310, minUtxoDatum)
+
+		_, err = CalculateMinUtxoForOutput(protocolParams, NewTxOutput(addr, 0).WithDatumHash("ab"))
+		require.ErrorContains(t, err, "invalid datum hash")
+	})
+
+	t.Run("serialization", func(t *testing.T) {
+		t.Parallel()
+
+		outputCbor, err := getTxOutputCbor(outputs[0])
+		require.NoError(t, err)
+
+		assert.IsType(t, []interface{}{}, outputCbor)
+
+		builder, err := NewTxBuilder(ResolveCardanoCliBinary(TestNetNetwork))
+		require.NoError(t, err)
+
+		defer builder.Dispose()
+
+		builder.SetProtocolParameters(protocolParameters).SetTimeToLive(1000).SetFee(200_000)
+		builder.AddInputs(NewTxInput(inputHash, 0))
+		builder.AddOutputs(outputs...)
+
+		txRaw, _, err := builder.Build()
+		require.NoError(t, err)
+
+		tx, err := NewTransaction(txRaw)
+		require.NoError(t, err)
+
+		assert.Equal(t, outputs, tx.Outputs)
+	})
+
+	t.Run("cardano-cli", func(t *testing.T) {
+		t.Parallel()
+
+		var builder *TxBuilder
+
+		fakeCli := clitest.NewFakeCardanoCli().
+			HandleFunc(func(call clitest.Call) clitest.Response {
+				txRaw, _, err := builder.buildRawTxNative(0)
+				if err != nil {
+					return clitest.Response{Err: err}
+				}
+
+				return clitest.Response{Files: map[string][]byte{
+					"--out-file": clitest.TextEnvelope("Unwitnessed Tx BabbageEra", txRaw),
+				}}
+			}, "transaction", "build-raw").
+			Handle(clitest.Response{Stdout: "Lovelace 1000000\n"}, "transaction", "calculate-min-required-utxo").
+			Handle(clitest.Response{Stdout: strings.Repeat("ab", 32)}, "transaction", "txid")
+
+		builder, err := NewTxBuilder(ResolveCardanoCliBinary(TestNetNetwork), WithCardanoCliBuild(WithCommandRunner(fakeCli)))
+		require.NoError(t, err)
+
+		defer builder.Dispose()
+
+		builder.SetProtocolParameters(protocolParameters).SetTimeToLive(1000).SetFee(200_000)
+		builder.AddInputs(NewTxInput(inputHash, 0))
+		builder.AddOutputs(outputs[2:]...)
+
+		_, err = builder.CalculateMinUtxo(outputs[1])
+		require.NoError(t, err)
+
+		_, _, err = builder.Build()
+		require.NoError(t, err)
+
+		minUtxoCalls := fakeCli.CallsOf("transaction", "calculate-min-required-utxo")
+		require.Len(t, minUtxoCalls, 1)
+
+		datum, err := os.ReadFile(minUtxoCalls[0].Flag("--tx-out-inline-datum-cbor-file"))
+		require.NoError(t, err)
+
+		assert.Equal(t, outputs[1].Datum, datum)
+
+		calls := fakeCli.CallsOf("transaction", "build-raw")
+		require.Len(t, calls, 1)
+
+		assert.Equal(t, datumHash, calls[0].Flag("--tx-out-datum-hash"))
+
+		scriptJSON, err := os.ReadFile(calls[0].Flag("--tx-out-reference-script-file"))
+		require.NoError(t, err)
+
+		script, err := NewPlutusScriptFromJSON(scriptJSON)
+		require.NoError(t, err)
+
+		assert.Equal(t, plutusScript, script)
+	})
+}
+
+func Test_TxBuilder_ReferenceInputs(t *testing.T) {
+	t.Parallel()
+
+	const (
+		scriptTxHash = "e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f"
+		refTxHash    = "2c4e1fc7ae53aa0ebeb14a4e8d5d8a5fd4fa0bd9fd3c6e25d8d4e06e6d5f40a1"
+	)
+
+	policyScript := NewPolicyScript([]string{
+		"2411b7ef3c33d35c2f1e1a2fa4ef0a63e1aa5fe9ac9cd8ca4d1c1b09",
+		"9fd3c6e25d8d4e06e6d5f40a12c4e1fc7ae53aa0ebeb14a4e8d5d8a5",
+	}, 2)
+	oracleInput := NewTxInput(refTxHash, 0)
+	scriptRefInput := NewTxInput(refTxHash, 1)
+
+	t.Run("native reference script", func(t *testing.T) {
+		t.Parallel()
+
+		builderScript := newTestTxBuilder(t, protocolParameters)
+		builderScript.AddInputsWithScript(policyScript, NewTxInput(scriptTxHash, 0), NewTxInput(scriptTxHash, 1))
+
+		builderRef := newTestTxBuilder(t, protocolParameters)
+		builderRef.AddInputsWithReferenceScript(policyScript, scriptRefInput,
+			NewTxInput(scriptTxHash, 0), NewTxInput(scriptTxHash, 1))
+
+		feeScript, err := builderScript.CalculateFee(0)
+		require.NoError(t, err)
+
+		feeRef, err := builderRef.CalculateFee(0)
+		require.NoError(t, err)
+
+		assert.Less(t, feeRef, feeScript)
+
+		// script reference input is not duplicated
+		builderRef.AddReferenceInputs(oracleInput, scriptRefInput)
+
+		txRaw, _, err := builderRef.Build()
+		require.NoError(t, err)
+
+		tx, err := NewTransaction(txRaw)
+		require.NoError(t, err)
+
+		assert.Equal(t, []TxInput{oracleInput, scriptRefInput}, tx.ReferenceInputs)
+		assert.Empty(t, tx.NativeScripts)
+
+		// key input owner and both signers of the referenced script (counted for every input)
+		witnessCount, err := builderRef.getWitnessCount()
+		require.NoError(t, err)
+
+		assert.Equal(t, 5, witnessCount)
+	})
+
+	t.Run("plutus reference script", func(t *testing.T) {
+		t.Parallel()
+
+		witness := TxPlutusScriptWitness{
+			Script:         NewPlutusScript(PlutusV2, nil),
+			ReferenceInput: &scriptRefInput,
+			Redeemer:       []byte{0x00},
+			ExUnits:        NewTxExUnits(1_000_000, 500_000_000),
+		}
+
+		builder := newTestTxBuilder(t, protocolParameters)
+		builder.AddPlutusInputs(witness, NewTxInput(scriptTxHash, 0))
+		builder.AddCollateralInputs(NewTxInput(testInputHash, 3))
+
+		txRaw, _, err := builder.Build()
+		require.NoError(t, err)
+
+		tx, err := NewTransaction(txRaw)
+		require.NoError(t, err)
+
+		assert.Equal(t, []TxInput{scriptRefInput}, tx.ReferenceInputs)
+		assert.Empty(t, tx.PlutusScripts)
+		assert.Len(t, tx.Redeemers, 1)
+		assert.NotEmpty(t, tx.ScriptDataHash)
+
+		witness.Script.Version = PlutusV1
+
+		builder = newTestTxBuilder(t, protocolParameters)
+		builder.AddPlutusInputs(witness, NewTxInput(scriptTxHash, 0))
+
+		_, _, err = builder.Build()
+		require.ErrorContains(t, err, "PlutusV1 script can not be used as reference script")
+	})
+
+	t.Run("reference scripts fee", func(t *testing.T) {
+		t.Parallel()
+
+		plutusScriptRefInput := NewTxInput(refTxHash, 2)
+		refScriptParams := bytes.Replace(conwayProtocolParameters,
+			[]byte(`"txFeeFixed"`), []byte(`"minFeeRefScriptCostPerByte":15,"txFeeFixed"`), 1)
+
+		policyScriptBytes, err := policyScript.MarshalCBOR()
+		require.NoError(t, err)
+
+		newBuilder := func(t *testing.T, protocolParams []byte) *TxBuilder {
+			t.Helper()
+
+			builder := newTestTxBuilder(t, protocolParams)
+			// the same script utxo is counted only once
+			builder.AddInputsWithReferenceScript(policyScript, scriptRefInput,
+				NewTxInput(scriptTxHash, 0), NewTxInput(scriptTxHash, 1))
+			builder.AddPlutusInputs(TxPlutusScriptWitness{
+				Script:         NewPlutusScript(PlutusV2, bytes.Repeat([]byte{0x01}, 100)),
+				ReferenceInput: &plutusScriptRefInput,
+				Redeemer:       []byte{0x00},
+				ExUnits:        NewTxExUnits(1_000_000, 500_000_000),
+			}, NewTxInput(scriptTxHash, 2))
+			builder.AddCollateralInputs(NewTxInput(testInputHash, 3))
+			// script of other reference input
+			builder.SetReferenceScriptsSize(50)
+
+			return builder
+		}
+
+		builder := newBuilder(t, refScriptParams)
+
+		referenceScriptsSize, err := builder.getReferenceScriptsSize()
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(len(policyScriptBytes)+100+50), referenceScriptsSize)
+
+		fee, err := builder.CalculateFee(0)
+		require.NoError(t, err)
+
+		feeWithoutRefScripts, err := newBuilder(t, conwayProtocolParameters).CalculateFee(0)
+		require.NoError(t, err)
+
+		assert.Equal(t, GetReferenceScriptsFee(ProtocolParameters{MinFeeRefScriptCostPerByte: 15}, referenceScriptsSize),
+			fee-feeWithoutRefScripts)
+	})
+
+	t.Run("cardano-cli", func(t *testing.T) {
+		t.Parallel()
+
+		var builder *TxBuilder
+
+		fakeCli := clitest.NewFakeCardanoCli().
+			HandleFunc(func(call clitest.Call) clitest.Response {
+				txRaw, _, err := builder.buildRawTxNative(0)
+				if err != nil {
+					return clitest.Response{Err: err}
+				}
+
+				return clitest.Response{Files: map[string][]byte{
+					"--out-file": clitest.TextEnvelope("Unwitnessed Tx BabbageEra", txRaw),
+				}}
+			}, "transaction", "build-raw").
+			Handle(clitest.Response{Stdout: strings.Repeat("ab", 32)}, "transaction", "txid").
+			Handle(clitest.Response{Stdout: "170000 Lovelace\n"}, "transaction", "calculate-min-fee")
+
+		policyScriptBytes, err := policyScript.MarshalCBOR()
+		require.NoError(t, err)
+
+		builder = newTestTxBuilder(t, protocolParameters, WithCardanoCliBuild(WithCommandRunner(fakeCli)))
+		builder.AddInputsWithReferenceScript(policyScript, scriptRefInput, NewTxInput(scriptTxHash, 0))
+		builder.AddPlutusInputs(TxPlutusScriptWitness{
+			Script:         NewPlutusScript(PlutusV2, nil),
+			ReferenceInput: &scriptRefInput,
+			Redeemer:       []byte{0x00},
+			ExUnits:        NewTxExUnits(1_000_000, 500_000_000),
+		}, NewTxInput(scriptTxHash, 1))
+		builder.AddReferenceInputs(oracleInput)
+
+		_, err = builder.CalculateFee(0)
+		require.NoError(t, err)
+
+		feeCalls := fakeCli.CallsOf("transaction", "calculate-min-fee")
+		require.Len(t, feeCalls, 1)
+
+		assert.Equal(t, strconv.Itoa(len(policyScriptBytes)), feeCalls[0].Flag("--reference-script-size"))
+
+		_, _, err = builder.Build()
+		require.NoError(t, err)
+
+		calls := fakeCli.CallsOf("transaction", "build-raw")
+		require.Len(t, calls, 2)
+
+		assert.Equal(t, scriptRefInput.String(), calls[1].Flag("--simple-script-tx-in-reference"))
+		assert.Equal(t, scriptRefInput.String(), calls[1].Flag("--spending-tx-in-reference"))
+		assert.True(t, calls[1].HasFlag("--spending-plutus-script-v2"))
+		assert.True(t, calls[1].HasFlag("--spending-reference-tx-in-inline-datum-present"))
+		assert.NotEmpty(t, calls[1].Flag("--spending-reference-tx-in-redeemer-cbor-file"))
+		assert.Equal(t, "(500000000, 1000000)", calls[1].Flag("--spending-reference-tx-in-execution-units"))
+		assert.Equal(t, oracleInput.String(), calls[1].Flag("--read-only-tx-in-reference"))
+		assert.False(t, calls[1].HasFlag("--tx-in-script-file"))
+	})
+}
@@ -1,19 +1,44 @@
 package core
 
 import (
+	"bytes"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"math"
 	"testing"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 var (
-	protocolParameters = []byte(`{"costModels":{"PlutusV1":[197209,0,1,1,396231,621,0,1,150000,1000,0,1,150000,32,2477736,29175,4,29773,100,29773,100,29773,100,29773,100,29773,100,29773,100,100,100,29773,100,150000,32,150000,32,150000,32,150000,1000,0,1,150000,32,150000,1000,0,8,148000,425507,118,0,1,1,150000,1000,0,8,150000,112536,247,1,150000,10000,1,136542,1326,1,1000,150000,1000,1,150000,32,150000,32,150000,32,1,1,150000,1,150000,4,103599,248,1,103599,248,1,145276,1366,1,179690,497,1,150000,32,150000,32,150000,32,150000,32,150000,32,150000,32,148000,425507,118,0,1,1,61516,11218,0,1,150000,32,148000,425507,118,0,1,1,148000,425507,118,0,1,1,2477736,29175,4,0,82363,4,150000,5000,0,1,150000,32,197209,0,1,1,150000,32,150000,32,150000,32,150000,32,150000,32,150000,32,150000,32,3345831,1,1],"PlutusV2":[205665,812,1,1,1000,571,0,1,1000,24177,4,1,1000,32,117366,10475,4,23000,100,23000,100,23000,100,23000,100,23000,100,23000,100,100,100,23000,100,19537,32,175354,32,46417,4,221973,511,0,1,89141,32,497525,14068,4,2,196500,453240,220,0,1,1,1000,28662,4,2,245000,216773,62,1,1060367,12586,1,208512,421,1,187000,1000,52998,1,80436,32,43249,32,1000,32,80556,1,57667,4,1000,10,197145,156,1,197145,156,1,204924,473,1,208896,511,1,52467,32,64832,32,65493,32,22558,32,16563,32,76511,32,196500,453240,220,0,1,1,69522,11687,0,1,60091,32,196500,453240,220,0,1,1,196500,453240,220,0,1,1,1159724,392670,0,2,806990,30482,4,1927926,82523,4,265318,0,4,0,85931,32,205665,812,1,1,41182,32,212342,32,31220,32,32696,32,43357,32,32247,32,38314,32,35892428,10,9462713,1021,10,38887044,32947,10]},"protocolVersion":{"major":7,"minor":0},"maxBlockHeaderSize":1100,"maxBlockBodySize":65536,"maxTxSize":16384,"txFeeFixed":155381,"txFeePerByte":44,"stakeAddressDeposit":0,"stakePoolDeposit":0,"minPoolCost":0,"poolRetireMaxEpoch":18,"stakePoolTargetNum":100,"poolPledgeInfluence":0,"monetaryExpansion":0.1,"treasuryCut":0.1,"collateralPercentage":150,"executionUnitPrices":{"priceMemory":0.0577,"priceSteps":0.0000721},"utxoCostPerByte":4310,"maxTxExecutionUnits":{"memory":16000000,"steps":10000000000},"maxBlockExecutionUnits":{"memory":80000000,"steps":40000000000},"maxCollateralInputs":3,"maxValueSize":5000,"extraPraosEntropy":null,"decentralization":null,"minUTxOValue":null}`)
+	protocolParameters       = []byte(`{"costModels":{"PlutusV1":[197209,0,1,1,396231,621,0,1,150000,1000,0,1,150000,32,2477736,29175,4,29773,100,29773,100,29773,100,29773,100,29773,100,29773,100,100,100,29773,100,150000,32,150000,32,150000,32,150000,1000,0,1,150000,32,150000,1000,0,8,148000,425507,118,0,1,1,150000,1000,0,8,150000,112536,247,1,150000,10000,1,136542,1326,1,1000,150000,1000,1,150000,32,150000,32,150000,32,1,1,150000,1,150000,4,103599,248,1,103599,248,1,145276,1366,1,179690,497,1,150000,32,150000,32,150000,32,150000,32,150000,32,150000,32,148000,425507,118,0,1,1,61516,11218,0,1,150000,32,148000,425507,118,0,1,1,148000,425507,118,0,1,1,2477736,29175,4,0,82363,4,150000,5000,0,1,150000,32,197209,0,1,1,150000,32,150000,32,150000,32,150000,32,150000,32,150000,32,150000,32,3345831,1,1],"PlutusV2":[205665,812,1,1,1000,571,0,1,1000,24177,4,1,1000,32,117366,10475,4,23000,100,23000,100,23000,100,23000,100,23000,100,23000,100,100,100,23000,100,19537,32,175354,32,46417,4,221973,511,0,1,89141,32,497525,14068,4,2,196500,453240,220,0,1,1,1000,28662,4,2,245000,216773,62,1,1060367,12586,1,208512,421,1,187000,1000,52998,1,80436,32,43249,32,1000,32,80556,1,57667,4,1000,10,197145,156,1,197145,156,1,204924,473,1,208896,511,1,52467,32,64832,32,65493,32,22558,32,16563,32,76511,32,196500,453240,220,0,1,1,69522,11687,0,1,60091,32,196500,453240,220,0,1,1,196500,453240,220,0,1,1,1159724,392670,0,2,806990,30482,4,1927926,82523,4,265318,0,4,0,85931,32,205665,812,1,1,41182,32,212342,32,31220,32,32696,32,43357,32,32247,32,38314,32,35892428,10,9462713,1021,10,38887044,32947,10]},"protocolVersion":{"major":7,"minor":0},"maxBlockHeaderSize":1100,"maxBlockBodySize":65536,"maxTxSize":16384,"txFeeFixed":155381,"txFeePerByte":44,"stakeAddressDeposit":0,"stakePoolDeposit":0,"minPoolCost":0,"poolRetireMaxEpoch":18,"stakePoolTargetNum":100,"poolPledgeInfluence":0,"monetaryExpansion":0.1,"treasuryCut":0.1,"collateralPercentage":150,"executionUnitPrices":{"priceMemory":0.0577,"priceSteps":0.0000721},"utxoCostPerByte":4310,"maxTxExecutionUnits":{"memory":16000000,"steps":10000000000},"maxBlockExecutionUnits":{"memory":80000000,"steps":40000000000},"maxCollateralInputs":3,"maxValueSize":5000,"extraPraosEntropy":null,"decentralization":null,"minUTxOValue":null}`)
+	conwayProtocolParameters = bytes.Replace(protocolParameters, []byte(`"major":7`), []byte(`"major":9`), 1)
 )
 
+const (
+	testAddr      = "addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u"
+	testInputHash = "098236134e0f2077a6434dd9d7727126fa8b3627bcab3ae030a194d46eded73e"
+)
+
+// newTestTxBuilder returns builder with protocol parameters, time to live, fee, one key input and one output
+func newTestTxBuilder(t *testing.T, protocolParams []byte, options ...TxBuilderOption) *TxBuilder {
+	t.Helper()
+
+	builder, err := NewTxBuilder(ResolveCardanoCliBinary(TestNetNetwork), options...)
+	require.NoError(t, err)
+
+	t.Cleanup(builder.Dispose)
+
+	builder.SetProtocolParameters(protocolParams).SetTimeToLive(1000).SetFee(200_000)
+	builder.AddInputs(NewTxInput(testInputHash, 0))
+	builder.AddOutputs(NewTxOutput(testAddr, 2_000_000))
+
+	return builder
+}
+
 func Test_TransactionBuilder(t *testing.T) {
 	t.Parallel()
 
@@ -41,16 +66,10 @@ func Test_TransactionBuilder(t *testing.T) {
 	policyScriptFeeMultiSig := NewPolicyScript(walletsFeeKeyHashes, len(walletsFeeKeyHashes)*2/3+1)
 	cliUtils := NewCliUtils(ResolveCardanoCliBinary(TestNetNetwork))
 
-	multisigPolicyID, err := cliUtils.GetPolicyID(policyScriptMultiSig)
-	require.NoError(t, err)
-
-	feeMultisigPolicyID, err := cliUtils.GetPolicyID(policyScriptFeeMultiSig)
-	require.NoError(t, err)
-
-	multiSigAddr, err := NewPolicyScriptAddress(TestNetNetwork, multisigPolicyID)
+	multiSigAddr, err := NewPolicyScriptAddressFromScript(TestNetNetwork, policyScriptMultiSig)
 	require.NoError(t, err)
 
-	multiSigFeeAddr, err := NewPolicyScriptAddress(TestNetNetwork, feeMultisigPolicyID)
+	multiSigFeeAddr, err := NewPolicyScriptAddressFromScript(TestNetNetwork, policyScriptFeeMultiSig)
 	require.NoError(t, err)
 
 	type metaDataKey0 struct {
@@ -83,7 +102,8 @@ func Test_TransactionBuilder(t *testing.T) {
 	}
 	outputsSum := GetOutputsSum(outputs)
 
-	builder, err := NewTxBuilder(ResolveCardanoCliBinary(TestNetNetwork))
+	// reference values are produced by cardano-cli
+	builder, err := NewTxBuilder(ResolveCardanoCliBinary(TestNetNetwork), WithCardanoCliBuild())
 	require.NoError(t, err)
 
 	defer builder.Dispose()
@@ -149,6 +169,293 @@ func Test_TransactionBuilder(t *testing.T) {
 	require.Equal(t, txHash, txHashUtil)
 }
 
+func Test_TxBuilder_BuildNative(t *testing.T) {
+	t.Parallel()
+
+	policyScriptMultiSig := NewPolicyScript([]string{
+		"d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21",
+		"cba89c7084bf0ce4bf404346b668a7e83c8c9c250d1cafd8d8996e41",
+		"79df3577e4c7d7da04872c2182b8d8829d7b477912dbf35d89287c39",
+		"2368e8113bd5f32d713751791d29acee9e1b5a425b0454b963b2558b",
+		"06b4c7f5254d6395b527ac3de60c1d77194df7431d85fe55ca8f107d",
+	}, 4)
+	policyScriptFeeMultiSig := NewPolicyScript([]string{
+		"f0f4837b3a306752a2b3e52394168bc7391de3dce11364b723cc55cf",
+		"47344d5bd7b2fea56336ba789579705a944760032585ef64084c92db",
+		"f01018c1d8da54c2f557679243b09af1c4dd4d9c671512b01fa5f92b",
+		"6837232854849427dae7c45892032d7ded136c5beb13c68fda635d87",
+		"d215701e2eb17c741b9d306cba553f9fbaaca1e12a5925a065b90fa8",
+	}, 4)
+
+	multiSigAddr, err := NewPolicyScriptAddress(
+		TestNetNetwork, "4aaad0f0626a8ce7b097497e542055b6520842ade881f980e002ae66")
+	require.NoError(t, err)
+
+	multiSigFeeAddr, err := NewPolicyScriptAddress(
+		TestNetNetwork, "3ea4c4aef89a27f111e78464d7d6717b099f85ce27109ee9e5fbddec")
+	require.NoError(t, err)
+
+	builder, err := NewTxBuilder(ResolveCardanoCliBinary(TestNetNetwork))
+	require.NoError(t, err)
+
+	defer builder.Dispose()
+
+	builder.SetTimeToLive(28096).SetProtocolParameters(protocolParameters).SetFee(264897)
+	builder.SetMetaData([]byte(`{"0":{"type":"multi","signers":5,"feeSigners":5},"4":{"comp":"Ethernal","city":"Novi Sad"}}`))
+	builder.AddOutputs(TxOutput{
+		Addr:   "addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u",
+		Amount: 1_000_000,
+	}, TxOutput{
+		Addr:   multiSigAddr.String(),
+		Amount: 1_999_990,
+	}, TxOutput{
+		Addr:   multiSigFeeAddr.String(),
+		Amount: 1_735_103,
+	})
+	builder.AddInputsWithScript(policyScriptMultiSig,
+		NewTxInput("e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f", 0),
+		NewTxInput("d1fd0d772be7741d9bfaf0b037d02d2867a987ccba3e6ba2ee9aa2a861b73145", 2))
+	builder.AddInputsWithScript(policyScriptFeeMultiSig,
+		NewTxInput("098236134e0f2077a6434dd9d7727126fa8b3627bcab3ae030a194d46eded73e", 0))
+
+	txRaw, txHash, err := builder.Build()
+	require.NoError(t, err)
+
+	// same transaction as the one built by cardano-cli in Test_TransactionBuilder
+	assert.Equal(t, "84a50083825820098236134e0f2077a6434dd9d7727126fa8b3627bcab3ae030a194d46eded73e00825820d1fd0d772be7741d9bfaf0b037d02d2867a987ccba3e6ba2ee9aa2a861b7314502825820e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f00018382581d60244877c1aeefc7fd5405a6e14d927d91758d45e37c20fa2ac89cb1671a000f424082581d704aaad0f0626a8ce7b097497e542055b6520842ade881f980e002ae661a001e847682581d703ea4c4aef89a27f111e78464d7d6717b099f85ce27109ee9e5fbddec1a001a79bf021a00040ac103196dc0075820802e4d6f15ce98826886a5451e94855e77aae779cb341d3aab1e3bae4fb2f78da10182830304858200581c47344d5bd7b2fea56336ba789579705a944760032585ef64084c92db8200581c6837232854849427dae7c45892032d7ded136c5beb13c68fda635d878200581cd215701e2eb17c741b9d306cba553f9fbaaca1e12a5925a065b90fa88200581cf01018c1d8da54c2f557679243b09af1c4dd4d9c671512b01fa5f92b8200581cf0f4837b3a306752a2b3e52394168bc7391de3dce11364b723cc55cf830304858200581c06b4c7f5254d6395b527ac3de60c1d77194df7431d85fe55ca8f107d8200581c2368e8113bd5f32d713751791d29acee9e1b5a425b0454b963b2558b8200581c79df3577e4c7d7da04872c2182b8d8829d7b477912dbf35d89287c398200581ccba89c7084bf0ce4bf404346b668a7e83c8c9c250d1cafd8d8996e418200581cd6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21f5d90103a100a200a36a6665655369676e65727305677369676e657273056474797065656d756c746904a26463697479684e6f76692053616464636f6d706845746865726e616c", hex.EncodeToString(txRaw))
+	assert.Equal(t, "1b9298c51f4dc05c04cae37104124cfb76e9f98f04a7f6b8179cfe02913152ec", txHash)
+}
+
+func Test_TxBuilder_BuildNativeConwayWithMint(t *testing.T) {
+	t.Parallel()
+
+	policyScript := NewPolicyScript([]string{"d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21"}, 1)
+	policyID := "29f8873beb52e126f207a2dfd50f7cff556806b5b4cba9834a7b26a8"
+	tokens := []TokenAmount{
+		NewTokenAmount(NewToken(policyID, "Route345"), 100),
+		NewTokenAmount(NewToken(policyID, "Route3"), 200),
+	}
+
+	builder, err := NewTxBuilder(ResolveCardanoCliBinary(TestNetNetwork))
+	require.NoError(t, err)
+
+	defer builder.Dispose()
+
+	builder.SetProtocolParameters(conwayProtocolParameters).SetFee(200_000)
+	builder.AddInputs(NewTxInput("e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f", 1))
+	builder.AddOutputs(NewTxOutput(
+		"addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u", 1_000_000, tokens...))
+	builder.AddTokenMints([]IPolicyScript{policyScript}, tokens)
+
+	txRaw, txHash, err := builder.Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, "84a400d9010281825820e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f01018182581d60244877c1aeefc7fd5405a6e14d927d91758d45e37c20fa2ac89cb167821a000f4240a1581c29f8873beb52e126f207a2dfd50f7cff556806b5b4cba9834a7b26a8a246526f7574653318c848526f7574653334351864021a00030d4009a1581c29f8873beb52e126f207a2dfd50f7cff556806b5b4cba9834a7b26a8a246526f7574653318c848526f7574653334351864a101d9010281830301818200581cd6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21f5f6", hex.EncodeToString(txRaw))
+	assert.Len(t, txHash, TxHashSize*2)
+}
+
+func Test_TxBuilder_BurnTokens(t *testing.T) {
+	t.Parallel()
+
+	policyScript := NewPolicyScript([]string{"d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21"}, 1)
+
+	policyID, err := policyScript.PolicyID()
+	require.NoError(t, err)
+
+	wrapped, other := NewToken(policyID, "wETH"), NewToken(policyID, "Route3")
+
+	builder := newTestTxBuilder(t, protocolParameters)
+	builder.BurnTokens([]IPolicyScript{policyScript}, []TokenAmount{NewTokenAmount(wrapped, 40)})
+	// minted and burned in the same tx nets to zero
+	builder.AddTokenMints(nil, []TokenAmount{NewTokenAmount(other, 10)})
+	builder.BurnTokens(nil, []TokenAmount{NewTokenAmount(other, 10)})
+
+	minted, burned, err := builder.GetMintedAndBurnedTokens()
+	require.NoError(t, err)
+
+	assert.Empty(t, minted)
+	assert.Equal(t, map[string]uint64{wrapped.String(): 40}, burned)
+
+	err = builder.CheckBurnedTokens(map[string]uint64{AdaTokenName: 3_000_000, wrapped.String(): 39})
+	require.ErrorIs(t, err, ErrBurnedTokensNotInInputs)
+
+	_, err = builder.CreateTxOutputChange(NewTxOutput(testAddr, 0),
+		map[string]uint64{AdaTokenName: 3_000_000}, map[string]uint64{AdaTokenName: 1_200_000})
+	require.ErrorIs(t, err, ErrBurnedTokensNotInInputs)
+
+	change, err := builder.CreateTxOutputChange(NewTxOutput(testAddr, 0),
+		map[string]uint64{AdaTokenName: 3_000_000, wrapped.String(): 100},
+		map[string]uint64{AdaTokenName: 1_200_000})
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(1_800_000), change.Amount)
+	assert.Equal(t, []TokenAmount{NewTokenAmount(wrapped, 60)}, change.Tokens)
+
+	txRaw, _, err := builder.Build()
+	require.NoError(t, err)
+
+	tx, err := NewTransaction(txRaw)
+	require.NoError(t, err)
+
+	assert.Equal(t, []MintTokenAmount{NewMintTokenAmount(wrapped, -40)}, tx.Mint)
+	assert.Equal(t, "-40 "+policyID+"."+hex.EncodeToString([]byte("wETH")), tx.Mint[0].String())
+
+	t.Run("amount out of range", func(t *testing.T) {
+		t.Parallel()
+
+		builder := newTestTxBuilder(t, protocolParameters)
+		builder.BurnTokens([]IPolicyScript{policyScript}, []TokenAmount{NewTokenAmount(wrapped, math.MaxInt64+1)})
+
+		_, _, err := builder.Build()
+		require.ErrorIs(t, err, ErrMintAmountOutOfRange)
+	})
+
+	t.Run("sum overflows", func(t *testing.T) {
+		t.Parallel()
+
+		builder := newTestTxBuilder(t, protocolParameters)
+		builder.AddTokenMints([]IPolicyScript{policyScript},
+			[]TokenAmount{NewTokenAmount(wrapped, math.MaxInt64), NewTokenAmount(wrapped, 1)})
+
+		_, _, err := builder.GetMintedAndBurnedTokens()
+		require.ErrorIs(t, err, ErrMintAmountOutOfRange)
+
+		_, err = builder.CalculateFee(1)
+		require.ErrorIs(t, err, ErrMintAmountOutOfRange)
+
+		_, _, err = builder.Build()
+		require.ErrorIs(t, err, ErrMintAmountOutOfRange)
+	})
+}
+
+func Test_TxBuilder_TimeLockedScripts(t *testing.T) {
+	t.Parallel()
+
+	const keyHash = "d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21"
+
+	vestingScript := NewPolicyScriptAll(NewPolicyScriptSig(keyHash), NewPolicyScriptAfter(1_000))
+	mintScript := NewPolicyScriptAll(NewPolicyScriptSig(keyHash), NewPolicyScriptBefore(5_000))
+
+	mintPolicyID, err := mintScript.PolicyID()
+	require.NoError(t, err)
+
+	newBuilder := func(t *testing.T) *TxBuilder {
+		t.Helper()
+
+		builder := newTestTxBuilder(t, protocolParameters)
+		// validity interval comes from the time locks
+		builder.SetTimeToLive(0)
+		builder.AddInputsWithScript(vestingScript, NewTxInput(testInputHash, 1))
+		builder.AddTokenMints([]IPolicyScript{mintScript},
+			[]TokenAmount{NewTokenAmount(NewToken(mintPolicyID, "Route3"), 10)})
+
+		return builder
+	}
+
+	t.Run("validity interval set from time locks", func(t *testing.T) {
+		t.Parallel()
+
+		builder := newBuilder(t)
+
+		_, err := builder.CalculateFee(0)
+		require.NoError(t, err)
+
+		txRaw, _, err := builder.Build()
+		require.NoError(t, err)
+
+		tx, err := NewTransaction(txRaw)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(1_000), tx.ValidityStart)
+		assert.Equal(t, uint64(5_000), tx.TimeToLive)
+	})
+
+	t.Run("narrower interval is kept", func(t *testing.T) {
+		t.Parallel()
+
+		builder := newBuilder(t)
+		builder.SetValidityStart(2_000).SetTimeToLive(3_000)
+
+		txRaw, _, err := builder.Build()
+		require.NoError(t, err)
+
+		tx, err := NewTransaction(txRaw)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(2_000), tx.ValidityStart)
+		assert.Equal(t, uint64(3_000), tx.TimeToLive)
+	})
+
+	t.Run("not satisfiable", func(t *testing.T) {
+		t.Parallel()
+
+		builder := newBuilder(t)
+		builder.SetTimeToLive(900)
+
+		_, _, err := builder.Build()
+		require.ErrorIs(t, err, ErrPolicyScriptNotSatisfiable)
+
+		builder = newBuilder(t)
+		builder.AddInputsWithScript(NewPolicyScriptAll(NewPolicyScriptSig(keyHash), NewPolicyScriptAfter(6_000)),
+			NewTxInput(testInputHash, 2))
+
+		_, err = builder.CalculateFee(0)
+		require.ErrorIs(t, err, ErrPolicyScriptNotSatisfiable)
+	})
+}
+
+func Test_TxBuilder_RequiredSigners(t *testing.T) {
+	t.Parallel()
+
+	const otherKeyHash = "d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21"
+
+	wallet, err := GenerateWallet(true)
+	require.NoError(t, err)
+
+	stakeKeyHash, err := GetKeyHash(wallet.StakeVerificationKey)
+	require.NoError(t, err)
+
+	builder := newTestTxBuilder(t, protocolParameters)
+
+	feeWithoutSigners, err := builder.CalculateFee(0)
+	require.NoError(t, err)
+
+	builder.AddRequiredSigners(stakeKeyHash, otherKeyHash, stakeKeyHash)
+
+	// input owner and two required signers
+	witnessCount, err := builder.getWitnessCount()
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, witnessCount)
+
+	fee, err := builder.CalculateFee(0)
+	require.NoError(t, err)
+
+	// two key hashes in the body and two more vkey witnesses
+	assert.Equal(t, feeWithoutSigners+44*(1+1+2*(2+KeyHashSize)+2*vkeyWitnessSize), fee)
+
+	builder.SetFee(fee)
+
+	txRaw, _, err := builder.Build()
+	require.NoError(t, err)
+
+	txSigned, err := builder.SignTx(txRaw, []ITxSigner{wallet})
+	require.NoError(t, err)
+
+	tx, err := NewTransaction(txSigned)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{stakeKeyHash, otherKeyHash}, tx.RequiredSigners)
+	require.Len(t, tx.VKeyWitnesses, 2)
+	assert.ElementsMatch(t, [][]byte{wallet.VerificationKey, wallet.StakeVerificationKey},
+		[][]byte{tx.VKeyWitnesses[0].VKey, tx.VKeyWitnesses[1].VKey})
+
+	builder.AddRequiredSigners("abcd")
+
+	_, _, err = builder.Build()
+	require.ErrorContains(t, err, "invalid required signer key hash")
+}
+
 func Test_TxBuilder_UpdateOutputAmountAndRemoveOutput(t *testing.T) {
 	t.Parallel()
 
@@ -262,9 +569,7 @@ func TestCreateTxWitnessAndAssembleTxWitnesses(t *testing.T) {
 
 	require.Equal(t, witnessData, hex.EncodeToString(txWitnessBytes))
 
-	cliUtils := NewCliUtils(ResolveCardanoCliBinary(TestNetNetwork))
-
-	txHash, err := cliUtils.GetTxHash(txRawBytes)
+	txHash, err := GetTxHash(txRawBytes)
 	require.NoError(t, err)
 
 	witness := TxWitnessRaw(txWitnessBytes)
@@ -285,6 +590,75 @@ func TestCreateTxWitnessAndAssembleTxWitnesses(t *testing.T) {
 	require.Equal(t, txWitness, hex.EncodeToString(txFinal))
 }
 
+func Test_TxBuilder_AssembleTxWitnessesNative(t *testing.T) {
+	t.Parallel()
+
+	wallet1, err := GenerateWallet(false)
+	require.NoError(t, err)
+
+	wallet2, err := GenerateWallet(false)
+	require.NoError(t, err)
+
+	policyScript := NewPolicyScript([]string{"d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21"}, 1)
+	tokens := []TokenAmount{
+		NewTokenAmount(NewToken("29f8873beb52e126f207a2dfd50f7cff556806b5b4cba9834a7b26a8", "Route3"), 200),
+	}
+
+	builder, err := NewTxBuilder(ResolveCardanoCliBinary(TestNetNetwork))
+	require.NoError(t, err)
+
+	defer builder.Dispose()
+
+	builder.SetProtocolParameters(conwayProtocolParameters).SetFee(200_000)
+	builder.AddInputs(NewTxInput("e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f", 1))
+	builder.AddOutputs(NewTxOutput(
+		"addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u", 1_000_000, tokens...))
+	builder.AddTokenMints([]IPolicyScript{policyScript}, tokens)
+
+	txRaw, _, err := builder.Build()
+	require.NoError(t, err)
+
+	witness1, err := builder.CreateTxWitness(txRaw, wallet1)
+	require.NoError(t, err)
+
+	witness2, err := builder.CreateTxWitness(txRaw, wallet2)
+	require.NoError(t, err)
+
+	txSigned, err := builder.AssembleTxWitnesses(txRaw, [][]byte{witness2, witness1, witness1})
+	require.NoError(t, err)
+
+	var tx []cbor.RawMessage
+
+	require.NoError(t, cbor.Unmarshal(txSigned, &tx))
+	require.Len(t, tx, 4)
+
+	var witnessSet map[uint64]cbor.RawMessage
+
+	require.NoError(t, cbor.Unmarshal(tx[1], &witnessSet))
+
+	vkeyWitnesses, isTagged, err := decodeCborSet(witnessSet[0])
+	require.NoError(t, err)
+
+	assert.True(t, isTagged)
+	require.Len(t, vkeyWitnesses, 2)
+
+	nativeScripts, isTagged, err := decodeCborSet(witnessSet[1])
+	require.NoError(t, err)
+
+	assert.True(t, isTagged)
+	require.Len(t, nativeScripts, 1)
+
+	for _, witness := range vkeyWitnesses {
+		require.NoError(t, VerifyTxWitness(txSigned, witness))
+	}
+
+	// assembling already present witness does not change transaction
+	txSignedAgain, err := builder.AssembleTxWitnesses(txSigned, [][]byte{witness1})
+	require.NoError(t, err)
+
+	assert.Equal(t, txSigned, txSignedAgain)
+}
+
 func TestCalculateMinUtxo(t *testing.T) {
 	t.Parallel()
 
@@ -351,3 +725,37 @@ func TestCalculateMinUtxo(t *testing.T) {
 
 	require.Equal(t, uint64(849070), minUtxo)
 }
+
+func Test_TxJSONEra(t *testing.T) {
+	t.Parallel()
+
+	conwayTxRaw, _, err := newTestTxBuilder(t, conwayProtocolParameters).Build()
+	require.NoError(t, err)
+
+	babbageTxRaw, _, err := newTestTxBuilder(t, protocolParameters).Build()
+	require.NoError(t, err)
+
+	// era is taken from the transaction itself so building one transaction does not change json of the other
+	for _, c := range []struct {
+		txRaw       []byte
+		unwitnessed string
+		witnessed   string
+	}{
+		{conwayTxRaw, "Unwitnessed Tx ConwayEra", "Witnessed Tx ConwayEra"},
+		{babbageTxRaw, "Unwitnessed Tx BabbageEra", "Witnessed Tx BabbageEra"},
+	} {
+		unwitnessedJSON, err := transactionUnwitnessedRaw(c.txRaw).ToJSON()
+		require.NoError(t, err)
+
+		witnessedJSON, err := transactionWitnessedRaw(c.txRaw).ToJSON()
+		require.NoError(t, err)
+
+		var unwitnessed, witnessed map[string]string
+
+		require.NoError(t, json.Unmarshal(unwitnessedJSON, &unwitnessed))
+		require.NoError(t, json.Unmarshal(witnessedJSON, &witnessed))
+
+		assert.Equal(t, c.unwitnessed, unwitnessed["type"])
+		assert.Equal(t, c.witnessed, witnessed["type"])
+	}
+}
@@ -0,0 +1,167 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+func NewTxWithdrawal(rewardAddress string, amount uint64) TxWithdrawal {
+	return TxWithdrawal{
+		Address: rewardAddress,
+		Amount:  amount,
+	}
+}
+
+func (w TxWithdrawal) String() string {
+	return fmt.Sprintf("%s+%d", w.Address, w.Amount)
+}
+
+// getStakeCredential returns stake credential of the withdrawal reward address
+func (w TxWithdrawal) getStakeCredential() (*CardanoAddress, TxCredential, error) {
+	addr, err := NewCardanoAddressFromString(w.Address)
+	if err != nil {
+		return nil, TxCredential{}, err
+	}
+
+	info := addr.GetInfo()
+	if info.AddressType != RewardAddress || info.Stake == nil {
+		return nil, TxCredential{}, fmt.Errorf("not a reward address: %s", w.Address)
+	}
+
+	return addr, NewTxCredential(info.Stake.String(), info.Stake.IsScript), nil
+}
+
+type txWithdrawalWithScript struct {
+	withdrawal   TxWithdrawal
+	policyScript IPolicyScript
+}
+
+// AddWithdrawals adds reward withdrawals of key hash stake credentials
+func (b *TxBuilder) AddWithdrawals(withdrawals ...TxWithdrawal) *TxBuilder {
+	for _, withdrawal := range withdrawals {
+		b.withdrawals = append(b.withdrawals, txWithdrawalWithScript{
+			withdrawal: withdrawal,
+		})
+	}
+
+	return b
+}
+
+// AddWithdrawalsWithScript adds reward withdrawals of the native script stake credential
+func (b *TxBuilder) AddWithdrawalsWithScript(script IPolicyScript, withdrawals ...TxWithdrawal) *TxBuilder {
+	for _, withdrawal := range withdrawals {
+		b.withdrawals = append(b.withdrawals, txWithdrawalWithScript{
+			withdrawal:   withdrawal,
+			policyScript: script,
+		})
+	}
+
+	return b
+}
+
+// CreateTxOutputChange creates change output same as the package level CreateTxOutputChange function
+// but withdrawals, refunds and minted tokens are counted as inputs and deposits and burned tokens as outputs
+func (b *TxBuilder) CreateTxOutputChange(
+	baseTxOutput TxOutput, totalSum map[string]uint64, outputsSum map[string]uint64,
+) (TxOutput, error) {
+	if err := b.CheckBurnedTokens(totalSum); err != nil {
+		return TxOutput{}, err
+	}
+
+	consumed, produced, err := b.GetImplicitCoin()
+	if err != nil {
+		return TxOutput{}, err
+	}
+
+	totalSumWithImplicit := make(map[string]uint64, len(totalSum)+1)
+	for tokenName, amount := range totalSum {
+		totalSumWithImplicit[tokenName] = amount
+	}
+
+	outputsSumWithImplicit := make(map[string]uint64, len(outputsSum)+1)
+	for tokenName, amount := range outputsSum {
+		outputsSumWithImplicit[tokenName] = amount
+	}
+
+	totalSumWithImplicit[AdaTokenName] += consumed
+	outputsSumWithImplicit[AdaTokenName] += produced
+
+	minted, burned, err := b.GetMintedAndBurnedTokens()
+	if err != nil {
+		return TxOutput{}, err
+	}
+
+	for tokenName, amount := range minted {
+		totalSumWithImplicit[tokenName] += amount
+	}
+
+	for tokenName, amount := range burned {
+		outputsSumWithImplicit[tokenName] += amount
+	}
+
+	return CreateTxOutputChange(baseTxOutput, totalSumWithImplicit, outputsSumWithImplicit)
+}
+
+func (b *TxBuilder) getWithdrawalsCbor() (cborOrderedMap, error) {
+	withdrawals := make([]TxWithdrawal, len(b.withdrawals))
+	for i, w := range b.withdrawals {
+		withdrawals[i] = w.withdrawal
+	}
+
+	return getTxWithdrawalsCbor(withdrawals)
+}
+
+// getTxWithdrawalsCbor returns reward account => amount map ordered by reward account
+func getTxWithdrawalsCbor(txWithdrawals []TxWithdrawal) (cborOrderedMap, error) {
+	withdrawals := make(cborOrderedMap, 0, len(txWithdrawals))
+	seen := map[string]bool{}
+
+	for _, w := range txWithdrawals {
+		addr, _, err := w.getStakeCredential()
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[string(addr.GetBytes())] {
+			return nil, fmt.Errorf("duplicate withdrawal: %s", w.Address)
+		}
+
+		seen[string(addr.GetBytes())] = true
+		withdrawals = append(withdrawals, cborKeyValue{Key: addr.GetBytes(), Value: w.Amount})
+	}
+
+	sort.Slice(withdrawals, func(i, j int) bool {
+		return bytes.Compare(withdrawals[i].Key.([]byte), withdrawals[j].Key.([]byte)) < 0 //nolint:forcetypeassert
+	})
+
+	return withdrawals, nil
+}
+
+// applyWithdrawals adds cardano-cli build-raw arguments for withdrawals
+func (b *TxBuilder) applyWithdrawals(args *[]string) error {
+	for i, w := range b.withdrawals {
+		*args = append(*args, "--withdrawal", w.withdrawal.String())
+
+		if w.policyScript == nil {
+			continue
+		}
+
+		policyScriptJSON, err := w.policyScript.GetPolicyScriptJSON()
+		if err != nil {
+			return err
+		}
+
+		policyFilePath := filepath.Join(b.baseDirectory, "withdrawal_policy_"+strconv.Itoa(i)+".json")
+		if err := os.WriteFile(policyFilePath, policyScriptJSON, FilePermission); err != nil {
+			return err
+		}
+
+		*args = append(*args, "--withdrawal-script-file", policyFilePath)
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/igorcrevar/go-cardano-tx/core/clitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TxBuilder_Withdrawals(t *testing.T) {
+	t.Parallel()
+
+	wallet, err := GenerateWallet(true)
+	require.NoError(t, err)
+
+	rewardAddress, err := NewRewardAddress(TestNetNetwork, wallet.StakeVerificationKey)
+	require.NoError(t, err)
+
+	policyScript := NewPolicyScript([]string{
+		"2411b7ef3c33d35c2f1e1a2fa4ef0a63e1aa5fe9ac9cd8ca4d1c1b09",
+		"9fd3c6e25d8d4e06e6d5f40a12c4e1fc7ae53aa0ebeb14a4e8d5d8a5",
+	}, 1)
+
+	policyID, err := policyScript.PolicyID()
+	require.NoError(t, err)
+
+	scriptRewardAddress, err := CardanoAddressInfo{
+		AddressType: RewardAddress,
+		Network:     TestNetNetwork,
+		Stake: &CardanoAddressPayload{
+			Payload:  [KeyHashSize]byte(mustDecodeHex(t, policyID)),
+			IsScript: true,
+		},
+	}.ToCardanoAddress()
+	require.NoError(t, err)
+
+	t.Run("key credential", func(t *testing.T) {
+		t.Parallel()
+
+		builder := newTestTxBuilder(t, protocolParameters)
+		builder.AddWithdrawals(NewTxWithdrawal(rewardAddress.String(), 1_500_000))
+
+		consumed, produced, err := builder.GetImplicitCoin()
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(1_500_000), consumed)
+		assert.Equal(t, uint64(0), produced)
+
+		change, err := builder.CreateTxOutputChange(NewTxOutput(testAddr, 0),
+			map[string]uint64{AdaTokenName: 3_000_000}, map[string]uint64{AdaTokenName: 2_200_000})
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(2_300_000), change.Amount)
+
+		// payment key and stake key
+		witnessCount, err := builder.getWitnessCount()
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, witnessCount)
+
+		txRaw, _, err := builder.Build()
+		require.NoError(t, err)
+
+		txSigned, err := builder.SignTx(txRaw, []ITxSigner{wallet})
+		require.NoError(t, err)
+
+		tx, err := NewTransaction(txSigned)
+		require.NoError(t, err)
+
+		assert.Equal(t, []TxWithdrawal{NewTxWithdrawal(rewardAddress.String(), 1_500_000)}, tx.Withdrawals)
+		require.Len(t, tx.VKeyWitnesses, 2)
+		assert.ElementsMatch(t, [][]byte{wallet.VerificationKey, wallet.StakeVerificationKey},
+			[][]byte{tx.VKeyWitnesses[0].VKey, tx.VKeyWitnesses[1].VKey})
+	})
+
+	t.Run("script credential", func(t *testing.T) {
+		t.Parallel()
+
+		builder := newTestTxBuilder(t, protocolParameters)
+		builder.AddWithdrawals(NewTxWithdrawal(rewardAddress.String(), 1))
+		builder.AddWithdrawalsWithScript(policyScript, NewTxWithdrawal(scriptRewardAddress.String(), 2))
+
+		// payment key, stake key and both keys of the script
+		witnessCount, err := builder.getWitnessCount()
+		require.NoError(t, err)
+
+		assert.Equal(t, 4, witnessCount)
+
+		txRaw, _, err := builder.Build()
+		require.NoError(t, err)
+
+		tx, err := NewTransaction(txRaw)
+		require.NoError(t, err)
+
+		require.Len(t, tx.NativeScripts, 1)
+		assert.ElementsMatch(t, []TxWithdrawal{
+			NewTxWithdrawal(rewardAddress.String(), 1),
+			NewTxWithdrawal(scriptRewardAddress.String(), 2),
+		}, tx.Withdrawals)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		builder := newTestTxBuilder(t, protocolParameters)
+		builder.AddWithdrawals(NewTxWithdrawal(testAddr, 1))
+
+		_, _, err := builder.Build()
+		require.ErrorContains(t, err, "not a reward address")
+
+		builder = newTestTxBuilder(t, protocolParameters)
+		builder.AddWithdrawals(NewTxWithdrawal(rewardAddress.String(), 1), NewTxWithdrawal(rewardAddress.String(), 2))
+
+		_, _, err = builder.Build()
+		require.ErrorContains(t, err, "duplicate withdrawal")
+	})
+
+	t.Run("cardano-cli", func(t *testing.T) {
+		t.Parallel()
+
+		var builder *TxBuilder
+
+		fakeCli := clitest.NewFakeCardanoCli().
+			HandleFunc(func(call clitest.Call) clitest.Response {
+				txRaw, _, err := builder.buildRawTxNative(0)
+				if err != nil {
+					return clitest.Response{Err: err}
+				}
+
+				return clitest.Response{Files: map[string][]byte{
+					"--out-file": clitest.TextEnvelope("Unwitnessed Tx BabbageEra", txRaw),
+				}}
+			}, "transaction", "build-raw").
+			Handle(clitest.Response{Stdout: strings.Repeat("ab", 32)}, "transaction", "txid").
+			Handle(clitest.Response{Stdout: "170000 Lovelace\n"}, "transaction", "calculate-min-fee")
+
+		builder = newTestTxBuilder(t, protocolParameters, WithCardanoCliBuild(WithCommandRunner(fakeCli)))
+		builder.AddWithdrawalsWithScript(policyScript, NewTxWithdrawal(scriptRewardAddress.String(), 2))
+		builder.AddWithdrawals(NewTxWithdrawal(rewardAddress.String(), 3))
+
+		_, err := builder.CalculateFee(0)
+		require.NoError(t, err)
+
+		feeCalls := fakeCli.CallsOf("transaction", "calculate-min-fee")
+		require.Len(t, feeCalls, 1)
+
+		// payment key, stake key of the reward address and both keys of the withdrawal script
+		assert.Equal(t, "4", feeCalls[0].Flag("--witness-count"))
+
+		_, _, err = builder.Build()
+		require.NoError(t, err)
+
+		calls := fakeCli.CallsOf("transaction", "build-raw")
+		require.Len(t, calls, 2)
+
+		assert.ElementsMatch(t, []string{
+			scriptRewardAddress.String() + "+2", rewardAddress.String() + "+3",
+		}, calls[1].Flags("--withdrawal"))
+		assert.True(t, calls[1].HasFlag("--withdrawal-script-file"))
+	})
+}
@@ -280,6 +280,8 @@ func convertProtocolParameters(bytes []byte) ([]byte, error) {
 		MaxCollateralInputs uint64                      `json:"max_collateral_inputs"`
 		MaxValSize          string                      `json:"max_val_size"`
 		CostModels          map[string]map[string]int64 `json:"cost_models"`
+
+		MinFeeRefScriptCostPerByte float64 `json:"min_fee_ref_script_cost_per_byte"`
 	}
 
 	if err := json.Unmarshal(bytes, &bfpp); err != nil {
@@ -319,6 +321,8 @@ func convertProtocolParameters(bytes []byte) ([]byte, error) {
 		MaxCollateralInputs: bfpp.MaxCollateralInputs,
 		MaxValueSize:        strToUInt64(bfpp.MaxValSize),
 		CostModels:          map[string][]int64{},
+
+		MinFeeRefScriptCostPerByte: bfpp.MinFeeRefScriptCostPerByte,
 	}
 
 	for scriptName, mapValue := range bfpp.CostModels {
@@ -12,25 +12,27 @@ import (
 )
 
 type TxProviderCli struct {
-	baseDirectory    string
-	testNetMagic     uint
-	socketPath       string
-	cardanoCliBinary string
+	baseDirectory string
+	testNetMagic  uint
+	socketPath    string
+	cli           cardanoCli
 }
 
 var _ ITxProvider = (*TxProviderCli)(nil)
 
-func NewTxProviderCli(testNetMagic uint, socketPath string, cardanoCliBinary string) (*TxProviderCli, error) {
+func NewTxProviderCli(
+	testNetMagic uint, socketPath string, cardanoCliBinary string, options ...CardanoCliOption,
+) (*TxProviderCli, error) {
 	baseDirectory, err := os.MkdirTemp("", "cardano-txs")
 	if err != nil {
 		return nil, err
 	}
 
 	return &TxProviderCli{
-		baseDirectory:    baseDirectory,
-		testNetMagic:     testNetMagic,
-		socketPath:       socketPath,
-		cardanoCliBinary: cardanoCliBinary,
+		baseDirectory: baseDirectory,
+		testNetMagic:  testNetMagic,
+		socketPath:    socketPath,
+		cli:           newCardanoCli(cardanoCliBinary, options...),
 	}, nil
 }
 
@@ -44,7 +46,7 @@ func (b *TxProviderCli) GetProtocolParameters(_ context.Context) ([]byte, error)
 		"--socket-path", b.socketPath,
 	}, getTestNetMagicArgs(b.testNetMagic)...)
 
-	response, err := runCommand(b.cardanoCliBinary, args)
+	response, err := b.cli.run(args)
 	if err != nil {
 		return nil, err
 	}
@@ -59,7 +61,7 @@ func (b *TxProviderCli) GetUtxos(_ context.Context, addr string) ([]Utxo, error)
 		"--address", addr,
 	}, getTestNetMagicArgs(b.testNetMagic)...)
 
-	output, err := runCommand(b.cardanoCliBinary, args)
+	output, err := b.cli.run(args)
 	if err != nil {
 		return nil, err
 	}
@@ -131,7 +133,7 @@ func (b *TxProviderCli) GetTip(_ context.Context) (QueryTipData, error) {
 		"--socket-path", b.socketPath,
 	}, getTestNetMagicArgs(b.testNetMagic)...)
 
-	res, err := runCommand(b.cardanoCliBinary, args)
+	res, err := b.cli.run(args)
 	if err != nil {
 		return QueryTipData{}, err
 	}
@@ -163,7 +165,7 @@ func (b *TxProviderCli) SubmitTx(_ context.Context, txSigned []byte) error {
 		"--tx-file", txFilePath,
 	}, getTestNetMagicArgs(b.testNetMagic)...)
 
-	res, err := runCommand(b.cardanoCliBinary, args)
+	res, err := b.cli.run(args)
 	if err != nil {
 		return err
 	}
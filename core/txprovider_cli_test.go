@@ -0,0 +1,99 @@
+package core
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/igorcrevar/go-cardano-tx/core/clitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxProviderCli_FakeCli(t *testing.T) {
+	t.Parallel()
+
+	const (
+		socketPath = "/tmp/node.socket"
+		addr       = "addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u"
+		txHash     = "e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f"
+		policyID   = "29f8873beb52e126f207a2dfd50f7cff556806b5b4cba9834a7b26a8"
+	)
+
+	fakeCli := clitest.NewFakeCardanoCli().
+		Handle(clitest.Response{Stdout: clitest.QueryUtxoOutput(
+			clitest.UtxoRow{TxHash: txHash, TxIx: 0, Lovelace: 1_000_000},
+			clitest.UtxoRow{TxHash: txHash, TxIx: 2, Lovelace: 3_000_000, Assets: []string{
+				"10 " + policyID + ".526f75746533",
+			}},
+		)}, "query", "utxo").
+		Handle(clitest.Response{Stdout: clitest.QueryTipOutput(101, 7, 3_000_123, "Conway")}, "query", "tip").
+		Handle(clitest.Response{Stdout: string(protocolParameters)}, "query", "protocol-parameters").
+		HandleFunc(func(call clitest.Call) clitest.Response {
+			content, err := os.ReadFile(call.Flag("--tx-file"))
+			if err != nil {
+				return clitest.Response{Err: err}
+			}
+
+			if _, err := clitest.ReadTextEnvelope(content); err != nil {
+				return clitest.Response{Err: err}
+			}
+
+			return clitest.Response{Stdout: "Transaction successfully submitted.\n"}
+		}, "transaction", "submit")
+
+	provider, err := NewTxProviderCli(2, socketPath, "cardano-cli-fake", WithCommandRunner(fakeCli))
+	require.NoError(t, err)
+
+	defer provider.Dispose()
+
+	utxos, err := provider.GetUtxos(context.Background(), addr)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Utxo{
+		{Hash: txHash, Index: 0, Amount: 1_000_000},
+		{Hash: txHash, Index: 2, Amount: 3_000_000, Tokens: []TokenAmount{
+			{Token: NewToken(policyID, "Route3"), Amount: 10},
+		}},
+	}, utxos)
+
+	tip, err := provider.GetTip(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(101), tip.Block)
+	assert.Equal(t, uint64(3_000_123), tip.Slot)
+
+	params, err := provider.GetProtocolParameters(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, protocolParameters, params)
+
+	require.NoError(t, provider.SubmitTx(context.Background(), []byte{0x84, 0xa0, 0xa0, 0xf5, 0xf6}))
+
+	calls := fakeCli.Calls()
+	require.Len(t, calls, 4)
+
+	for _, call := range calls {
+		assert.Equal(t, "cardano-cli-fake", call.Binary)
+		assert.Equal(t, socketPath, call.Flag("--socket-path"))
+		assert.Equal(t, "2", call.Flag("--testnet-magic"))
+	}
+
+	assert.Equal(t, addr, calls[0].Flag("--address"))
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		provider, err := NewTxProviderCli(MainNetProtocolMagic, socketPath, "cardano-cli-fake",
+			WithCommandRunner(clitest.NewFakeCardanoCli().
+				Handle(clitest.Response{Stdout: "Command failed"}, "transaction", "submit")))
+		require.NoError(t, err)
+
+		defer provider.Dispose()
+
+		_, err = provider.GetTip(context.Background())
+		require.ErrorContains(t, err, "unexpected command: query tip")
+
+		require.ErrorContains(t, provider.SubmitTx(context.Background(), []byte{0x80}), "Command failed")
+	})
+}
@@ -16,11 +16,36 @@ const (
 	adaTokenPolicyID     = "ada"
 )
 
+// OgmiosError is json-rpc error returned by ogmios (for example script evaluation failure)
+type OgmiosError struct {
+	StatusCode int
+	Code       int
+	Message    string
+	Data       json.RawMessage
+}
+
+func (e *OgmiosError) Error() string {
+	return fmt.Sprintf("status code %d: %s", e.StatusCode, e.Message)
+}
+
+// ogmiosRedeemerPurposes maps ogmios validator purpose to redeemer tag
+var ogmiosRedeemerPurposes = map[string]TxRedeemerTag{
+	"spend":    RedeemerTagSpend,
+	"mint":     RedeemerTagMint,
+	"publish":  RedeemerTagCert,
+	"withdraw": RedeemerTagReward,
+	"vote":     RedeemerTagVoting,
+	"propose":  RedeemerTagPropose,
+}
+
 type TxProviderOgmios struct {
 	url string
 }
 
-var _ ITxProvider = (*TxProviderOgmios)(nil)
+var (
+	_ ITxProvider  = (*TxProviderOgmios)(nil)
+	_ ITxEvaluator = (*TxProviderOgmios)(nil)
+)
 
 func NewTxProviderOgmios(url string) *TxProviderOgmios {
 	return &TxProviderOgmios{
@@ -85,6 +110,10 @@ func (o *TxProviderOgmios) GetProtocolParameters(ctx context.Context) ([]byte, e
 		MaxCollateralInputs: params.Result.MaxCollateralInputs,
 		MaxValueSize:        params.Result.MaxValueSize.Bytes,
 		CostModels:          map[string][]int64{},
+
+		MinFeeRefScriptCostPerByte: params.Result.MinFeeReferenceScripts.Base,
+		MinFeeRefScriptRange:       params.Result.MinFeeReferenceScripts.Range,
+		MinFeeRefScriptMultiplier:  params.Result.MinFeeReferenceScripts.Multiplier,
 	}
 
 	for scriptName, values := range params.Result.PlutusCostModels {
@@ -205,6 +234,47 @@ func (o *TxProviderOgmios) SubmitTx(ctx context.Context, txSigned []byte) error
 	return nil
 }
 
+// EvaluateTx implements ITxEvaluator.
+func (o *TxProviderOgmios) EvaluateTx(ctx context.Context, txRaw []byte) (map[TxRedeemerPointer]TxExUnits, error) {
+	response, err := executeHTTPOgmios[ogmiosEvaluateTransactionResponse](
+		ctx, o.url, ogmiosEvaluateTransaction{
+			Jsonrpc: ogmiosJSONRPCVersion,
+			Method:  "evaluateTransaction",
+			Params: ogmiosSubmitTransactionParams{
+				Transaction: ogmiosSubmitTransactionParamsTransaction{
+					CBOR: hex.EncodeToString(txRaw),
+				},
+			},
+			ID: nil,
+		}, false,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, &OgmiosError{
+			StatusCode: http.StatusOK,
+			Code:       response.Error.Code,
+			Message:    response.Error.Message,
+			Data:       response.Error.Data,
+		}
+	}
+
+	result := make(map[TxRedeemerPointer]TxExUnits, len(response.Result))
+
+	for _, x := range response.Result {
+		tag, exists := ogmiosRedeemerPurposes[x.Validator.Purpose]
+		if !exists {
+			return nil, fmt.Errorf("unknown ogmios validator purpose: %s", x.Validator.Purpose)
+		}
+
+		result[TxRedeemerPointer{Tag: tag, Index: x.Validator.Index}] = NewTxExUnits(x.Budget.Memory, x.Budget.CPU)
+	}
+
+	return result, nil
+}
+
 func (o *TxProviderOgmios) GetTxByHash(ctx context.Context, hash string) (map[string]interface{}, error) {
 	panic("not implemented") //nolint:gocritic
 }
@@ -247,12 +317,18 @@ func executeHTTPOgmios[T any](
 }
 
 func getErrorFromResponseOgmios(resp *http.Response) error {
-	var responseData map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&responseData); err != nil {
-		return fmt.Errorf("status code %d", resp.StatusCode)
+	var responseData struct {
+		Error *ogmiosError `json:"error"`
 	}
 
-	msg := responseData["error"].(map[string]interface{})["message"].(string) //nolint:forcetypeassert
+	if err := json.NewDecoder(resp.Body).Decode(&responseData); err != nil || responseData.Error == nil {
+		return fmt.Errorf("status code %d", resp.StatusCode)
+	}
 
-	return fmt.Errorf("status code %d: %s", resp.StatusCode, msg)
+	return &OgmiosError{
+		StatusCode: resp.StatusCode,
+		Code:       responseData.Error.Code,
+		Message:    responseData.Error.Message,
+		Data:       responseData.Error.Data,
+	}
 }
@@ -0,0 +1,85 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxProviderOgmios_EvaluateTx(t *testing.T) {
+	t.Parallel()
+
+	newServer := func(t *testing.T, statusCode int, response string) *httptest.Server {
+		t.Helper()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var request map[string]interface{}
+
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+			assert.Equal(t, "evaluateTransaction", request["method"])
+			assert.Equal(t, map[string]interface{}{
+				"transaction": map[string]interface{}{"cbor": "84a0a0f5f6"},
+			}, request["params"])
+
+			w.WriteHeader(statusCode)
+			_, _ = w.Write([]byte(response))
+		}))
+
+		t.Cleanup(server.Close)
+
+		return server
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		server := newServer(t, http.StatusOK, `{"jsonrpc":"2.0","method":"evaluateTransaction","result":[
+			{"validator":{"index":1,"purpose":"spend"},"budget":{"memory":1700,"cpu":476468}},
+			{"validator":{"index":0,"purpose":"mint"},"budget":{"memory":2000,"cpu":500000}}
+		]}`)
+
+		exUnits, err := NewTxProviderOgmios(server.URL).EvaluateTx(context.Background(), []byte{0x84, 0xa0, 0xa0, 0xf5, 0xf6})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[TxRedeemerPointer]TxExUnits{
+			{Tag: RedeemerTagSpend, Index: 1}: NewTxExUnits(1700, 476468),
+			{Tag: RedeemerTagMint, Index: 0}:  NewTxExUnits(2000, 500000),
+		}, exUnits)
+	})
+
+	t.Run("script failure", func(t *testing.T) {
+		t.Parallel()
+
+		server := newServer(t, http.StatusBadRequest, `{"jsonrpc":"2.0","method":"evaluateTransaction","error":{
+			"code":3010,"message":"Some scripts of the transactions terminated with error(s).",
+			"data":[{"validator":{"index":1,"purpose":"spend"},"error":{"code":3012,"message":"validation failure"}}]
+		}}`)
+
+		_, err := NewTxProviderOgmios(server.URL).EvaluateTx(context.Background(), []byte{0x84, 0xa0, 0xa0, 0xf5, 0xf6})
+
+		var ogmiosErr *OgmiosError
+
+		require.True(t, errors.As(err, &ogmiosErr))
+		assert.Equal(t, http.StatusBadRequest, ogmiosErr.StatusCode)
+		assert.Equal(t, 3010, ogmiosErr.Code)
+		assert.Contains(t, string(ogmiosErr.Data), "validation failure")
+		assert.Equal(t, "status code 400: Some scripts of the transactions terminated with error(s).", err.Error())
+	})
+
+	t.Run("unknown purpose", func(t *testing.T) {
+		t.Parallel()
+
+		server := newServer(t, http.StatusOK, `{"jsonrpc":"2.0","method":"evaluateTransaction","result":[
+			{"validator":{"index":0,"purpose":"unknown"},"budget":{"memory":1,"cpu":1}}
+		]}`)
+
+		_, err := NewTxProviderOgmios(server.URL).EvaluateTx(context.Background(), []byte{0x84, 0xa0, 0xa0, 0xf5, 0xf6})
+		require.ErrorContains(t, err, "unknown ogmios validator purpose")
+	})
+}
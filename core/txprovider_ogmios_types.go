@@ -1,5 +1,7 @@
 package core
 
+import "encoding/json"
+
 type ogmiosQueryStateRequest struct {
 	Jsonrpc string      `json:"jsonrpc"`
 	Method  string      `json:"method"`
@@ -92,6 +94,11 @@ type ogmiosQueryProtocolParamsResponse struct {
 		MaxValueSize struct {
 			Bytes uint64 `json:"bytes"`
 		} `json:"maxValueSize"`
+		MinFeeReferenceScripts struct {
+			Range      uint64  `json:"range"`
+			Base       float64 `json:"base"`
+			Multiplier float64 `json:"multiplier"`
+		} `json:"minFeeReferenceScripts"`
 		CollateralPercentage uint64 `json:"collateralPercentage"`
 		MaxCollateralInputs  uint64 `json:"maxCollateralInputs"`
 		Version              struct {
@@ -152,3 +159,33 @@ type ogmiosQueryNetworkBlockHeightResponse struct {
 	Result  uint64      `json:"result"`
 	ID      interface{} `json:"id"`
 }
+
+type ogmiosEvaluateTransaction struct {
+	Jsonrpc string                        `json:"jsonrpc"`
+	Method  string                        `json:"method"`
+	Params  ogmiosSubmitTransactionParams `json:"params"`
+	ID      interface{}                   `json:"id"`
+}
+
+type ogmiosError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+type ogmiosEvaluateTransactionResponse struct {
+	Jsonrpc string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Result  []struct {
+		Validator struct {
+			Index   uint32 `json:"index"`
+			Purpose string `json:"purpose"`
+		} `json:"validator"`
+		Budget struct {
+			Memory uint64 `json:"memory"`
+			CPU    uint64 `json:"cpu"`
+		} `json:"budget"`
+	} `json:"result"`
+	Error *ogmiosError `json:"error"`
+	ID    interface{}  `json:"id"`
+}
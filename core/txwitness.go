@@ -3,24 +3,30 @@ package core
 import (
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 
 	"github.com/fxamacker/cbor/v2"
 )
 
-var (
-	witnessJSONType       = "TxWitness BabbageEra"
-	witnessJSONDesc       = "Key Witness ShelleyEra"
-	txUnwitnessedJSONType = "Unwitnessed Tx BabbageEra"
-	txUnwitnessedJSONDesc = "Ledger Cddl Format"
-	txWitnessedJSONType   = "Witnessed Tx BabbageEra"
-	txWitnessedJSONDesc   = "Ledger Cddl Format"
+const (
+	witnessJSONTypeFormat       = "TxWitness %sEra"
+	witnessJSONDesc             = "Key Witness ShelleyEra"
+	txUnwitnessedJSONTypeFormat = "Unwitnessed Tx %sEra"
+	txUnwitnessedJSONDesc       = "Ledger Cddl Format"
+	txWitnessedJSONTypeFormat   = "Witnessed Tx %sEra"
+	txWitnessedJSONDesc         = "Ledger Cddl Format"
 )
 
 type TxWitnessRaw []byte // cbor slice of bytes
 
 func (w TxWitnessRaw) ToJSON() ([]byte, error) {
+	return w.toJSONInEra(babbageEraName)
+}
+
+// toJSONInEra returns json of witness for transaction of eraName era
+func (w TxWitnessRaw) toJSONInEra(eraName string) ([]byte, error) {
 	return json.Marshal(map[string]interface{}{
-		"type":        witnessJSONType,
+		"type":        fmt.Sprintf(witnessJSONTypeFormat, eraName),
 		"description": witnessJSONDesc,
 		"cborHex":     hex.EncodeToString(w),
 	})
@@ -45,17 +51,12 @@ func newTransactionUnwitnessedRawFromJSON(bytes []byte) (transactionUnwitnessedR
 		return nil, err
 	}
 
-	// a little hack so we have always correct witness key and description for json
-	// (cardano-cli can return error otherwise)
-	txUnwitnessedJSONType = data["type"].(string)        //nolint:forcetypeassert
-	txUnwitnessedJSONDesc = data["description"].(string) //nolint:forcetypeassert
-
 	return hex.DecodeString(data["cborHex"].(string)) //nolint:forcetypeassert
 }
 
 func (tx transactionUnwitnessedRaw) ToJSON() ([]byte, error) {
 	return json.Marshal(map[string]interface{}{
-		"type":        txUnwitnessedJSONType,
+		"type":        fmt.Sprintf(txUnwitnessedJSONTypeFormat, getTxEraName(tx)),
 		"description": txUnwitnessedJSONDesc,
 		"cborHex":     hex.EncodeToString(tx),
 	})
@@ -70,18 +71,29 @@ func newTransactionWitnessedRawFromJSON(bytes []byte) (transactionWitnessedRaw,
 		return nil, err
 	}
 
-	// a little hack so we have always correct witness key and description for json
-	// (cardano-cli can return error otherwise)
-	txWitnessedJSONType = data["type"].(string)        //nolint:forcetypeassert
-	txWitnessedJSONDesc = data["description"].(string) //nolint:forcetypeassert
-
 	return hex.DecodeString(data["cborHex"].(string)) //nolint:forcetypeassert
 }
 
 func (tx transactionWitnessedRaw) ToJSON() ([]byte, error) {
 	return json.Marshal(map[string]interface{}{
-		"type":        txWitnessedJSONType,
+		"type":        fmt.Sprintf(txWitnessedJSONTypeFormat, getTxEraName(tx)),
 		"description": txWitnessedJSONDesc,
 		"cborHex":     hex.EncodeToString(tx),
 	})
 }
+
+// getTxEraName returns era name of the transaction (json type must match it or cardano-cli returns error).
+// Transaction body with inputs tagged as set is conway era transaction
+func getTxEraName(txRaw []byte) string {
+	var tx []cbor.RawMessage
+
+	if err := cbor.Unmarshal(txRaw, &tx); err != nil || len(tx) == 0 {
+		return babbageEraName
+	}
+
+	if isConway, err := isTxBodyWithTaggedSets(tx[0]); err == nil && isConway {
+		return conwayEraName
+	}
+
+	return babbageEraName
+}
@@ -21,7 +21,10 @@ type Wallet struct {
 	StakeSigningKey      []byte `json:"sstake"`
 }
 
-var _ ITxSigner = (*Wallet)(nil)
+var (
+	_ ITxSigner      = (*Wallet)(nil)
+	_ ITxStakeSigner = (*Wallet)(nil)
+)
 
 func NewWallet(signingKey, stakeSigningKey []byte) *Wallet {
 	getVerificationKey := func(signingKey []byte) []byte {
@@ -89,6 +92,63 @@ func (w Wallet) GetPaymentKeys() ([]byte, []byte) {
 	return w.SigningKey, w.VerificationKey
 }
 
+func (w Wallet) GetStakeKeys() ([]byte, []byte) {
+	return w.StakeSigningKey, w.StakeVerificationKey
+}
+
+// DRepWallet holds delegated representative keys. It witnesses drep certificates as any other signer
+type DRepWallet struct {
+	VerificationKey []byte `json:"vkey"`
+	SigningKey      []byte `json:"skey"`
+}
+
+var _ ITxSigner = (*DRepWallet)(nil)
+
+func NewDRepWallet(signingKey []byte) *DRepWallet {
+	wallet := NewWallet(signingKey, nil)
+
+	return &DRepWallet{
+		SigningKey:      wallet.SigningKey,
+		VerificationKey: wallet.VerificationKey,
+	}
+}
+
+// GenerateDRepWallet generates drep wallet
+func GenerateDRepWallet() (*DRepWallet, error) {
+	signingKey, verificationKey, err := GenerateKeyPair()
+	if err != nil {
+		return nil, err
+	}
+
+	return &DRepWallet{
+		SigningKey:      PadKeyToSize(signingKey),
+		VerificationKey: PadKeyToSize(verificationKey),
+	}, nil
+}
+
+func (w DRepWallet) CreateTxWitness(txHash []byte) ([]byte, error) {
+	return Wallet{SigningKey: w.SigningKey, VerificationKey: w.VerificationKey}.CreateTxWitness(txHash)
+}
+
+func (w DRepWallet) GetPaymentKeys() ([]byte, []byte) {
+	return w.SigningKey, w.VerificationKey
+}
+
+// GetCredential returns drep key hash credential
+func (w DRepWallet) GetCredential() (TxCredential, error) {
+	return NewTxCredentialFromKey(w.VerificationKey)
+}
+
+// GetDRepID returns CIP-129 bech32 drep id (drep1...)
+func (w DRepWallet) GetDRepID() (string, error) {
+	credential, err := w.GetCredential()
+	if err != nil {
+		return "", err
+	}
+
+	return GetDRepID(credential)
+}
+
 type Key struct {
 	Type        string `json:"type"`
 	Description string `json:"description"`
@@ -1,13 +1,16 @@
 package core
 
 import (
+	"bytes"
 	"crypto/ed25519"
 	"crypto/rand"
+	"crypto/sha512"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 
+	"filippo.io/edwards25519"
 	"github.com/fxamacker/cbor/v2"
 	"github.com/igorcrevar/go-cardano-tx/core/bech32"
 	"golang.org/x/crypto/blake2b"
@@ -45,6 +48,52 @@ func VerifyWitness(txHash string, witness []byte) error {
 	return VerifyMessage(txHashBytes, vKey, signature)
 }
 
+// VerifyTxWitness verifies if transaction (witnessed or unwitnessed cbor) is signed by witness
+func VerifyTxWitness(txRaw []byte, witness []byte) error {
+	txHash, err := GetTxHash(txRaw)
+	if err != nil {
+		return err
+	}
+
+	return VerifyWitness(txHash, witness)
+}
+
+// GetTxBodyBytes returns original bytes of the transaction body from witnessed or unwitnessed transaction cbor
+func GetTxBodyBytes(txRaw []byte) ([]byte, error) {
+	var tx []cbor.RawMessage
+
+	if err := cbor.Unmarshal(txRaw, &tx); err != nil {
+		return nil, err
+	}
+
+	if len(tx) == 0 {
+		return nil, fmt.Errorf("%w: empty transaction cbor", ErrInvalidTransaction)
+	}
+
+	return tx[0], nil
+}
+
+// GetTxHash returns hash (blake2b-256 of the body bytes) of witnessed or unwitnessed transaction cbor
+func GetTxHash(txRaw []byte) (string, error) {
+	hash, err := getTxHashBytes(txRaw)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash), nil
+}
+
+func getTxHashBytes(txRaw []byte) ([]byte, error) {
+	bodyBytes, err := GetTxBodyBytes(txRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := blake2b.Sum256(bodyBytes)
+
+	return hash[:], nil
+}
+
 // SignMessage signs message
 func SignMessage(signingKey, verificationKey, message []byte) (result []byte, err error) {
 	defer func() {
@@ -53,6 +102,10 @@ func SignMessage(signingKey, verificationKey, message []byte) (result []byte, er
 		}
 	}()
 
+	if len(signingKey) == KeyExtendedSize {
+		return signExtendedMessage(signingKey, verificationKey, message), nil
+	}
+
 	privateKey := make([]byte, len(signingKey)+len(verificationKey))
 
 	copy(privateKey, signingKey)
@@ -63,6 +116,29 @@ func SignMessage(signingKey, verificationKey, message []byte) (result []byte, er
 	return
 }
 
+// signExtendedMessage signs message with bip32 ed25519 extended signing key (kL || kR || vkey || chain code)
+func signExtendedMessage(signingKey, verificationKey, message []byte) []byte {
+	// kL is already clamped scalar which is not reduced modulo l
+	kl, _ := new(edwards25519.Scalar).SetUniformBytes(append(bytes.Clone(signingKey[:32]), make([]byte, 32)...))
+
+	nonceHash := sha512.New()
+	nonceHash.Write(signingKey[32:64])
+	nonceHash.Write(message)
+
+	r, _ := new(edwards25519.Scalar).SetUniformBytes(nonceHash.Sum(nil))
+	encodedR := new(edwards25519.Point).ScalarBaseMult(r).Bytes()
+
+	challengeHash := sha512.New()
+	challengeHash.Write(encodedR)
+	challengeHash.Write(verificationKey)
+	challengeHash.Write(message)
+
+	k, _ := new(edwards25519.Scalar).SetUniformBytes(challengeHash.Sum(nil))
+	s := new(edwards25519.Scalar).MultiplyAdd(k, kl, r)
+
+	return append(encodedR, s.Bytes()...)
+}
+
 // VerifyMessage verifies message with verificationKey and signature
 func VerifyMessage(message, verificationKey, signature []byte) (err error) {
 	defer func() {
@@ -125,7 +201,7 @@ func PadKeyToSize(key []byte) []byte {
 
 // GetKeyBytes extracts the original key bytes from a given string. Supported formats:
 // - Hex + CBOR encoded string: Attempts to decode the key assuming it is hex-encoded,
-// - Bech32 encoded keys: Handles formats like addr_vk, addr_sk, stake_vk, stake_sk
+// - Bech32 encoded keys: Handles formats like addr_vk, addr_sk, stake_vk, stake_sk, drep_vk, drep_sk
 func GetKeyBytes(key string) (result []byte, err error) {
 	if bytes, err := hex.DecodeString(key); err == nil {
 		if err := cbor.Unmarshal(bytes, &result); err != nil {
@@ -1,12 +1,15 @@
 package core
 
 import (
+	"bytes"
+	"crypto/sha512"
 	"encoding/hex"
 	"encoding/json"
 	"os"
 	"strings"
 	"testing"
 
+	"filippo.io/edwards25519"
 	"github.com/fxamacker/cbor/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -45,6 +48,38 @@ func TestVerifyWitness(t *testing.T) {
 	assert.ErrorIs(t, VerifyWitness(txHash, dummyWitness), ErrInvalidSignature)
 }
 
+func TestGetTxHashAndVerifyTxWitness(t *testing.T) {
+	t.Parallel()
+
+	var (
+		// transaction, its witness created with cardano-cli and the same transaction with the witness
+		txRaw, _       = hex.DecodeString("84a500818258201f55818892cc447cbf9fc27e04899ea98795538889555d3846a8071f4fdb75eb01018282581d70c4aab1955b120811d634e3a1b282ea090537d9e753842e8f46c280041a00200b2082583900712c77c7e146b95a569f2f7edf1dd81df2545edecb132701f17f84d4694c18049dcafc175d262c06eac9f52b86f205e38e8bfca6e6a545611a055e8308021a0002e908031a0152a319075820cb1b53bb62ee65e8ae893d04331dcc70d745298a32fcedf5ff9cc7a12d8471e3a0f5d90103a100a101a5616466766563746f726266611a0010c8e06173837828616464725f74657374317170636a63613738753972746a6b6a6b6e756868616863616d71776c793478287a376d6d39337866637037396c636634726666737671663877326c73743436663376716d34766e61781c6674736d6571746375773330373264653439673473737a333437377a61746662726964676562747881a26161827828766563746f725f7465737431766772677868347333356135706476306463347a6771333363726e33781934656d6e6b326537766e656e73663474657a7133746b6d396d616d1a000f4240")
+		witness, _     = hex.DecodeString("825820c73cd59dbfba2e07577ad69621e964d404c7bef56f69e1691438abd37356199958408233a747b14fc78ba32fbe8501b842d3290c591a565f589dbeec1c1e8b3dfe27de19002784c6c7020871fd07a5dd70e1003b6d1449255985c823464123085a00")
+		txWitnessed, _ = hex.DecodeString("84a500818258201f55818892cc447cbf9fc27e04899ea98795538889555d3846a8071f4fdb75eb01018282581d70c4aab1955b120811d634e3a1b282ea090537d9e753842e8f46c280041a00200b2082583900712c77c7e146b95a569f2f7edf1dd81df2545edecb132701f17f84d4694c18049dcafc175d262c06eac9f52b86f205e38e8bfca6e6a545611a055e8308021a0002e908031a0152a319075820cb1b53bb62ee65e8ae893d04331dcc70d745298a32fcedf5ff9cc7a12d8471e3a10081825820c73cd59dbfba2e07577ad69621e964d404c7bef56f69e1691438abd37356199958408233a747b14fc78ba32fbe8501b842d3290c591a565f589dbeec1c1e8b3dfe27de19002784c6c7020871fd07a5dd70e1003b6d1449255985c823464123085a00f5d90103a100a101a5616466766563746f726266611a0010c8e06173837828616464725f74657374317170636a63613738753972746a6b6a6b6e756868616863616d71776c793478287a376d6d39337866637037396c636634726666737671663877326c73743436663376716d34766e61781c6674736d6571746375773330373264653439673473737a333437377a61746662726964676562747881a26161827828766563746f725f7465737431766772677868347333356135706476306463347a6771333363726e33781934656d6e6b326537766e656e73663474657a7133746b6d396d616d1a000f4240")
+	)
+
+	txHash, err := GetTxHash(txRaw)
+	require.NoError(t, err)
+
+	txHashWitnessed, err := GetTxHash(txWitnessed)
+	require.NoError(t, err)
+
+	require.Equal(t, txHash, txHashWitnessed)
+
+	bodyBytes, err := GetTxBodyBytes(txWitnessed)
+	require.NoError(t, err)
+
+	require.Equal(t, txRaw[1:len(bodyBytes)+1], bodyBytes)
+
+	require.NoError(t, VerifyWitness(txHash, witness))
+	require.NoError(t, VerifyTxWitness(txRaw, witness))
+	require.NoError(t, VerifyTxWitness(txWitnessed, witness))
+	require.ErrorIs(t, VerifyTxWitness(append([]byte{0x84, 0xa0}, txRaw[len(bodyBytes)+1:]...), witness), ErrInvalidSignature)
+
+	_, err = GetTxHash([]byte{0x01, 0x02})
+	require.Error(t, err)
+}
+
 func TestVerifyMessage(t *testing.T) {
 	t.Parallel()
 
@@ -195,3 +230,40 @@ func TestGetKeyBytes(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, k, KeyExtendedSize)
 }
+
+func TestSignMessage_ExtendedKey(t *testing.T) {
+	t.Parallel()
+
+	// kL is clamped the same way as bip32 ed25519 (icarus) root key is
+	keyHash := sha512.Sum512([]byte("extended key seed"))
+	kl, kr := keyHash[:32], keyHash[32:]
+
+	kl[0] &= 0xf8
+	kl[31] &= 0x1f
+	kl[31] |= 0x40
+
+	scalar, err := new(edwards25519.Scalar).SetBytesWithClamping(kl)
+	require.NoError(t, err)
+
+	verificationKey := new(edwards25519.Point).ScalarBaseMult(scalar).Bytes()
+	chainCode := bytes.Repeat([]byte{0x11}, 32)
+
+	signingKey := bytes.Join([][]byte{kl, kr, verificationKey, chainCode}, nil)
+	require.Len(t, signingKey, KeyExtendedSize)
+
+	message, err := hex.DecodeString("7e8b59e41d2ba71888272a14cff401268fa01dceb19014f5dda7763334b8f221")
+	require.NoError(t, err)
+
+	signature, err := SignMessage(signingKey, verificationKey, message)
+	require.NoError(t, err)
+	require.Len(t, signature, 64)
+
+	require.NoError(t, VerifyMessage(message, verificationKey, signature))
+	require.ErrorIs(t, VerifyMessage(append([]byte{0}, message[1:]...), verificationKey, signature), ErrInvalidSignature)
+
+	// wallet takes verification key from the extended signing key
+	witness, err := NewWallet(signingKey, nil).CreateTxWitness(message)
+	require.NoError(t, err)
+
+	require.NoError(t, VerifyWitness(hex.EncodeToString(message), witness))
+}
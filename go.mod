@@ -3,6 +3,7 @@ module github.com/igorcrevar/go-cardano-tx
 go 1.21
 
 require (
+	filippo.io/edwards25519 v1.1.0
 	github.com/akamensky/base58 v0.0.0-20210829145138-ce8bf8802e8f
 	github.com/fxamacker/cbor/v2 v2.6.0
 )
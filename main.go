@@ -151,24 +151,13 @@ func createMultiSigTx(
 ) ([]byte, string, error) {
 	policyScriptMultiSig := cardano.NewPolicyScript(getKeyHashes(signers), len(signers)*2/3+1)
 	policyScriptFeeMultiSig := cardano.NewPolicyScript(getKeyHashes(feeSigners), len(signers)*2/3+1)
-	cliUtils := cardano.NewCliUtils(cardanoCliBinary)
 
-	multisigPolicyID, err := cliUtils.GetPolicyID(policyScriptMultiSig)
+	multiSigAddr, err := cardano.NewPolicyScriptAddressFromScript(cardano.TestNetNetwork, policyScriptMultiSig)
 	if err != nil {
 		return nil, "", err
 	}
 
-	feeMultisigPolicyID, err := cliUtils.GetPolicyID(policyScriptFeeMultiSig)
-	if err != nil {
-		return nil, "", err
-	}
-
-	multiSigAddr, err := cardano.NewPolicyScriptAddress(cardano.TestNetNetwork, multisigPolicyID)
-	if err != nil {
-		return nil, "", err
-	}
-
-	multiSigFeeAddr, err := cardano.NewPolicyScriptAddress(cardano.TestNetNetwork, feeMultisigPolicyID)
+	multiSigFeeAddr, err := cardano.NewPolicyScriptAddressFromScript(cardano.TestNetNetwork, policyScriptFeeMultiSig)
 	if err != nil {
 		return nil, "", err
 	}